@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bytebase/gomongo"
 	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/bytebase/gomongo/types"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -33,12 +35,16 @@ func containsCollectionName(values []any, name string) bool {
 }
 
 // containsDatabaseName checks if the values contain the given database name.
-// Values are strings from show dbs.
+// Values are bson.D documents from show dbs.
 func containsDatabaseName(values []any, name string) bool {
 	for _, v := range values {
-		// show dbs returns strings
-		if s, ok := v.(string); ok && s == name {
-			return true
+		// show dbs returns documents with name, sizeOnDisk and empty
+		if doc, ok := v.(bson.D); ok {
+			for _, elem := range doc {
+				if elem.Key == "name" && elem.Value == name {
+					return true
+				}
+			}
 		}
 	}
 	return false
@@ -825,5 +831,115 @@ func TestLatencyStats(t *testing.T) {
 
 		row := valueToJSON(result.Value[0])
 		require.Contains(t, row, `"latencyStats"`)
+
+		// The result is capped like an aggregation.
+		result, err = gc.Execute(ctx, dbName, `db.users.latencyStats()`, gomongo.WithMaxBytes(1))
+		require.NoError(t, err)
+		require.Empty(t, result.Value)
+		require.True(t, result.Truncated)
+	})
+}
+
+func TestCurrentOp(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_current_op_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		tests := []struct {
+			name      string
+			statement string
+		}{
+			{"no filter", `db.currentOp()`},
+			{"all operations", `db.currentOp(true)`},
+			{"document filter", `db.currentOp({ active: true })`},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				result, err := gc.Execute(ctx, dbName, tc.statement)
+				require.NoError(t, err)
+				require.NotNil(t, result)
+				require.Equal(t, types.OpCurrentOp, result.Operation)
+				require.Equal(t, 1, len(result.Value))
+
+				row := valueToJSON(result.Value[0])
+				require.Contains(t, row, `"inprog"`)
+			})
+		}
+	})
+}
+
+func TestCurrentOpInvalidArgument(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_current_op_invalid_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		_, err := gc.Execute(ctx, dbName, `db.currentOp("active")`)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "currentOp() argument must be a document or boolean")
+	})
+}
+
+func TestKillOp(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_kill_op_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		// Killing an operation id that does not exist is acknowledged by the server
+		result, err := gc.Execute(ctx, dbName, `db.killOp(2147483000)`)
+		require.NoError(t, err)
+		require.Equal(t, types.OpKillOp, result.Operation)
+		require.Equal(t, 1, len(result.Value))
+
+		row := valueToJSON(result.Value[0])
+		require.Contains(t, row, `"ok"`)
+
+		_, err = gc.Execute(ctx, dbName, `db.killOp()`)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "killOp() requires an operation id")
+
+		_, err = gc.Execute(ctx, dbName, `db.killOp(1.5)`)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "killOp() operation id must be an integer")
+	})
+}
+
+func TestKillOnCancel(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_kill_on_cancel_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+
+		_, err := db.Client.Database(dbName).Collection("slow").InsertOne(ctx, bson.M{"x": 1})
+		require.NoError(t, err)
+
+		gc := gomongo.NewClient(db.Client)
+
+		cancelCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+		defer cancel()
+
+		_, err = gc.Execute(cancelCtx, dbName, `db.slow.find({ $where: "sleep(5000) || true" })`, gomongo.WithKillOnCancel())
+		require.Error(t, err)
+
+		// The server-side operation must have been killed rather than left running
+		result, err := gc.Execute(ctx, dbName, fmt.Sprintf(`db.currentOp({ ns: "%s.slow", "command.filter.$where": { $exists: true } })`, dbName))
+		require.NoError(t, err)
+		doc, ok := result.Value[0].(bson.D)
+		require.True(t, ok)
+		for _, elem := range doc {
+			if elem.Key == "inprog" {
+				require.Empty(t, elem.Value)
+			}
+		}
 	})
 }
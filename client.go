@@ -2,19 +2,60 @@ package gomongo
 
 import (
 	"context"
+	"time"
 
 	"github.com/bytebase/gomongo/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 // Client wraps a MongoDB client and provides query execution.
 type Client struct {
-	client *mongo.Client
+	client        *mongo.Client
+	securityAdmin bool
+	noJavaScript  bool
+	extendedJSON  bool
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithSecurityAdmin enables user and role administration commands such as
+// db.createUser(), db.dropUser() and db.changeUserPassword(). Without this
+// option they are rejected with a SecurityAdminRequiredError.
+func WithSecurityAdmin() ClientOption {
+	return func(c *Client) {
+		c.securityAdmin = true
+	}
+}
+
+// WithoutServerSideJavaScript rejects statements that run JavaScript on the
+// server, through the $where, $function or $accumulator operators, with a
+// ServerSideJavaScriptError. Placeholder values are checked as well.
+func WithoutServerSideJavaScript() ClientOption {
+	return func(c *Client) {
+		c.noJavaScript = true
+	}
+}
+
+// WithExtendedJSONInput converts Extended JSON v2 wrapper documents in
+// statements, such as { "$oid": "..." } and { "$date": "..." }, to the values
+// they represent, so documents copied from Compass or mongoexport can be used
+// as written. Without this option they are sent as documents, as mongosh
+// sends them.
+func WithExtendedJSONInput() ClientOption {
+	return func(c *Client) {
+		c.extendedJSON = true
+	}
 }
 
 // NewClient creates a new gomongo client from an existing MongoDB client.
-func NewClient(client *mongo.Client) *Client {
-	return &Client{client: client}
+func NewClient(client *mongo.Client, opts ...ClientOption) *Client {
+	c := &Client{client: client}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Result represents query execution results.
@@ -25,8 +66,10 @@ func NewClient(client *mongo.Client) *Client {
 //   - OpFindOne, OpFindOneAndUpdate, OpFindOneAndReplace, OpFindOneAndDelete: 0 or 1 element of bson.D
 //   - OpCountDocuments, OpEstimatedDocumentCount: single element of int64
 //   - OpDistinct: elements are the distinct values (various types)
-//   - OpShowDatabases, OpShowCollections, OpGetCollectionNames: each element is string
+//   - OpShowDatabases: each element is bson.D with name, sizeOnDisk and empty
+//   - OpShowCollections, OpGetCollectionNames: each element is string
 //   - OpInsertOne, OpInsertMany, OpUpdateOne, OpUpdateMany, OpReplaceOne, OpDeleteOne, OpDeleteMany: single bson.D with operation result
+//     (see InsertOneResult, InsertManyResult, UpdateResult and DeleteResult for typed access)
 //   - OpCreateIndex: single element of string (index name)
 //   - OpCreateIndexes: each element is string (index name)
 //   - OpDropIndex, OpDropIndexes, OpCreateCollection, OpDropDatabase, OpRenameCollection: single bson.D with {ok: 1}
@@ -37,35 +80,135 @@ func NewClient(client *mongo.Client) *Client {
 //   - OpTotalSize: single int64 (storageSize + totalIndexSize)
 //   - OpIsCapped: single element of bool
 //   - OpLatencyStats: each element is bson.D (aggregation result)
+//   - OpCurrentOp, OpKillOp: single bson.D (command result)
+//   - OpSetProfilingLevel, OpGetProfilingStatus: single bson.D (previous/current profiler settings)
+//   - OpGetProfilingLevel: single numeric value (profiling level)
+//   - OpShowProfile: each element is bson.D (system.profile entry, most recent first)
+//   - OpShowUsers, OpShowRoles, OpGetUsers, OpGetRoles: each element is bson.D (user or role document)
+//   - OpGetUser, OpGetRole: 0 or 1 element of bson.D
+//   - OpCreateUser, OpUpdateUser, OpDropUser, OpGrantRolesToUser, OpRevokeRolesFromUser,
+//     OpCreateRole, OpGrantPrivilegesToRole, OpChangeUserPassword: single bson.D with {ok: 1}
+//   - OpShowLog: each element is string (log line)
+//   - OpShowLogs: each element is string (log name)
+//   - OpRsStatus, OpRsConf: single bson.D (replica set status or configuration)
+//   - OpRsPrintReplicationInfo: single bson.D (oplog size and time window)
+//   - OpRsPrintSecondaryReplicationInfo: each element is bson.D (secondary member and its lag)
+//   - OpShStatus, OpGetShardDistribution: single bson.D (sharding report)
+//
+// The remaining fields describe how the statement was executed. RoundTripTime,
+// ServerAddress, Batches and ReadPreference are only set when the MongoDB client
+// was created with the monitor from NewCommandMonitor.
 type Result struct {
 	Operation types.OperationType
 	Value     []any
+
+	// Truncated reports that WithMaxRows or WithMaxBytes cut the result short:
+	// the statement found more values than were returned, or countDocuments()
+	// counted more.
+	Truncated bool
+
+	// TruncatedValues counts the string and binary values shortened by
+	// WithMaxValueBytes.
+	TruncatedValues int
+
+	// Warnings lists the parts of the statement that were not executed as
+	// written, such as options that have no effect.
+	Warnings []Warning
+
+	Duration       time.Duration // wall-clock time from the Execute call until the result was returned
+	RoundTripTime  time.Duration // total round-trip time of the commands sent to the server, as measured by the driver, including network time
+	ServerAddress  string        // host:port of the server that ran the first command
+	Batches        int           // cursor batches fetched, including the first
+	ReadPreference string        // $readPreference mode the driver sent, such as "secondaryPreferred"; empty if it sent none
 }
 
 // executeConfig holds configuration for Execute.
 type executeConfig struct {
-	maxRows *int64
+	maxRows       *int64
+	maxBytes      *int64
+	maxValueBytes int
+	killOnCancel  bool
+	securityAdmin bool
+	noJavaScript  bool
+	extendedJSON  bool
+	params        []any
 }
 
 // ExecuteOption configures Execute behavior.
 type ExecuteOption func(*executeConfig)
 
-// WithMaxRows limits the maximum number of rows returned by find() and
-// countDocuments() operations. If the query includes .limit(N), the effective
-// limit is min(N, maxRows). Aggregate operations are not affected.
+// WithMaxRows limits the maximum number of rows returned by queries, such as
+// find() and aggregate(), list operations, such as distinct() and show
+// collections, and the count of countDocuments(). If the query includes
+// .limit(N), the effective limit is min(N, maxRows). Aggregation pipelines that
+// end in $out or $merge are not limited.
 func WithMaxRows(n int64) ExecuteOption {
 	return func(c *executeConfig) {
 		c.maxRows = &n
 	}
 }
 
+// WithMaxBytes limits the total raw BSON size of the documents and values
+// returned by queries and list operations to n bytes. find(), aggregate(),
+// getIndexes() and getCollectionInfos() stop reading their cursor at the first
+// document that would exceed the budget. Result.Truncated reports a result cut
+// short this way.
+func WithMaxBytes(n int64) ExecuteOption {
+	return func(c *executeConfig) {
+		c.maxBytes = &n
+	}
+}
+
+// WithMaxValueBytes shortens string and binary values longer than n bytes, at
+// any depth, for display. Strings are cut at a character boundary and end in
+// "…", which counts toward the n bytes. Result.TruncatedValues counts the
+// values shortened.
+func WithMaxValueBytes(n int) ExecuteOption {
+	return func(c *executeConfig) {
+		c.maxValueBytes = n
+	}
+}
+
+// WithKillOnCancel tags the operation with a unique comment and, if ctx is
+// cancelled before the operation completes, issues killOp for the matching
+// server-side operation so it is aborted rather than left running.
+// Operations that already specify a comment are not tagged.
+func WithKillOnCancel() ExecuteOption {
+	return func(c *executeConfig) {
+		c.killOnCancel = true
+	}
+}
+
 // Execute parses and executes a MongoDB shell statement.
 // Returns a Result containing the operation type and native Go values.
 // Use Result.Operation to determine the expected type of elements in Result.Value.
+//
+// A write that is only partly applied returns both a Result and a *ServerError.
+// For example, an unordered insertMany() in which some documents are rejected
+// returns the IDs of the inserted documents along with an error listing each
+// rejected document, and an update whose write concern fails returns its counts
+// along with an error whose WriteConcernError is set.
 func (c *Client) Execute(ctx context.Context, database, statement string, opts ...ExecuteOption) (*Result, error) {
-	cfg := &executeConfig{}
+	cfg := &executeConfig{securityAdmin: c.securityAdmin, noJavaScript: c.noJavaScript, extendedJSON: c.extendedJSON}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return execute(ctx, c.client, database, statement, cfg)
+}
+
+// Stream parses and executes a MongoDB shell statement, calling fn for each
+// document it returns instead of collecting them in a Result.
+//
+// find(), aggregate() and getIndexes() read the cursor one batch at a time, so
+// memory use does not grow with the number of documents. Other statements that
+// return documents, such as getCollectionInfos(), are executed as usual and their
+// documents passed to fn in turn; statements that return other values, such as
+// countDocuments(), return an error. Returning an error from fn stops the
+// iteration, closes the cursor and returns that error.
+func (c *Client) Stream(ctx context.Context, database, statement string, fn func(bson.D) error, opts ...ExecuteOption) error {
+	cfg := &executeConfig{securityAdmin: c.securityAdmin, noJavaScript: c.noJavaScript, extendedJSON: c.extendedJSON}
 	for _, opt := range opts {
 		opt(cfg)
 	}
-	return execute(ctx, c.client, database, statement, cfg.maxRows)
+	return stream(ctx, c.client, database, statement, cfg, fn)
 }
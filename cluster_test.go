@@ -0,0 +1,172 @@
+package gomongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestRsStatus(t *testing.T) {
+	client := testutil.GetReplicaSetClient(t)
+	gc := gomongo.NewClient(client)
+	ctx := context.Background()
+
+	result, err := gc.Execute(ctx, "admin", `rs.status()`)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Value))
+	row := valueToJSON(result.Value[0])
+	require.Contains(t, row, `"set": "rs0"`)
+	require.Contains(t, row, `"stateStr": "PRIMARY"`)
+}
+
+func TestRsConf(t *testing.T) {
+	client := testutil.GetReplicaSetClient(t)
+	gc := gomongo.NewClient(client)
+	ctx := context.Background()
+
+	for _, statement := range []string{`rs.conf()`, `rs.config()`} {
+		result, err := gc.Execute(ctx, "admin", statement)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+		conf, ok := result.Value[0].(bson.D)
+		require.True(t, ok)
+
+		var members bson.A
+		for _, elem := range conf {
+			if elem.Key == "_id" {
+				require.Equal(t, "rs0", elem.Value)
+			}
+			if elem.Key == "members" {
+				members, _ = elem.Value.(bson.A)
+			}
+		}
+		require.Equal(t, 1, len(members))
+	}
+}
+
+func TestRsPrintReplicationInfo(t *testing.T) {
+	client := testutil.GetReplicaSetClient(t)
+	gc := gomongo.NewClient(client)
+	ctx := context.Background()
+
+	result, err := gc.Execute(ctx, "admin", `rs.printReplicationInfo()`)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Value))
+	info, ok := result.Value[0].(bson.D)
+	require.True(t, ok)
+
+	keys := make([]string, len(info))
+	for i, elem := range info {
+		keys[i] = elem.Key
+		if elem.Key == "logSizeMB" {
+			require.Greater(t, elem.Value.(float64), 0.0)
+		}
+	}
+	require.Equal(t, []string{"logSizeMB", "usedMB", "timeDiff", "timeDiffHours", "tFirst", "tLast", "now"}, keys)
+}
+
+func TestRsPrintSecondaryReplicationInfo(t *testing.T) {
+	client := testutil.GetReplicaSetClient(t)
+	gc := gomongo.NewClient(client)
+	ctx := context.Background()
+
+	// A single-node replica set has no secondaries.
+	result, err := gc.Execute(ctx, "admin", `rs.printSecondaryReplicationInfo()`)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(result.Value))
+}
+
+func TestRsUnsupportedMethods(t *testing.T) {
+	client := testutil.GetReplicaSetClient(t)
+	gc := gomongo.NewClient(client)
+	ctx := context.Background()
+
+	for _, statement := range []string{`rs.initiate()`, `rs.reconfig({})`, `sh.addShard("shard1/host:27017")`} {
+		_, err := gc.Execute(ctx, "admin", statement)
+		var unsupportedErr *gomongo.UnsupportedOperationError
+		require.ErrorAs(t, err, &unsupportedErr, statement)
+	}
+
+	_, err := gc.Execute(ctx, "admin", `rs.status(1)`)
+	require.ErrorContains(t, err, "rs.status() takes no arguments")
+}
+
+func TestShStatus(t *testing.T) {
+	client := testutil.GetShardedClusterClient(t)
+	gc := gomongo.NewClient(client)
+	ctx := context.Background()
+	dbName := "testdb_sh_status"
+	defer testutil.CleanupDatabase(t, client, dbName)
+
+	admin := client.Database("admin")
+	require.NoError(t, admin.RunCommand(ctx, bson.D{{Key: "enableSharding", Value: dbName}}).Err())
+	require.NoError(t, admin.RunCommand(ctx, bson.D{
+		{Key: "shardCollection", Value: dbName + ".orders"},
+		{Key: "key", Value: bson.D{{Key: "customerId", Value: "hashed"}}},
+	}).Err())
+
+	result, err := gc.Execute(ctx, dbName, `sh.status()`)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Value))
+	row := valueToJSON(result.Value[0])
+	require.Contains(t, row, `"shardingVersion"`)
+	require.Contains(t, row, `"_id": "shard0"`)
+	require.Contains(t, row, `"activeMongoses"`)
+	require.Contains(t, row, `"currentlyEnabled"`)
+	require.Contains(t, row, `"collection": "testdb_sh_status.orders"`)
+	require.Contains(t, row, `"customerId": "hashed"`)
+	require.Contains(t, row, `"shard": "shard0"`)
+}
+
+func TestGetShardDistribution(t *testing.T) {
+	client := testutil.GetShardedClusterClient(t)
+	gc := gomongo.NewClient(client)
+	ctx := context.Background()
+	dbName := "testdb_shard_distribution"
+	defer testutil.CleanupDatabase(t, client, dbName)
+
+	admin := client.Database("admin")
+	require.NoError(t, admin.RunCommand(ctx, bson.D{{Key: "enableSharding", Value: dbName}}).Err())
+	require.NoError(t, admin.RunCommand(ctx, bson.D{
+		{Key: "shardCollection", Value: dbName + ".events"},
+		{Key: "key", Value: bson.D{{Key: "_id", Value: 1}}},
+	}).Err())
+
+	docs := make([]any, 10)
+	for i := range docs {
+		docs[i] = bson.D{{Key: "_id", Value: i}, {Key: "kind", Value: "click"}}
+	}
+	_, err := client.Database(dbName).Collection("events").InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	result, err := gc.Execute(ctx, dbName, `db.events.getShardDistribution()`)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Value))
+	row := valueToJSON(result.Value[0])
+	require.Contains(t, row, `"shard": "shard0"`)
+	require.Contains(t, row, `"docs": 10`)
+	require.Contains(t, row, `"docsPercent": 100`)
+	require.Contains(t, row, `"totals"`)
+
+	// Unsharded collections are reported as such.
+	_, err = client.Database(dbName).Collection("plain").InsertOne(ctx, bson.D{{Key: "x", Value: 1}})
+	require.NoError(t, err)
+	_, err = gc.Execute(ctx, dbName, `db.plain.getShardDistribution()`)
+	require.ErrorContains(t, err, "is not sharded")
+}
+
+func TestShardingRequiresMongos(t *testing.T) {
+	client := testutil.GetReplicaSetClient(t)
+	gc := gomongo.NewClient(client)
+	ctx := context.Background()
+
+	_, err := gc.Execute(ctx, "test", `sh.status()`)
+	require.ErrorContains(t, err, "must be run against a mongos")
+
+	_, err = gc.Execute(ctx, "test", `db.events.getShardDistribution()`)
+	require.ErrorContains(t, err, "must be run against a mongos")
+}
@@ -39,12 +39,18 @@ func TestShowDatabases(t *testing.T) {
 				require.NotNil(t, result)
 				require.GreaterOrEqual(t, len(result.Value), 1)
 
-				// Check that dbName is in the result (values are strings)
+				// Check that dbName is in the result (values are documents with name and size)
 				found := false
 				for _, v := range result.Value {
-					if name, ok := v.(string); ok && name == dbName {
+					doc, ok := v.(bson.D)
+					require.True(t, ok, "expected bson.D, got %T", v)
+					require.Equal(t, "name", doc[0].Key)
+					require.Equal(t, "sizeOnDisk", doc[1].Key)
+					require.Equal(t, "empty", doc[2].Key)
+					if doc[0].Value == dbName {
 						found = true
-						break
+						_, isInt64 := doc[1].Value.(int64)
+						require.True(t, isInt64, "expected int64 sizeOnDisk")
 					}
 				}
 				require.True(t, found, "expected database '%s' in result", dbName)
@@ -301,3 +307,219 @@ func TestCollectionAccessPatterns(t *testing.T) {
 		}
 	})
 }
+
+func TestProfilingLevel(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_profiling_level_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+
+		_, err := db.Client.Database(dbName).Collection("test").InsertOne(ctx, bson.M{"x": 1})
+		require.NoError(t, err)
+
+		gc := gomongo.NewClient(db.Client)
+
+		// setProfilingLevel returns the previous settings
+		result, err := gc.Execute(ctx, dbName, `db.setProfilingLevel(1, { slowms: 50, sampleRate: 0.5 })`)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+		row := valueToJSON(result.Value[0])
+		require.Contains(t, row, `"was"`)
+
+		result, err = gc.Execute(ctx, dbName, `db.getProfilingLevel()`)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+		require.EqualValues(t, 1, result.Value[0])
+
+		result, err = gc.Execute(ctx, dbName, `db.getProfilingStatus()`)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+		row = valueToJSON(result.Value[0])
+		require.Contains(t, row, `"slowms": 50`)
+		require.Contains(t, row, `"sampleRate": 0.5`)
+
+		_, err = gc.Execute(ctx, dbName, `db.setProfilingLevel(0)`)
+		require.NoError(t, err)
+
+		result, err = gc.Execute(ctx, dbName, `db.getProfilingLevel()`)
+		require.NoError(t, err)
+		require.EqualValues(t, 0, result.Value[0])
+	})
+}
+
+func TestSetProfilingLevelInvalid(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_profiling_invalid_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		tests := []struct {
+			name      string
+			statement string
+			errMsg    string
+		}{
+			{"missing level", `db.setProfilingLevel()`, "requires a profiling level"},
+			{"level out of range", `db.setProfilingLevel(3)`, "out of range [0..2]"},
+			{"invalid slowms", `db.setProfilingLevel(1, { slowms: "fast" })`, "slowms must be a number"},
+			{"slowms out of range", `db.setProfilingLevel(1, 1e12)`, "slowms 1e+12 is out of range"},
+			{"invalid sampleRate", `db.setProfilingLevel(1, { sampleRate: "all" })`, "sampleRate must be a number"},
+			{"sampleRate out of range", `db.setProfilingLevel(1, { sampleRate: 2 })`, "sampleRate must be between 0 and 1"},
+			{"invalid filter", `db.setProfilingLevel(1, { filter: 1 })`, "filter must be a document"},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := gc.Execute(ctx, dbName, tc.statement)
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+
+		_, err := gc.Execute(ctx, dbName, `db.setProfilingLevel(1, { unknown: 1 })`)
+		var optErr *gomongo.UnsupportedOptionError
+		require.ErrorAs(t, err, &optErr)
+		require.Equal(t, "setProfilingLevel()", optErr.Method)
+	})
+}
+
+func TestShowProfile(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_show_profile_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+
+		_, err := db.Client.Database(dbName).Collection("test").InsertOne(ctx, bson.M{"x": 1})
+		require.NoError(t, err)
+
+		gc := gomongo.NewClient(db.Client)
+
+		// Empty profile before profiling is enabled
+		result, err := gc.Execute(ctx, dbName, `show profile`)
+		require.NoError(t, err)
+		require.Empty(t, result.Value)
+
+		_, err = gc.Execute(ctx, dbName, `db.setProfilingLevel(2)`)
+		require.NoError(t, err)
+		defer func() { _, _ = gc.Execute(ctx, dbName, `db.setProfilingLevel(0)`) }()
+
+		// Run a query slow enough to be recorded with millis > 0
+		_, err = gc.Execute(ctx, dbName, `db.test.find({ $where: "sleep(20) || true" })`)
+		require.NoError(t, err)
+
+		result, err = gc.Execute(ctx, dbName, `show profile`)
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Value)
+		require.LessOrEqual(t, len(result.Value), 5)
+
+		row := valueToJSON(result.Value[0])
+		require.Contains(t, row, `"millis"`)
+		require.Contains(t, row, `"ns"`)
+	})
+}
+
+func TestShowUsers(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_show_users_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+
+		err := db.Client.Database(dbName).RunCommand(ctx, bson.D{
+			{Key: "createUser", Value: "reporter"},
+			{Key: "pwd", Value: "secret"},
+			{Key: "roles", Value: bson.A{"read"}},
+		}).Err()
+		require.NoError(t, err)
+		defer func() {
+			_ = db.Client.Database(dbName).RunCommand(ctx, bson.D{{Key: "dropUser", Value: "reporter"}}).Err()
+		}()
+
+		gc := gomongo.NewClient(db.Client)
+
+		result, err := gc.Execute(ctx, dbName, `show users`)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+
+		row := valueToJSON(result.Value[0])
+		require.Contains(t, row, `"user": "reporter"`)
+		require.Contains(t, row, `"roles"`)
+	})
+}
+
+func TestShowRoles(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_show_roles_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		// Built-in roles are always listed
+		result, err := gc.Execute(ctx, dbName, `show roles`)
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Value)
+
+		found := false
+		for _, v := range result.Value {
+			doc, ok := v.(bson.D)
+			require.True(t, ok)
+			for _, elem := range doc {
+				if elem.Key == "role" && elem.Value == "read" {
+					found = true
+				}
+			}
+		}
+		require.True(t, found, "expected built-in 'read' role")
+	})
+}
+
+func TestShowLog(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_show_log_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		tests := []struct {
+			name      string
+			statement string
+		}{
+			{"default log", `show log`},
+			{"named log", `show log global`},
+			{"named log with semicolon", `show log global;`},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				result, err := gc.Execute(ctx, dbName, tc.statement)
+				require.NoError(t, err)
+				require.NotEmpty(t, result.Value)
+				_, ok := result.Value[0].(string)
+				require.True(t, ok, "expected string log line, got %T", result.Value[0])
+			})
+		}
+
+		_, err := gc.Execute(ctx, dbName, `show log nosuchlog`)
+		require.Error(t, err)
+	})
+}
+
+func TestShowLogs(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_show_logs_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		result, err := gc.Execute(ctx, dbName, `show logs`)
+		require.NoError(t, err)
+		require.Contains(t, result.Value, "global")
+		require.Contains(t, result.Value, "startupWarnings")
+	})
+}
@@ -2,11 +2,13 @@ package gomongo_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/bytebase/gomongo"
 	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/bytebase/gomongo/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -144,3 +146,114 @@ func TestEstimatedDocumentCountUnsupportedOption(t *testing.T) {
 		require.Equal(t, "comment", optErr.Option)
 	})
 }
+
+func TestServerErrorWriteErrors(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_server_err_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		_, err := gc.Execute(ctx, dbName, `db.users.insertOne({ _id: 1 })`)
+		require.NoError(t, err)
+
+		_, err = gc.Execute(ctx, dbName, `db.users.insertMany([{ _id: 2 }, { _id: 1 }, { _id: 3 }, { _id: 2 }], { ordered: false })`)
+		var serverErr *gomongo.ServerError
+		require.ErrorAs(t, err, &serverErr)
+		require.Equal(t, 11000, serverErr.Code)
+		require.Equal(t, "DuplicateKey", serverErr.CodeName)
+		require.Equal(t, types.OpInsertMany, serverErr.Operation)
+		require.Equal(t, "users", serverErr.Collection)
+		require.Equal(t, 2, len(serverErr.WriteErrors))
+		require.Equal(t, 1, serverErr.WriteErrors[0].Index)
+		require.Equal(t, 3, serverErr.WriteErrors[1].Index)
+		require.Equal(t, "DuplicateKey", serverErr.WriteErrors[1].CodeName)
+		require.Contains(t, serverErr.WriteErrors[1].Message, "duplicate key")
+
+		// Single-document writes report the failing document at index 0.
+		_, err = gc.Execute(ctx, dbName, `db.users.insertOne({ _id: 1 })`)
+		require.ErrorAs(t, err, &serverErr)
+		require.Equal(t, types.OpInsertOne, serverErr.Operation)
+		require.Equal(t, 0, serverErr.WriteErrors[0].Index)
+	})
+}
+
+func TestServerErrorCommand(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_server_cmd_err_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		_, err := gc.Execute(ctx, dbName, `db.createCollection("users")`)
+		require.NoError(t, err)
+		_, err = gc.Execute(ctx, dbName, `db.createCollection("users")`)
+		var serverErr *gomongo.ServerError
+		require.ErrorAs(t, err, &serverErr)
+		require.Equal(t, 48, serverErr.Code)
+		require.Equal(t, "NamespaceExists", serverErr.CodeName)
+		require.Equal(t, types.OpCreateCollection, serverErr.Operation)
+		require.Empty(t, serverErr.WriteErrors)
+		require.False(t, serverErr.HasErrorLabel("RetryableWriteError"))
+
+		// Errors that did not come from the server are returned unchanged.
+		_, err = gc.Execute(ctx, dbName, `db.users.find({ name: })`)
+		require.False(t, errors.As(err, &serverErr))
+	})
+}
+
+func TestSemanticError(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_semantic_err_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		statement := "db.users.find(\n  { age: 1 },\n  5\n)"
+		_, err := gc.Execute(ctx, dbName, statement)
+		var semanticErr *gomongo.SemanticError
+		require.ErrorAs(t, err, &semanticErr)
+		require.Equal(t, "find() projection must be a document", semanticErr.Error())
+		require.Equal(t, "find()", semanticErr.Method)
+		require.Equal(t, 1, semanticErr.Arg)
+		require.Equal(t, 3, semanticErr.Line)
+		require.Equal(t, 3, semanticErr.Column)
+		require.Equal(t, "5", statement[semanticErr.Start:semanticErr.End])
+
+		// Prepared statements report errors in bound values the same way.
+		stmt, err := gc.Prepare(`db.users.find({ age: $1 })`)
+		require.NoError(t, err)
+		_, err = stmt.Execute(ctx, dbName)
+		require.ErrorAs(t, err, &semanticErr)
+		require.Equal(t, 0, semanticErr.Arg)
+		require.Equal(t, 22, semanticErr.Column)
+	})
+}
+
+func TestFormatError(t *testing.T) {
+	statement := "db.users.find(\n\t{ age: 1 },\n\t{ name: ObjectId(\"xyz\") }\n)"
+	err := &gomongo.SemanticError{
+		Message: "invalid ObjectId",
+		Method:  "find()",
+		Arg:     1,
+		Line:    3,
+		Column:  10,
+		Start:   37,
+		End:     52,
+	}
+	require.Equal(t, "invalid ObjectId\n"+
+		"\t{ name: ObjectId(\"xyz\") }\n"+
+		"\t        ^~~~~~~~~~~~~~~", gomongo.FormatError(statement, err))
+
+	// A parse error is underlined for the length of the token found.
+	parseErr := &gomongo.ParseError{Line: 1, Column: 21, Message: `expected :, got "10"`, Found: "10", Expected: ":"}
+	require.Equal(t, parseErr.Error()+"\n"+
+		"db.users.find({ age 10 })\n"+
+		"                    ^~", gomongo.FormatError("db.users.find({ age 10 })", parseErr))
+
+	// Errors without a position are returned as they are.
+	require.Equal(t, "boom", gomongo.FormatError(statement, errors.New("boom")))
+}
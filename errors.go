@@ -1,6 +1,15 @@
 package gomongo
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bytebase/gomongo/types"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
 
 // ParseError represents a syntax error during parsing.
 type ParseError struct {
@@ -47,3 +56,222 @@ type UnsupportedOptionError struct {
 func (e *UnsupportedOptionError) Error() string {
 	return fmt.Sprintf("unsupported option '%s' in %s", e.Option, e.Method)
 }
+
+// SemanticError represents a statement that parses but cannot be translated,
+// such as a method argument of the wrong type or a malformed ObjectId(). It
+// locates the offending source text, usually an argument, an option value or a
+// nested value, so an editor can underline it. Errors that no single value
+// causes, such as a missing argument, are located at the method call.
+type SemanticError struct {
+	Message string
+	Method  string // method the error was found in, such as "find()"; empty outside a method call
+	Arg     int    // index of the method argument that contains the error, or -1
+	Line    int    // 1-based line of Start
+	Column  int    // 1-based column of Start, in bytes
+	Start   int    // byte offset of the offending source text in the statement
+	End     int    // byte offset just past the offending source text
+}
+
+func (e *SemanticError) Error() string {
+	return e.Message
+}
+
+// SecurityAdminRequiredError represents a user or role administration command
+// executed by a client that was not created with WithSecurityAdmin().
+type SecurityAdminRequiredError struct {
+	Operation string
+}
+
+func (e *SecurityAdminRequiredError) Error() string {
+	return fmt.Sprintf("operation %s requires a client created with WithSecurityAdmin()", e.Operation)
+}
+
+// ServerSideJavaScriptError represents a statement that runs JavaScript on the
+// server, executed by a client created with WithoutServerSideJavaScript().
+type ServerSideJavaScriptError struct {
+	Operator string // such as "$where"
+}
+
+func (e *ServerSideJavaScriptError) Error() string {
+	return fmt.Sprintf("%s runs JavaScript on the server, which is disabled by WithoutServerSideJavaScript()", e.Operator)
+}
+
+// ServerError represents an error reported by the MongoDB server while executing
+// a statement. It unwraps to the underlying driver error.
+type ServerError struct {
+	Code        int                 // server error code, such as 11000
+	CodeName    string              // server error code name, such as "DuplicateKey"; empty if unknown
+	Labels      []string            // error labels, such as "TransientTransactionError" or "RetryableWriteError"
+	Operation   types.OperationType // operation that failed
+	Collection  string              // collection the operation ran against; empty for database and server commands
+	WriteErrors []WriteError        // per-document write errors, in index order
+
+	// WriteConcernError is set when the write was applied but could not be
+	// confirmed at the requested write concern.
+	WriteConcernError *WriteConcernError
+
+	err error
+}
+
+func (e *ServerError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ServerError) Unwrap() error {
+	return e.err
+}
+
+// HasErrorLabel reports whether the error carries the given label.
+func (e *ServerError) HasErrorLabel(label string) bool {
+	return slices.Contains(e.Labels, label)
+}
+
+// WriteError describes a single document that the server failed to write.
+type WriteError struct {
+	Index    int    // position of the document in the statement, such as the insertMany() array index
+	Code     int    // server error code
+	CodeName string // server error code name; empty if unknown
+	Message  string
+}
+
+// WriteConcernError describes a write that was applied but not acknowledged at
+// the requested write concern, for example because too few replica set members
+// confirmed it in time.
+type WriteConcernError struct {
+	Code     int
+	CodeName string
+	Message  string
+}
+
+// writeErrorCodeNames maps common write error codes to their server code names,
+// which the server does not include in write errors.
+var writeErrorCodeNames = map[int]string{
+	2:     "BadValue",
+	14:    "TypeMismatch",
+	50:    "MaxTimeMSExpired",
+	52:    "DollarPrefixedFieldName",
+	55:    "InvalidDBRef",
+	56:    "EmptyFieldName",
+	57:    "DottedFieldName",
+	66:    "ImmutableField",
+	121:   "DocumentValidationFailure",
+	11000: "DuplicateKey",
+	17280: "KeyTooLong",
+}
+
+// newServerError converts an error that carries a server error to a *ServerError,
+// and returns other errors unchanged.
+func newServerError(err error, opType types.OperationType, collection string) error {
+	serverErr := &ServerError{Operation: opType, Collection: collection, err: err}
+
+	var cmdErr mongo.CommandError
+	var writeErr mongo.WriteException
+	var bulkErr mongo.BulkWriteException
+	switch {
+	case errors.As(err, &bulkErr):
+		serverErr.Labels = bulkErr.Labels
+		for _, we := range bulkErr.WriteErrors {
+			serverErr.WriteErrors = append(serverErr.WriteErrors, convertWriteError(we.WriteError))
+		}
+		serverErr.setCode(bulkErr.WriteConcernError)
+	case errors.As(err, &writeErr):
+		serverErr.Labels = writeErr.Labels
+		for _, we := range writeErr.WriteErrors {
+			serverErr.WriteErrors = append(serverErr.WriteErrors, convertWriteError(we))
+		}
+		serverErr.setCode(writeErr.WriteConcernError)
+	case errors.As(err, &cmdErr):
+		if cmdErr.Code == 0 && cmdErr.Name == "" {
+			// A network or client-side error wrapped by the driver.
+			return err
+		}
+		serverErr.Code = int(cmdErr.Code)
+		serverErr.CodeName = cmdErr.Name
+		serverErr.Labels = cmdErr.Labels
+	default:
+		return err
+	}
+	return serverErr
+}
+
+// setCode records the write concern error and sets the error code from the
+// first write error, or from the write concern error if no document failed.
+func (e *ServerError) setCode(wcErr *mongo.WriteConcernError) {
+	if wcErr != nil {
+		e.WriteConcernError = &WriteConcernError{Code: wcErr.Code, CodeName: wcErr.Name, Message: wcErr.Message}
+	}
+	switch {
+	case len(e.WriteErrors) > 0:
+		e.Code = e.WriteErrors[0].Code
+		e.CodeName = e.WriteErrors[0].CodeName
+	case wcErr != nil:
+		e.Code = wcErr.Code
+		e.CodeName = wcErr.Name
+	}
+}
+
+// convertWriteError converts a driver write error.
+func convertWriteError(we mongo.WriteError) WriteError {
+	return WriteError{
+		Index:    we.Index,
+		Code:     we.Code,
+		CodeName: writeErrorCodeNames[we.Code],
+		Message:  we.Message,
+	}
+}
+
+// FormatError formats an error returned for statement with the line of the
+// statement it points at and a caret under the offending text:
+//
+//	find() projection must be a document
+//	db.users.find({ age: 1 }, 5)
+//	                          ^
+//
+// Errors without a position, such as a *ServerError, are formatted as
+// err.Error().
+func FormatError(statement string, err error) string {
+	var line, column, width int
+	var parseErr *ParseError
+	var semanticErr *SemanticError
+	switch {
+	case errors.As(err, &semanticErr):
+		line, column = semanticErr.Line, semanticErr.Column
+		width = semanticErr.End - semanticErr.Start
+	case errors.As(err, &parseErr):
+		line, column = parseErr.Line, parseErr.Column
+		width = len(parseErr.Found)
+	}
+	lines := strings.Split(statement, "\n")
+	if line < 1 || line > len(lines) || column < 1 || column > len(lines[line-1])+1 {
+		return err.Error()
+	}
+
+	// Underline from the column to the end of the span, or of the line for
+	// a span that continues on the next lines. A parse error is underlined
+	// for the length of the token found where it is still in the source.
+	src := strings.TrimSuffix(lines[line-1], "\r")
+	start := min(column-1, len(src))
+	end := min(start+width, len(src))
+	if parseErr != nil && semanticErr == nil && !strings.HasPrefix(src[start:], parseErr.Found) {
+		end = start
+	}
+
+	var b strings.Builder
+	b.WriteString(err.Error())
+	b.WriteString("\n")
+	b.WriteString(src)
+	b.WriteString("\n")
+	for _, r := range src[:start] {
+		// Keep tabs so the caret lines up however tabs are displayed.
+		if r == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	b.WriteString("^")
+	if n := utf8.RuneCountInString(src[start:end]); n > 1 {
+		b.WriteString(strings.Repeat("~", n-1))
+	}
+	return b.String()
+}
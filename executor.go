@@ -2,44 +2,196 @@ package gomongo
 
 import (
 	"context"
+	"reflect"
+	"time"
 
 	"github.com/bytebase/gomongo/internal/executor"
 	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/bytebase/gomongo/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
-// execute parses and executes a MongoDB shell statement.
-func execute(ctx context.Context, client *mongo.Client, database, statement string, maxRows *int64) (*Result, error) {
-	op, err := translator.Parse(statement)
+// securityAdminOperations maps the operations that require WithSecurityAdmin() to their method names.
+var securityAdminOperations = map[types.OperationType]string{
+	types.OpCreateUser:            "createUser()",
+	types.OpUpdateUser:            "updateUser()",
+	types.OpDropUser:              "dropUser()",
+	types.OpGrantRolesToUser:      "grantRolesToUser()",
+	types.OpRevokeRolesFromUser:   "revokeRolesFromUser()",
+	types.OpCreateRole:            "createRole()",
+	types.OpGrantPrivilegesToRole: "grantPrivilegesToRole()",
+	types.OpChangeUserPassword:    "changeUserPassword()",
+}
+
+// javaScriptOperators are the operators that run JavaScript on the server.
+var javaScriptOperators = map[string]bool{"$where": true, "$function": true, "$accumulator": true}
+
+// parse translates a MongoDB shell statement into an operation, converting
+// translator errors to public errors and enforcing WithSecurityAdmin() and
+// WithoutServerSideJavaScript().
+func parse(statement string, cfg *executeConfig) (*translator.Operation, error) {
+	stmt, err := translator.PrepareWithOptions(statement, translator.Options{ExtendedJSON: cfg.extendedJSON})
 	if err != nil {
-		// Convert internal errors to public errors
-		switch e := err.(type) {
-		case *translator.ParseError:
-			return nil, &ParseError{
-				Line:     e.Line,
-				Column:   e.Column,
-				Message:  e.Message,
-				Found:    e.Found,
-				Expected: e.Expected,
+		return nil, convertTranslatorError(statement, err)
+	}
+	return bind(statement, stmt, cfg)
+}
+
+// bind translates a prepared statement with the parameters of cfg, converting
+// translator errors to public errors and enforcing WithSecurityAdmin() and
+// WithoutServerSideJavaScript().
+func bind(statement string, stmt *translator.Statement, cfg *executeConfig) (*translator.Operation, error) {
+	op, err := stmt.Bind(translatorParams(cfg.params))
+	if err != nil {
+		return nil, convertTranslatorError(statement, err)
+	}
+
+	if name, ok := securityAdminOperations[op.OpType]; ok && !cfg.securityAdmin {
+		return nil, &SecurityAdminRequiredError{Operation: name}
+	}
+	if cfg.noJavaScript {
+		if operator := javaScriptOperator(op); operator != "" {
+			return nil, &ServerSideJavaScriptError{Operator: operator}
+		}
+	}
+	return op, nil
+}
+
+// javaScriptOperator returns the first operator in any field of an operation
+// that runs JavaScript on the server, or "" if there is none. Every field is
+// searched, including let variables and options, so that no field added to
+// Operation is missed.
+func javaScriptOperator(op *translator.Operation) string {
+	fields := reflect.ValueOf(op).Elem()
+	for i := 0; i < fields.NumField(); i++ {
+		if !fields.Type().Field(i).IsExported() {
+			continue
+		}
+		if operator := findJavaScriptOperator(fields.Field(i).Interface()); operator != "" {
+			return operator
+		}
+	}
+	return ""
+}
+
+func findJavaScriptOperator(v any) string {
+	switch v := v.(type) {
+	case bson.D:
+		for _, e := range v {
+			if javaScriptOperators[e.Key] {
+				return e.Key
+			}
+			if operator := findJavaScriptOperator(e.Value); operator != "" {
+				return operator
+			}
+		}
+	case bson.A:
+		for _, elem := range v {
+			if operator := findJavaScriptOperator(elem); operator != "" {
+				return operator
+			}
+		}
+	case []bson.D:
+		for _, doc := range v {
+			if operator := findJavaScriptOperator(doc); operator != "" {
+				return operator
 			}
-		case *translator.UnsupportedOperationError:
-			return nil, &UnsupportedOperationError{Operation: e.Operation}
-		case *translator.PlannedOperationError:
-			return nil, &PlannedOperationError{Operation: e.Operation}
-		case *translator.UnsupportedOptionError:
-			return nil, &UnsupportedOptionError{Method: e.Method, Option: e.Option}
-		default:
-			return nil, err
 		}
 	}
+	return ""
+}
+
+// convertTranslatorError converts internal translator errors to public errors.
+// statement is the statement the error was found in.
+func convertTranslatorError(statement string, err error) error {
+	switch e := err.(type) {
+	case *translator.ParseError:
+		return &ParseError{
+			Line:     e.Line,
+			Column:   e.Column,
+			Message:  e.Message,
+			Found:    e.Found,
+			Expected: e.Expected,
+		}
+	case *translator.UnsupportedOperationError:
+		return &UnsupportedOperationError{Operation: e.Operation}
+	case *translator.PlannedOperationError:
+		return &PlannedOperationError{Operation: e.Operation}
+	case *translator.UnsupportedOptionError:
+		return &UnsupportedOptionError{Method: e.Method, Option: e.Option}
+	case *translator.SemanticError:
+		line, column := lineColumn(statement, e.Start)
+		return &SemanticError{
+			Message: e.Message,
+			Method:  e.Method,
+			Arg:     e.Arg,
+			Line:    line,
+			Column:  column,
+			Start:   e.Start,
+			End:     e.End,
+		}
+	default:
+		return err
+	}
+}
 
-	result, err := executor.Execute(ctx, client, database, op, statement, maxRows)
+// executorOptions converts the configuration to executor options.
+func executorOptions(cfg *executeConfig) executor.Options {
+	return executor.Options{
+		MaxRows:       cfg.maxRows,
+		MaxBytes:      cfg.maxBytes,
+		MaxValueBytes: cfg.maxValueBytes,
+		KillOnCancel:  cfg.killOnCancel,
+	}
+}
+
+// execute parses and executes a MongoDB shell statement.
+func execute(ctx context.Context, client *mongo.Client, database, statement string, cfg *executeConfig) (*Result, error) {
+	start := time.Now()
+	op, err := parse(statement, cfg)
 	if err != nil {
 		return nil, err
 	}
+	return executeOperation(ctx, client, database, statement, op, cfg, start)
+}
 
-	return &Result{
-		Operation: result.Operation,
-		Value:     result.Value,
-	}, nil
+// executeOperation executes a translated statement. start is the time parsing began.
+func executeOperation(ctx context.Context, client *mongo.Client, database, statement string, op *translator.Operation, cfg *executeConfig, start time.Time) (*Result, error) {
+	ctx, stats := withCommandStats(ctx)
+	result, err := executor.Execute(ctx, client, database, op, statement, executorOptions(cfg))
+	if err != nil {
+		err = newServerError(err, op.OpType, op.Collection)
+		if result == nil {
+			return nil, err
+		}
+	}
+
+	r := &Result{
+		Operation:       result.Operation,
+		Value:           result.Value,
+		Truncated:       result.Truncated,
+		TruncatedValues: result.TruncatedValues,
+		Warnings:        convertWarnings(statement, op.Warnings),
+		Duration:        time.Since(start),
+	}
+	stats.apply(r)
+	return r, err
+}
+
+// stream parses a MongoDB shell statement and calls fn for each document it returns.
+func stream(ctx context.Context, client *mongo.Client, database, statement string, cfg *executeConfig, fn func(bson.D) error) error {
+	op, err := parse(statement, cfg)
+	if err != nil {
+		return err
+	}
+	return streamOperation(ctx, client, database, statement, op, cfg, fn)
+}
+
+// streamOperation executes a translated statement and calls fn for each document it returns.
+func streamOperation(ctx context.Context, client *mongo.Client, database, statement string, op *translator.Operation, cfg *executeConfig, fn func(bson.D) error) error {
+	if err := executor.Stream(ctx, client, database, op, statement, executorOptions(cfg), fn); err != nil {
+		return newServerError(err, op.OpType, op.Collection)
+	}
+	return nil
 }
@@ -0,0 +1,276 @@
+package gomongo
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// exportDateLayout is the layout mongoexport uses for dates in CSV output.
+const exportDateLayout = "2006-01-02T15:04:05.000Z"
+
+// ExportFormat is the output format of an ExportWriter.
+type ExportFormat int
+
+const (
+	// ExportCSV writes comma-separated values, one row per document.
+	ExportCSV ExportFormat = iota
+	// ExportTSV writes tab-separated values, one row per document.
+	ExportTSV
+	// ExportJSONLines writes one Extended JSON document per line.
+	ExportJSONLines
+)
+
+// exportConfig holds configuration for an ExportWriter.
+type exportConfig struct {
+	fields    []string
+	noHeader  bool
+	canonical bool
+}
+
+// ExportOption configures ExportWriter behavior.
+type ExportOption func(*exportConfig)
+
+// WithFields selects the fields to export, in order. Nested fields are given as
+// dotted paths, such as "address.city", and array elements by index, such as
+// "tags.0". For CSV and TSV each field is a column; for JSON Lines documents are
+// reduced to these fields. Without a field list, CSV and TSV use the top-level
+// fields of the first document and JSON Lines writes whole documents.
+func WithFields(fields ...string) ExportOption {
+	return func(c *exportConfig) {
+		c.fields = fields
+	}
+}
+
+// WithoutHeader omits the header row of field names from CSV and TSV output.
+func WithoutHeader() ExportOption {
+	return func(c *exportConfig) {
+		c.noHeader = true
+	}
+}
+
+// WithCanonicalJSON writes JSON Lines output as canonical rather than relaxed
+// Extended JSON, preserving every BSON type.
+func WithCanonicalJSON() ExportOption {
+	return func(c *exportConfig) {
+		c.canonical = true
+	}
+}
+
+// ExportWriter writes documents as CSV, TSV or JSON Lines, following the
+// conventions of mongoexport.
+//
+// In CSV and TSV output, ObjectIDs are written as ObjectId(<hex>), dates as
+// ISO-8601 UTC timestamps with millisecond precision, binary data as base64,
+// nested documents and arrays as relaxed Extended JSON, and null or missing
+// fields as empty cells.
+//
+// Output is buffered; call Flush after the last document.
+type ExportWriter struct {
+	format      ExportFormat
+	cfg         exportConfig
+	w           *bufio.Writer
+	csv         *csv.Writer
+	fields      []string
+	wroteHeader bool
+}
+
+// NewExportWriter returns a writer that exports documents to w in the given format.
+func NewExportWriter(w io.Writer, format ExportFormat, opts ...ExportOption) *ExportWriter {
+	e := &ExportWriter{format: format, w: bufio.NewWriter(w)}
+	for _, opt := range opts {
+		opt(&e.cfg)
+	}
+	e.fields = e.cfg.fields
+	if format == ExportCSV || format == ExportTSV {
+		e.csv = csv.NewWriter(e.w)
+		if format == ExportTSV {
+			e.csv.Comma = '\t'
+		}
+	}
+	return e
+}
+
+// Write exports a single document.
+func (e *ExportWriter) Write(doc bson.D) error {
+	if e.csv == nil {
+		return e.writeJSONLine(doc)
+	}
+
+	if e.fields == nil {
+		e.fields = make([]string, len(doc))
+		for i, elem := range doc {
+			e.fields[i] = elem.Key
+		}
+	}
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(e.fields))
+	for i, field := range e.fields {
+		value, ok := lookupPath(doc, field)
+		if !ok {
+			continue
+		}
+		cell, err := exportCell(value)
+		if err != nil {
+			return fmt.Errorf("export field %q: %w", field, err)
+		}
+		record[i] = cell
+	}
+	return e.csv.Write(record)
+}
+
+// Flush writes any buffered output to the underlying writer. For CSV and TSV
+// with a field list, it also writes the header if no document was written.
+func (e *ExportWriter) Flush() error {
+	if e.csv != nil {
+		if e.fields != nil {
+			if err := e.writeHeader(); err != nil {
+				return err
+			}
+		}
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return e.w.Flush()
+}
+
+// writeHeader writes the header row once, unless disabled.
+func (e *ExportWriter) writeHeader() error {
+	if e.wroteHeader || e.cfg.noHeader {
+		return nil
+	}
+	e.wroteHeader = true
+	return e.csv.Write(e.fields)
+}
+
+// writeJSONLine writes a document, reduced to the selected fields, as one line of Extended JSON.
+func (e *ExportWriter) writeJSONLine(doc bson.D) error {
+	if e.fields != nil {
+		doc = projectFields(doc, e.fields)
+	}
+	data, err := bson.MarshalExtJSON(doc, e.cfg.canonical, false)
+	if err != nil {
+		return fmt.Errorf("marshal document: %w", err)
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+	return e.w.WriteByte('\n')
+}
+
+// Export executes a MongoDB shell statement and writes every document it returns
+// to ew, then flushes it. Documents are streamed as with Stream, so exports of any
+// size use constant memory.
+func (c *Client) Export(ctx context.Context, database, statement string, ew *ExportWriter, opts ...ExecuteOption) error {
+	if err := c.Stream(ctx, database, statement, ew.Write, opts...); err != nil {
+		return err
+	}
+	return ew.Flush()
+}
+
+// exportCell formats a value as a CSV or TSV cell.
+func exportCell(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bson.ObjectID:
+		return "ObjectId(" + v.Hex() + ")", nil
+	case bson.DateTime:
+		return v.Time().UTC().Format(exportDateLayout), nil
+	case bson.Binary:
+		return base64.StdEncoding.EncodeToString(v.Data), nil
+	case bson.Decimal128:
+		return v.String(), nil
+	}
+	data, err := marshalExtJSONValue(v, false)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// lookupPath finds the value at a dotted path, descending into nested documents
+// by key and into arrays by index.
+func lookupPath(doc bson.D, path string) (any, bool) {
+	var current any = doc
+	for _, part := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case bson.D:
+			found := false
+			for _, elem := range v {
+				if elem.Key == part {
+					current, found = elem.Value, true
+					break
+				}
+			}
+			if !found {
+				return nil, false
+			}
+		case bson.A:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			current = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// projectFields returns a document holding only the fields at the given paths,
+// nested as in the original document and in field-list order.
+func projectFields(doc bson.D, fields []string) bson.D {
+	out := bson.D{}
+	for _, field := range fields {
+		value, ok := lookupPath(doc, field)
+		if !ok {
+			continue
+		}
+		out = setPath(out, strings.Split(field, "."), value)
+	}
+	return out
+}
+
+// setPath sets the value at a path in doc, creating nested documents as needed.
+func setPath(doc bson.D, parts []string, value any) bson.D {
+	for i, elem := range doc {
+		if elem.Key != parts[0] {
+			continue
+		}
+		if len(parts) == 1 {
+			doc[i].Value = value
+			return doc
+		}
+		nested, _ := elem.Value.(bson.D)
+		doc[i].Value = setPath(nested, parts[1:], value)
+		return doc
+	}
+	if len(parts) == 1 {
+		return append(doc, bson.E{Key: parts[0], Value: value})
+	}
+	return append(doc, bson.E{Key: parts[0], Value: setPath(bson.D{}, parts[1:], value)})
+}
@@ -0,0 +1,192 @@
+package gomongo_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// exportDocuments writes docs with a new ExportWriter and returns the output.
+func exportDocuments(t *testing.T, format gomongo.ExportFormat, docs []bson.D, opts ...gomongo.ExportOption) string {
+	t.Helper()
+	var buf bytes.Buffer
+	ew := gomongo.NewExportWriter(&buf, format, opts...)
+	for _, doc := range docs {
+		require.NoError(t, ew.Write(doc))
+	}
+	require.NoError(t, ew.Flush())
+	return buf.String()
+}
+
+func TestExportWriter(t *testing.T) {
+	oid, err := bson.ObjectIDFromHex("507f1f77bcf86cd799439011")
+	require.NoError(t, err)
+	at := bson.NewDateTimeFromTime(time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC))
+
+	docs := []bson.D{
+		{
+			{Key: "_id", Value: oid},
+			{Key: "name", Value: "alice, \"al\""},
+			{Key: "joined", Value: at},
+			{Key: "address", Value: bson.D{{Key: "city", Value: "Paris"}}},
+			{Key: "tags", Value: bson.A{"a", "b"}},
+			{Key: "avatar", Value: bson.Binary{Data: []byte{1, 2, 3}}},
+		},
+		{
+			{Key: "_id", Value: int32(2)},
+			{Key: "name", Value: "bob"},
+			{Key: "joined", Value: nil},
+			{Key: "score", Value: 1.5},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		format   gomongo.ExportFormat
+		opts     []gomongo.ExportOption
+		expected string
+	}{
+		{
+			name:   "csv with fields from the first document",
+			format: gomongo.ExportCSV,
+			expected: "_id,name,joined,address,tags,avatar\n" +
+				"ObjectId(507f1f77bcf86cd799439011),\"alice, \"\"al\"\"\",2024-01-02T10:30:00.000Z,\"{\"\"city\"\":\"\"Paris\"\"}\",\"[\"\"a\"\",\"\"b\"\"]\",AQID\n" +
+				"2,bob,,,,\n",
+		},
+		{
+			name:   "csv with a field list",
+			format: gomongo.ExportCSV,
+			opts:   []gomongo.ExportOption{gomongo.WithFields("name", "address.city", "tags.1", "score")},
+			expected: "name,address.city,tags.1,score\n" +
+				"\"alice, \"\"al\"\"\",Paris,b,\n" +
+				"bob,,,1.5\n",
+		},
+		{
+			name:     "tsv without header",
+			format:   gomongo.ExportTSV,
+			opts:     []gomongo.ExportOption{gomongo.WithFields("_id", "name"), gomongo.WithoutHeader()},
+			expected: "ObjectId(507f1f77bcf86cd799439011)\t\"alice, \"\"al\"\"\"\n2\tbob\n",
+		},
+		{
+			name:   "json lines",
+			format: gomongo.ExportJSONLines,
+			opts:   []gomongo.ExportOption{gomongo.WithFields("_id", "address.city", "joined")},
+			expected: `{"_id":{"$oid":"507f1f77bcf86cd799439011"},"address":{"city":"Paris"},"joined":{"$date":"2024-01-02T10:30:00Z"}}` + "\n" +
+				`{"_id":2,"joined":null}` + "\n",
+		},
+		{
+			name:   "canonical json lines",
+			format: gomongo.ExportJSONLines,
+			opts:   []gomongo.ExportOption{gomongo.WithFields("_id", "score"), gomongo.WithCanonicalJSON()},
+			expected: `{"_id":{"$oid":"507f1f77bcf86cd799439011"}}` + "\n" +
+				`{"_id":{"$numberInt":"2"},"score":{"$numberDouble":"1.5"}}` + "\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, exportDocuments(t, tc.format, docs, tc.opts...))
+		})
+	}
+}
+
+func TestExportWriterEmpty(t *testing.T) {
+	// A field list gives a header even when there are no documents.
+	require.Equal(t, "a,b\n", exportDocuments(t, gomongo.ExportCSV, nil, gomongo.WithFields("a", "b")))
+	require.Equal(t, "", exportDocuments(t, gomongo.ExportCSV, nil))
+	require.Equal(t, "", exportDocuments(t, gomongo.ExportJSONLines, nil))
+}
+
+func TestStreamAndExport(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_export_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		var docs []string
+		for i := 0; i < 250; i++ {
+			docs = append(docs, fmt.Sprintf(`{ _id: %d, name: "user%d" }`, i, i))
+		}
+		_, err := gc.Execute(ctx, dbName, fmt.Sprintf(`db.users.insertMany([%s])`, strings.Join(docs, ", ")))
+		require.NoError(t, err)
+
+		// Stream reads every document, across several getMore batches.
+		count := 0
+		err = gc.Stream(ctx, dbName, `db.users.find()`, func(doc bson.D) error {
+			count++
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 250, count)
+
+		// Execute options apply to streams.
+		count = 0
+		err = gc.Stream(ctx, dbName, `db.users.find()`, func(doc bson.D) error {
+			count++
+			return nil
+		}, gomongo.WithMaxRows(10))
+		require.NoError(t, err)
+		require.Equal(t, 10, count)
+
+		// An error from the callback stops the stream.
+		stop := errors.New("stop")
+		count = 0
+		err = gc.Stream(ctx, dbName, `db.users.aggregate([{ $sort: { _id: 1 } }])`, func(doc bson.D) error {
+			count++
+			if count == 3 {
+				return stop
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, stop)
+		require.Equal(t, 3, count)
+
+		// Statements that do not return documents cannot be streamed.
+		err = gc.Stream(ctx, dbName, `db.users.countDocuments()`, func(doc bson.D) error { return nil })
+		require.ErrorContains(t, err, "does not return documents")
+
+		// Writes are rejected before they run, leaving the collection unchanged.
+		for _, statement := range []string{
+			`db.users.deleteMany({})`,
+			`db.users.insertOne({ _id: "new" })`,
+			`db.users.drop()`,
+			`db.users.aggregate([{ $match: {} }, { $out: "copy" }])`,
+			`db.users.aggregate([{ $merge: { into: "users", whenMatched: "replace" } }])`,
+		} {
+			err = gc.Stream(ctx, dbName, statement, func(doc bson.D) error { return nil })
+			require.ErrorContains(t, err, "does not return documents")
+		}
+		names, err := gc.Execute(ctx, dbName, `db.getCollectionNames()`)
+		require.NoError(t, err)
+		require.NotContains(t, names.Value, "copy")
+		err = gc.Export(ctx, dbName, `db.users.deleteMany({})`, gomongo.NewExportWriter(io.Discard, gomongo.ExportJSONLines))
+		require.ErrorContains(t, err, "does not return documents")
+		result, err := gc.Execute(ctx, dbName, `db.users.countDocuments()`)
+		require.NoError(t, err)
+		require.EqualValues(t, 250, result.Value[0])
+
+		var buf bytes.Buffer
+		ew := gomongo.NewExportWriter(&buf, gomongo.ExportCSV, gomongo.WithFields("_id", "name"))
+		err = gc.Export(ctx, dbName, `db.users.find({ _id: { $lt: 3 } }).sort({ _id: 1 })`, ew)
+		require.NoError(t, err)
+		require.Equal(t, "_id,name\n0,user0\n1,user1\n2,user2\n", buf.String())
+
+		buf.Reset()
+		ew = gomongo.NewExportWriter(&buf, gomongo.ExportJSONLines)
+		err = gc.Export(ctx, dbName, `db.users.getIndexes()`, ew)
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), `"name":"_id_"`)
+	})
+}
@@ -0,0 +1,125 @@
+package gomongo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestExpressions(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_expressions_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		now := time.Now()
+		_, err := gc.Execute(ctx, dbName, `db.logs.insertMany([
+			{ name: "recent", ts: new Date(Date.now() - 60 * 60 * 1000), size: 2 * 1024 },
+			{ name: "old", ts: new Date(Date.now() - 3 * 24 * 60 * 60 * 1000), size: 512 },
+		])`)
+		require.NoError(t, err)
+
+		result, err := gc.Execute(ctx, dbName, `db.logs.find({ ts: { $gt: new Date(Date.now() - 24*60*60*1000) } }, { _id: 0, name: 1, size: 1 })`)
+		require.NoError(t, err)
+		require.Equal(t, []any{bson.D{{Key: "name", Value: "recent"}, {Key: "size", Value: int32(2048)}}}, result.Value)
+
+		result, err = gc.Execute(ctx, dbName, `db.logs.findOne({ name: "rec" + "ent" })`)
+		require.NoError(t, err)
+		ts := result.Value[0].(bson.D)
+		for _, e := range ts {
+			if e.Key == "ts" {
+				require.WithinDuration(t, now.Add(-time.Hour), e.Value.(bson.DateTime).Time(), time.Minute)
+			}
+		}
+
+		result, err = gc.Execute(ctx, dbName, `
+			const day = 24 * 60 * 60 * 1000
+			let cutoff = new Date(Date.now() - :days * day)
+			db.logs.deleteMany({ ts: { $lt: cutoff } })`,
+			gomongo.WithParams(gomongo.Named("days", 2)))
+		require.NoError(t, err)
+		deleted, ok := result.DeleteResult()
+		require.True(t, ok)
+		require.Equal(t, int64(1), deleted.DeletedCount)
+
+		_, err = gc.Execute(ctx, dbName, `db.logs.find({ ts: { $lt: cutoff } })`)
+		require.ErrorContains(t, err, "cutoff is not defined")
+	})
+}
+
+func TestFunctions(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_functions_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		_, err := gc.Execute(ctx, dbName, `db.items.insertMany([{ _id: 1, a: 3, b: 1 }, { _id: 2, a: 1, b: 2 }])`)
+		require.NoError(t, err)
+
+		result, err := gc.Execute(ctx, dbName, `db.items.find({ $where: function() { return this.a > this.b } }, { _id: 1 })`)
+		require.NoError(t, err)
+		require.Equal(t, []any{bson.D{{Key: "_id", Value: int32(1)}}}, result.Value)
+
+		result, err = gc.Execute(ctx, dbName, `db.items.aggregate([
+			{ $sort: { _id: 1 } },
+			{ $project: { _id: 0, double: { $function: { body: function(a) { return a * 2 }, args: ["$a"], lang: "js" } } } },
+		])`)
+		require.NoError(t, err)
+		require.Equal(t, []any{bson.D{{Key: "double", Value: 6.0}}, bson.D{{Key: "double", Value: 2.0}}}, result.Value)
+
+		result, err = gc.Execute(ctx, dbName, `db.items.aggregate([{ $group: { _id: null, sum: { $accumulator: {
+			init: function() { return 0 },
+			accumulate: (state, a) => state + a,
+			accumulateArgs: ["$a"],
+			merge: (x, y) => x + y,
+			lang: "js",
+		} } } }])`)
+		require.NoError(t, err)
+		require.Equal(t, []any{bson.D{{Key: "_id", Value: nil}, {Key: "sum", Value: 4.0}}}, result.Value)
+	})
+}
+
+func TestWithoutServerSideJavaScript(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_no_javascript_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client, gomongo.WithoutServerSideJavaScript())
+
+		tests := []struct {
+			statement string
+			params    []any
+			operator  string
+		}{
+			{`db.items.find({ $where: function() { return true } })`, nil, "$where"},
+			{`db.items.find({ $where: "true" })`, nil, "$where"},
+			{`db.items.find({ $or: [{ a: 1 }, { $where: "true" }] })`, nil, "$where"},
+			{`db.items.aggregate([{ $project: { x: { $function: { body: "function() { return 1 }", args: [], lang: "js" } } } }])`, nil, "$function"},
+			{`db.items.updateMany({}, [{ $set: { x: { $function: { body: "function() { return 1 }", args: [], lang: "js" } } } }])`, nil, "$function"},
+			{`db.items.find($1)`, []any{bson.D{{Key: "$where", Value: "true"}}}, "$where"},
+			{`db.items.updateOne({ $expr: { $eq: ["$a", "$$x"] } }, { $set: { b: 1 } }, { let: { x: { $function: { body: "function() { return 1 }", args: [], lang: "js" } } } })`, nil, "$function"},
+			{`db.items.createIndexes([{ key: { a: 1 }, partialFilterExpression: { $where: "true" } }])`, nil, "$where"},
+		}
+		for _, tc := range tests {
+			_, err := gc.Execute(ctx, dbName, tc.statement, gomongo.WithParams(tc.params...))
+			var jsErr *gomongo.ServerSideJavaScriptError
+			require.ErrorAs(t, err, &jsErr, tc.statement)
+			require.Equal(t, tc.operator, jsErr.Operator)
+		}
+
+		// Strings that only mention an operator are allowed.
+		_, err := gc.Execute(ctx, dbName, `db.items.find({ note: "$where" })`)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,96 @@
+package gomongo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// extJSONWrapperKey is the key of the single-field document used to encode a
+// value on its own, since the driver only marshals documents at the top level.
+const extJSONWrapperKey = "v"
+
+// MarshalExtJSON encodes the result as MongoDB Extended JSON v2.
+//
+// The output is always a JSON array mirroring Result.Value, so every operation
+// encodes the same way: documents become objects and scalar results such as
+// int64 counts or the bool returned by drop() become one-element arrays.
+//
+// Canonical mode preserves every BSON type, so decoding the output with
+// bson.UnmarshalExtJSON restores the original values exactly. Relaxed mode
+// writes numbers as plain JSON numbers, which loses the distinction between
+// int32, int64 and double.
+//
+// Canonical output is also valid statement input for a client created with
+// WithExtendedJSONInput(): embedded in a statement, it reads back as the
+// original values. Without that option the wrappers stay nested documents, so
+// a filter built from the output matches nothing.
+func (r *Result) MarshalExtJSON(canonical bool) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeExtJSONArray(&buf, r.Value, canonical); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ExtJSONEncoder writes results to a stream as MongoDB Extended JSON v2.
+type ExtJSONEncoder struct {
+	w         io.Writer
+	canonical bool
+}
+
+// NewExtJSONEncoder returns an encoder that writes canonical or relaxed
+// Extended JSON to w.
+func NewExtJSONEncoder(w io.Writer, canonical bool) *ExtJSONEncoder {
+	return &ExtJSONEncoder{w: w, canonical: canonical}
+}
+
+// Encode writes the result in the same format as Result.MarshalExtJSON,
+// followed by a newline. Values are encoded and written one at a time, so the
+// full encoded result is never held in memory.
+func (e *ExtJSONEncoder) Encode(r *Result) error {
+	if err := writeExtJSONArray(e.w, r.Value, e.canonical); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "\n")
+	return err
+}
+
+// writeExtJSONArray writes values as an Extended JSON array.
+func writeExtJSONArray(w io.Writer, values []any, canonical bool) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	for i, v := range values {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		data, err := marshalExtJSONValue(v, canonical)
+		if err != nil {
+			return fmt.Errorf("marshal value %d: %w", i, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// marshalExtJSONValue encodes a single value of any BSON type, including scalars
+// that the driver cannot marshal on their own.
+func marshalExtJSONValue(v any, canonical bool) ([]byte, error) {
+	data, err := bson.MarshalExtJSON(bson.D{{Key: extJSONWrapperKey, Value: v}}, canonical, false)
+	if err != nil {
+		return nil, err
+	}
+	prefix := []byte(`{"` + extJSONWrapperKey + `":`)
+	if !bytes.HasPrefix(data, prefix) || !bytes.HasSuffix(data, []byte("}")) {
+		return nil, fmt.Errorf("unexpected Extended JSON encoding: %s", data)
+	}
+	return data[len(prefix) : len(data)-1], nil
+}
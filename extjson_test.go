@@ -0,0 +1,144 @@
+package gomongo_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/bytebase/gomongo/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// extJSONShapes covers each shape Result.Value can take.
+func extJSONShapes(t *testing.T) []struct {
+	name    string
+	result  gomongo.Result
+	relaxed string
+} {
+	t.Helper()
+	oid, err := bson.ObjectIDFromHex("507f1f77bcf86cd799439011")
+	require.NoError(t, err)
+	dec, err := bson.ParseDecimal128("1.50")
+	require.NoError(t, err)
+	date := bson.NewDateTimeFromTime(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC))
+
+	return []struct {
+		name    string
+		result  gomongo.Result
+		relaxed string
+	}{
+		{
+			name: "documents",
+			result: gomongo.Result{Operation: types.OpFind, Value: []any{
+				bson.D{{Key: "_id", Value: oid}, {Key: "at", Value: date}, {Key: "price", Value: dec}},
+				bson.D{{Key: "tags", Value: bson.A{"a", int32(1)}}, {Key: "big", Value: int64(1) << 40}},
+			}},
+			relaxed: `[{"_id":{"$oid":"507f1f77bcf86cd799439011"},"at":{"$date":"2024-01-02T10:00:00Z"},"price":{"$numberDecimal":"1.50"}},{"tags":["a",1],"big":1099511627776}]`,
+		},
+		{
+			name:    "empty",
+			result:  gomongo.Result{Operation: types.OpFindOne, Value: []any{}},
+			relaxed: `[]`,
+		},
+		{
+			name:    "count",
+			result:  gomongo.Result{Operation: types.OpCountDocuments, Value: []any{int64(42)}},
+			relaxed: `[42]`,
+		},
+		{
+			name:    "drop",
+			result:  gomongo.Result{Operation: types.OpDrop, Value: []any{true}},
+			relaxed: `[true]`,
+		},
+		{
+			name:    "strings",
+			result:  gomongo.Result{Operation: types.OpShowCollections, Value: []any{"users", "orders"}},
+			relaxed: `["users","orders"]`,
+		},
+		{
+			name:    "distinct",
+			result:  gomongo.Result{Operation: types.OpDistinct, Value: []any{int32(1), 2.5, "x", nil, bson.D{{Key: "k", Value: "v"}}}},
+			relaxed: `[1,2.5,"x",null,{"k":"v"}]`,
+		},
+		{
+			name:    "data size",
+			result:  gomongo.Result{Operation: types.OpDataSize, Value: []any{int32(512)}},
+			relaxed: `[512]`,
+		},
+		{
+			name:    "write result",
+			result:  gomongo.Result{Operation: types.OpInsertOne, Value: []any{bson.D{{Key: "acknowledged", Value: true}, {Key: "insertedId", Value: oid}}}},
+			relaxed: `[{"acknowledged":true,"insertedId":{"$oid":"507f1f77bcf86cd799439011"}}]`,
+		},
+	}
+}
+
+func TestResultMarshalExtJSONShapes(t *testing.T) {
+	for _, tc := range extJSONShapes(t) {
+		t.Run(tc.name, func(t *testing.T) {
+			relaxed, err := tc.result.MarshalExtJSON(false)
+			require.NoError(t, err)
+			require.Equal(t, tc.relaxed, string(relaxed))
+
+			// Canonical output decodes back to exactly the original values.
+			canonical, err := tc.result.MarshalExtJSON(true)
+			require.NoError(t, err)
+			var decoded bson.D
+			require.NoError(t, bson.UnmarshalExtJSON([]byte(`{"v":`+string(canonical)+`}`), true, &decoded))
+			require.Equal(t, bson.A(tc.result.Value), decoded[0].Value)
+
+			// The encoder writes the same bytes followed by a newline.
+			var buf bytes.Buffer
+			require.NoError(t, gomongo.NewExtJSONEncoder(&buf, true).Encode(&tc.result))
+			require.Equal(t, string(canonical)+"\n", buf.String())
+		})
+	}
+}
+
+func TestResultMarshalExtJSONTranslatorRoundTrip(t *testing.T) {
+	for _, tc := range extJSONShapes(t) {
+		t.Run(tc.name, func(t *testing.T) {
+			canonical, err := tc.result.MarshalExtJSON(true)
+			require.NoError(t, err)
+
+			// With Extended JSON input the output is valid statement input: embedded
+			// as an $in array it binds to exactly the original values.
+			stmt, err := translator.PrepareWithOptions(fmt.Sprintf(`db.c.find({ v: { $in: %s } })`, canonical), translator.Options{ExtendedJSON: true})
+			require.NoError(t, err)
+			op, err := stmt.Bind(nil)
+			require.NoError(t, err)
+			require.Equal(t, bson.A(tc.result.Value), op.Filter[0].Value.(bson.D)[0].Value)
+		})
+	}
+}
+
+func TestResultMarshalExtJSON(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_extjson_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		_, err := gc.Execute(ctx, dbName, `db.items.insertOne({ _id: ObjectId("507f1f77bcf86cd799439011"), qty: NumberLong(5), at: ISODate("2024-01-02T10:00:00Z") })`)
+		require.NoError(t, err)
+
+		result, err := gc.Execute(ctx, dbName, `db.items.find()`)
+		require.NoError(t, err)
+		data, err := result.MarshalExtJSON(true)
+		require.NoError(t, err)
+		require.Equal(t, `[{"_id":{"$oid":"507f1f77bcf86cd799439011"},"qty":{"$numberLong":"5"},"at":{"$date":{"$numberLong":"1704189600000"}}}]`, string(data))
+
+		result, err = gc.Execute(ctx, dbName, `db.items.countDocuments()`)
+		require.NoError(t, err)
+		data, err = result.MarshalExtJSON(false)
+		require.NoError(t, err)
+		require.Equal(t, `[1]`, string(data))
+	})
+}
@@ -0,0 +1,464 @@
+package gomongo
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytebase/gomongo/internal/executor"
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/bytebase/gomongo/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// defaultImportBatchSize is the number of documents written per batch.
+const defaultImportBatchSize = 1000
+
+// ImportFormat is the input format read by Import.
+type ImportFormat int
+
+const (
+	// ImportJSON reads Extended JSON documents, either as a single JSON array or
+	// as a sequence of documents such as JSON Lines.
+	ImportJSON ImportFormat = iota
+	// ImportCSV reads comma-separated values, one document per row.
+	ImportCSV
+	// ImportTSV reads tab-separated values, one document per row.
+	ImportTSV
+)
+
+// ImportMode selects how imported documents are written.
+type ImportMode int
+
+const (
+	// ImportInsert inserts every document, as insertMany() does.
+	ImportInsert ImportMode = iota
+	// ImportUpsert replaces the existing document with the same upsert fields,
+	// or inserts the document if there is none.
+	ImportUpsert
+	// ImportMerge sets the document's fields on the existing document with the
+	// same upsert fields, or inserts the document if there is none.
+	ImportMerge
+)
+
+// ImportResult reports the outcome of an import.
+type ImportResult struct {
+	Processed int64        // documents read from the input and sent to the server
+	Inserted  int64        // documents inserted
+	Matched   int64        // documents that matched an existing document in upsert or merge mode
+	Modified  int64        // matched documents that were changed
+	Upserted  int64        // documents inserted in upsert or merge mode because nothing matched
+	Failed    int64        // documents rejected by the server
+	Errors    []WriteError // the rejected documents, indexed by their position in the input
+}
+
+// importConfig holds configuration for Import.
+type importConfig struct {
+	mode         ImportMode
+	upsertFields []string
+	unordered    bool
+	batchSize    int
+	progress     func(ImportResult)
+	columns      []string
+	ignoreBlanks bool
+}
+
+// ImportOption configures Import behavior.
+type ImportOption func(*importConfig)
+
+// WithImportMode sets how documents are written. The default is ImportInsert.
+func WithImportMode(mode ImportMode) ImportOption {
+	return func(c *importConfig) {
+		c.mode = mode
+	}
+}
+
+// WithUpsertFields sets the fields, as dotted paths, that identify the existing
+// document in ImportUpsert and ImportMerge modes. The default is _id. Documents
+// that lack any of the fields are inserted; a field set to null is matched as
+// null.
+func WithUpsertFields(fields ...string) ImportOption {
+	return func(c *importConfig) {
+		c.upsertFields = fields
+	}
+}
+
+// WithUnordered continues past documents the server rejects, such as duplicate
+// keys, reporting them in ImportResult.Errors. By default the import stops at the
+// first rejected document and returns its error.
+func WithUnordered() ImportOption {
+	return func(c *importConfig) {
+		c.unordered = true
+	}
+}
+
+// WithBatchSize sets how many documents are sent to the server per write. The
+// default is 1000.
+func WithBatchSize(n int) ImportOption {
+	return func(c *importConfig) {
+		c.batchSize = n
+	}
+}
+
+// WithProgress calls fn with the running totals after each batch is written.
+func WithProgress(fn func(ImportResult)) ImportOption {
+	return func(c *importConfig) {
+		c.progress = fn
+	}
+}
+
+// WithColumns sets the CSV or TSV column names, for input without a header row.
+// Columns use the same syntax as a header, including types such as "age.int32()".
+func WithColumns(columns ...string) ImportOption {
+	return func(c *importConfig) {
+		c.columns = columns
+	}
+}
+
+// WithIgnoreBlanks omits fields for empty CSV or TSV cells instead of importing
+// them as empty strings.
+func WithIgnoreBlanks() ImportOption {
+	return func(c *importConfig) {
+		c.ignoreBlanks = true
+	}
+}
+
+// Import reads documents from r and writes them to the collection in batches.
+//
+// JSON input is parsed as Extended JSON, so typed values such as
+// {"$oid": "..."} or {"$date": "..."} are restored. CSV and TSV input takes its
+// field names from the header row, or from WithColumns. Dotted names such as
+// "address.city" create nested documents, and a name may carry a type, as in
+// mongoimport's --columnsHaveTypes: auto(), string(), int32(), int64(), double(),
+// decimal(), boolean(), date(<Go time layout>) and binary(base64|hex). Untyped
+// columns are auto(), which imports integers and floating-point numbers as numbers
+// and everything else as strings.
+//
+// In ImportInsert mode batches are written with insertMany(). The returned result
+// holds the counts so far even when an error is returned.
+func (c *Client) Import(ctx context.Context, database, collection string, r io.Reader, format ImportFormat, opts ...ImportOption) (*ImportResult, error) {
+	cfg := &importConfig{upsertFields: []string{"_id"}, batchSize: defaultImportBatchSize}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.batchSize < 1 {
+		return nil, fmt.Errorf("import batch size must be positive, got %d", cfg.batchSize)
+	}
+	if cfg.mode != ImportInsert && len(cfg.upsertFields) == 0 {
+		return nil, fmt.Errorf("upsert and merge imports require upsert fields")
+	}
+
+	next, err := newDocumentReader(r, format, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	batch := make([]bson.D, 0, cfg.batchSize)
+	for {
+		doc, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+		batch = append(batch, doc)
+		if len(batch) < cfg.batchSize {
+			continue
+		}
+		if err := c.importBatch(ctx, database, collection, batch, cfg, result); err != nil {
+			return result, err
+		}
+		batch = batch[:0]
+	}
+	if len(batch) > 0 {
+		if err := c.importBatch(ctx, database, collection, batch, cfg, result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// importBatch writes a batch of documents and adds its counts to result.
+func (c *Client) importBatch(ctx context.Context, database, collection string, batch []bson.D, cfg *importConfig, result *ImportResult) error {
+	ordered := !cfg.unordered
+	offset := result.Processed
+	result.Processed += int64(len(batch))
+
+	var err error
+	opType := types.OpUnknown
+	if cfg.mode == ImportInsert {
+		opType = types.OpInsertMany
+		op := &translator.Operation{
+			OpType:     opType,
+			Collection: collection,
+			Documents:  batch,
+			Ordered:    &ordered,
+		}
+		var inserted *executor.Result
+		inserted, err = executor.Execute(ctx, c.client, database, op, "insertMany()", executor.Options{})
+		if inserted != nil {
+			r := &Result{Operation: inserted.Operation, Value: inserted.Value}
+			if im, ok := r.InsertManyResult(); ok {
+				result.Inserted += int64(len(im.InsertedIDs))
+			}
+		}
+	} else {
+		var counts *executor.UpsertResult
+		counts, err = executor.BulkUpsert(ctx, c.client, database, collection, batch, cfg.upsertFields, cfg.mode == ImportMerge, ordered)
+		result.Inserted += counts.Inserted
+		result.Matched += counts.Matched
+		result.Modified += counts.Modified
+		result.Upserted += counts.Upserted
+	}
+
+	if err != nil {
+		err = newServerError(err, opType, collection)
+	}
+	var serverErr *ServerError
+	if errors.As(err, &serverErr) && len(serverErr.WriteErrors) > 0 {
+		for _, we := range serverErr.WriteErrors {
+			we.Index += int(offset)
+			result.Errors = append(result.Errors, we)
+		}
+		result.Failed += int64(len(serverErr.WriteErrors))
+		if !ordered && serverErr.WriteConcernError == nil {
+			err = nil
+		}
+	}
+
+	if cfg.progress != nil {
+		cfg.progress(*result)
+	}
+	return err
+}
+
+// newDocumentReader returns a function that reads the next document from r in
+// the given format, returning io.EOF after the last one.
+func newDocumentReader(r io.Reader, format ImportFormat, cfg *importConfig) (func() (bson.D, error), error) {
+	switch format {
+	case ImportJSON:
+		return newJSONReader(r)
+	case ImportCSV, ImportTSV:
+		return newCSVReader(r, format == ImportTSV, cfg)
+	default:
+		return nil, fmt.Errorf("unsupported import format %d", format)
+	}
+}
+
+// newJSONReader reads Extended JSON documents from a JSON array or a sequence of documents.
+func newJSONReader(r io.Reader) (func() (bson.D, error), error) {
+	br := bufio.NewReader(r)
+	isArray, err := startsWithArray(br)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(br)
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("read JSON array: %w", err)
+		}
+	}
+
+	index := 0
+	return func() (bson.D, error) {
+		if isArray && !dec.More() {
+			if _, err := dec.Token(); err != nil {
+				return nil, fmt.Errorf("read JSON array: %w", err)
+			}
+			return nil, io.EOF
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if err == io.EOF && !isArray {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("read document %d: %w", index, err)
+		}
+		var doc bson.D
+		if err := bson.UnmarshalExtJSON(raw, false, &doc); err != nil {
+			return nil, fmt.Errorf("parse document %d: %w", index, err)
+		}
+		index++
+		return doc, nil
+	}, nil
+}
+
+// startsWithArray reports whether the first non-space character of br opens a JSON array.
+func startsWithArray(br *bufio.Reader) (bool, error) {
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n', 0xEF, 0xBB, 0xBF: // whitespace and a UTF-8 byte order mark
+			continue
+		}
+		return b == '[', br.UnreadByte()
+	}
+}
+
+// typedColumnPattern matches a column name with a type, such as "age.int32()".
+var typedColumnPattern = regexp.MustCompile(`^(.+)\.(\w+)\((.*)\)$`)
+
+// importColumn is a parsed CSV or TSV column.
+type importColumn struct {
+	name    string   // column name as written, for error messages
+	path    []string // dotted field path
+	typ     string   // column type, such as "int32"
+	typeArg string   // argument of the type, such as a date layout
+}
+
+// parseColumn parses a column name with an optional type.
+func parseColumn(name string) (importColumn, error) {
+	col := importColumn{name: name, typ: "auto"}
+	field := name
+	if m := typedColumnPattern.FindStringSubmatch(name); m != nil {
+		field, col.typ, col.typeArg = m[1], m[2], m[3]
+	}
+	switch col.typ {
+	case "auto", "string", "int32", "int64", "double", "decimal", "boolean", "date":
+	case "binary":
+		if col.typeArg == "" {
+			col.typeArg = "base64"
+		}
+		if col.typeArg != "base64" && col.typeArg != "hex" {
+			return col, fmt.Errorf("column %q: unsupported binary encoding %q", name, col.typeArg)
+		}
+	default:
+		return col, fmt.Errorf("column %q: unsupported type %q", name, col.typ)
+	}
+	col.path = strings.Split(field, ".")
+	return col, nil
+}
+
+// newCSVReader reads one document per CSV or TSV row.
+func newCSVReader(r io.Reader, tabs bool, cfg *importConfig) (func() (bson.D, error), error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	if tabs {
+		cr.Comma = '\t'
+		cr.LazyQuotes = true
+	}
+
+	names := cfg.columns
+	if names == nil {
+		header, err := cr.Read()
+		if err == io.EOF {
+			return func() (bson.D, error) { return nil, io.EOF }, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read header: %w", err)
+		}
+		names = header
+	}
+	columns := make([]importColumn, len(names))
+	for i, name := range names {
+		col, err := parseColumn(name)
+		if err != nil {
+			return nil, err
+		}
+		columns[i] = col
+	}
+
+	return func() (bson.D, error) {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if len(record) > len(columns) {
+			return nil, fmt.Errorf("line %d: %d values for %d columns", line, len(record), len(columns))
+		}
+		doc := bson.D{}
+		for i, cell := range record {
+			if cell == "" && cfg.ignoreBlanks {
+				continue
+			}
+			value, err := columns[i].convert(cell)
+			if err != nil {
+				return nil, fmt.Errorf("line %d, column %q: %w", line, columns[i].name, err)
+			}
+			doc = setPath(doc, columns[i].path, value)
+		}
+		return doc, nil
+	}, nil
+}
+
+// convert parses a cell according to the column type.
+func (c importColumn) convert(cell string) (any, error) {
+	switch c.typ {
+	case "string":
+		return cell, nil
+	case "int32":
+		n, err := strconv.ParseInt(cell, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		return int32(n), nil
+	case "int64":
+		return strconv.ParseInt(cell, 10, 64)
+	case "double":
+		return strconv.ParseFloat(cell, 64)
+	case "decimal":
+		return bson.ParseDecimal128(cell)
+	case "boolean":
+		return strconv.ParseBool(cell)
+	case "date":
+		layout := c.typeArg
+		if layout == "" {
+			layout = time.RFC3339Nano
+		}
+		t, err := time.Parse(layout, cell)
+		if err != nil {
+			return nil, err
+		}
+		return bson.NewDateTimeFromTime(t), nil
+	case "binary":
+		decode := base64.StdEncoding.DecodeString
+		if c.typeArg == "hex" {
+			decode = hex.DecodeString
+		}
+		data, err := decode(cell)
+		if err != nil {
+			return nil, err
+		}
+		return bson.Binary{Data: data}, nil
+	}
+	return autoValue(cell), nil
+}
+
+// autoValue converts a cell to an int32, int64 or double if it is a number, and
+// leaves it a string otherwise.
+func autoValue(cell string) any {
+	if n, err := strconv.ParseInt(cell, 10, 64); err == nil {
+		if n >= math.MinInt32 && n <= math.MaxInt32 {
+			return int32(n)
+		}
+		return n
+	}
+	if f, err := strconv.ParseFloat(cell, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return f
+	}
+	return cell
+}
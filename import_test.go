@@ -0,0 +1,257 @@
+package gomongo_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestImportInputErrors(t *testing.T) {
+	// Input errors are reported before anything is written, so no server is needed.
+	gc := gomongo.NewClient(nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		input  string
+		format gomongo.ImportFormat
+		opts   []gomongo.ImportOption
+		errMsg string
+	}{
+		{
+			name:   "unknown column type",
+			input:  "name,age.uint8()\nalice,30\n",
+			format: gomongo.ImportCSV,
+			errMsg: `column "age.uint8()": unsupported type "uint8"`,
+		},
+		{
+			name:   "invalid typed cell",
+			input:  "name,age.int32()\nalice,thirty\n",
+			format: gomongo.ImportCSV,
+			errMsg: `line 2, column "age.int32()"`,
+		},
+		{
+			name:   "too many values",
+			input:  "a\tb\n1\t2\t3\n",
+			format: gomongo.ImportTSV,
+			errMsg: "line 2: 3 values for 2 columns",
+		},
+		{
+			name:   "invalid Extended JSON",
+			input:  `[{ "a": 1 }, { "b": { "$oid": "nope" } }]`,
+			format: gomongo.ImportJSON,
+			errMsg: "parse document 1",
+		},
+		{
+			name:   "invalid batch size",
+			input:  `{}`,
+			format: gomongo.ImportJSON,
+			opts:   []gomongo.ImportOption{gomongo.WithBatchSize(0)},
+			errMsg: "import batch size must be positive",
+		},
+		{
+			name:   "upsert without fields",
+			input:  `{}`,
+			format: gomongo.ImportJSON,
+			opts:   []gomongo.ImportOption{gomongo.WithImportMode(gomongo.ImportUpsert), gomongo.WithUpsertFields()},
+			errMsg: "require upsert fields",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := gc.Import(ctx, "db", "coll", strings.NewReader(tc.input), tc.format, tc.opts...)
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestImportJSON(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_import_json_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		array := `[
+			{ "_id": { "$oid": "507f1f77bcf86cd799439011" }, "name": "alice", "joined": { "$date": "2024-01-02T00:00:00Z" } },
+			{ "_id": 2, "name": "bob", "n": { "$numberLong": "5" } }
+		]`
+		result, err := gc.Import(ctx, dbName, "users", strings.NewReader(array), gomongo.ImportJSON)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.Processed)
+		require.Equal(t, int64(2), result.Inserted)
+
+		lines := "{\"_id\": 3, \"name\": \"carol\"}\n{\"_id\": 4, \"name\": \"dave\"}\n"
+		result, err = gc.Import(ctx, dbName, "users", strings.NewReader(lines), gomongo.ImportJSON)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.Inserted)
+
+		found, err := gc.Execute(ctx, dbName, `db.users.find({ name: "alice" })`)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(found.Value))
+		doc := found.Value[0].(bson.D)
+		require.IsType(t, bson.ObjectID{}, doc[0].Value)
+		require.IsType(t, bson.DateTime(0), doc[2].Value)
+
+		found, err = gc.Execute(ctx, dbName, `db.users.findOne({ _id: 2 })`)
+		require.NoError(t, err)
+		require.Equal(t, int64(5), found.Value[0].(bson.D)[2].Value)
+
+		count, err := gc.Execute(ctx, dbName, `db.users.countDocuments()`)
+		require.NoError(t, err)
+		require.Equal(t, int64(4), count.Value[0])
+	})
+}
+
+func TestImportCSV(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_import_csv_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		input := "_id,name,age.int32(),joined.date(2006-01-02),address.city,score,active.boolean()\n" +
+			"1,alice,30,2024-01-02,Paris,1.5,true\n" +
+			"2,bob,41,2023-06-30,,7,false\n"
+		result, err := gc.Import(ctx, dbName, "users", strings.NewReader(input), gomongo.ImportCSV, gomongo.WithIgnoreBlanks())
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.Inserted)
+
+		found, err := gc.Execute(ctx, dbName, `db.users.find().sort({ _id: 1 })`)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(found.Value))
+		require.Equal(t, bson.D{
+			{Key: "_id", Value: int32(1)},
+			{Key: "name", Value: "alice"},
+			{Key: "age", Value: int32(30)},
+			{Key: "joined", Value: bson.NewDateTimeFromTime(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))},
+			{Key: "address", Value: bson.D{{Key: "city", Value: "Paris"}}},
+			{Key: "score", Value: 1.5},
+			{Key: "active", Value: true},
+		}, found.Value[0])
+		// The blank city is omitted.
+		require.Equal(t, "_id,name,age,joined,score,active", strings.Join(keys(found.Value[1].(bson.D)), ","))
+
+		// Headerless TSV with columns given explicitly.
+		tsv := "3\tcarol\n4\tdave\n"
+		result, err = gc.Import(ctx, dbName, "users", strings.NewReader(tsv), gomongo.ImportTSV,
+			gomongo.WithColumns("_id.int64()", "name.string()"))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.Inserted)
+
+		found, err = gc.Execute(ctx, dbName, `db.users.findOne({ name: "dave" })`)
+		require.NoError(t, err)
+		require.Equal(t, int64(4), found.Value[0].(bson.D)[0].Value)
+	})
+}
+
+// keys returns the keys of a document in order.
+func keys(doc bson.D) []string {
+	names := make([]string, len(doc))
+	for i, elem := range doc {
+		names[i] = elem.Key
+	}
+	return names
+}
+
+func TestImportUpsertAndMerge(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_import_upsert_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		_, err := gc.Execute(ctx, dbName, `db.users.insertMany([{ email: "a@x.io", name: "alice", age: 30 }, { email: "b@x.io", name: "bob", age: 40 }])`)
+		require.NoError(t, err)
+
+		// Upsert replaces matching documents and inserts the rest.
+		input := `{"email": "a@x.io", "name": "Alice"}
+{"email": "c@x.io", "name": "carol"}`
+		result, err := gc.Import(ctx, dbName, "users", strings.NewReader(input), gomongo.ImportJSON,
+			gomongo.WithImportMode(gomongo.ImportUpsert), gomongo.WithUpsertFields("email"))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), result.Matched)
+		require.Equal(t, int64(1), result.Modified)
+		require.Equal(t, int64(1), result.Upserted)
+
+		found, err := gc.Execute(ctx, dbName, `db.users.findOne({ email: "a@x.io" }, { _id: 0 })`)
+		require.NoError(t, err)
+		require.Equal(t, bson.D{{Key: "email", Value: "a@x.io"}, {Key: "name", Value: "Alice"}}, found.Value[0])
+
+		// Merge sets the given fields and keeps the others.
+		input = `{"email": "b@x.io", "name": "Bob"}`
+		result, err = gc.Import(ctx, dbName, "users", strings.NewReader(input), gomongo.ImportJSON,
+			gomongo.WithImportMode(gomongo.ImportMerge), gomongo.WithUpsertFields("email"))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), result.Modified)
+
+		found, err = gc.Execute(ctx, dbName, `db.users.findOne({ email: "b@x.io" }, { _id: 0 })`)
+		require.NoError(t, err)
+		require.Equal(t, bson.D{{Key: "email", Value: "b@x.io"}, {Key: "name", Value: "Bob"}, {Key: "age", Value: int32(40)}}, found.Value[0])
+
+		count, err := gc.Execute(ctx, dbName, `db.users.countDocuments()`)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count.Value[0])
+
+		// A null key is a key: the second import matches the first instead of
+		// inserting a duplicate.
+		for _, name := range []string{"nobody", "Nobody"} {
+			input = fmt.Sprintf(`{"email": null, "name": %q}`, name)
+			_, err = gc.Import(ctx, dbName, "users", strings.NewReader(input), gomongo.ImportJSON,
+				gomongo.WithImportMode(gomongo.ImportUpsert), gomongo.WithUpsertFields("email"))
+			require.NoError(t, err)
+		}
+		found, err = gc.Execute(ctx, dbName, `db.users.find({ email: null }, { _id: 0 })`)
+		require.NoError(t, err)
+		require.Equal(t, []any{bson.D{{Key: "email", Value: nil}, {Key: "name", Value: "Nobody"}}}, found.Value)
+	})
+}
+
+func TestImportOrderedAndUnordered(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_import_ordered_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		input := `[{"_id": 1}, {"_id": 2}, {"_id": 1}, {"_id": 3}, {"_id": 4}]`
+
+		// Ordered imports stop at the first duplicate.
+		result, err := gc.Import(ctx, dbName, "ordered", strings.NewReader(input), gomongo.ImportJSON)
+		require.Error(t, err)
+		require.Equal(t, int64(2), result.Inserted)
+		require.Equal(t, int64(1), result.Failed)
+		require.Equal(t, 2, result.Errors[0].Index)
+		require.Equal(t, 11000, result.Errors[0].Code)
+
+		// Unordered imports continue past it, across batches.
+		var progress []gomongo.ImportResult
+		result, err = gc.Import(ctx, dbName, "unordered", strings.NewReader(input), gomongo.ImportJSON,
+			gomongo.WithUnordered(), gomongo.WithBatchSize(2), gomongo.WithProgress(func(r gomongo.ImportResult) {
+				progress = append(progress, r)
+			}))
+		require.NoError(t, err)
+		require.Equal(t, int64(5), result.Processed)
+		require.Equal(t, int64(4), result.Inserted)
+		require.Equal(t, int64(1), result.Failed)
+		require.Equal(t, 2, result.Errors[0].Index)
+
+		require.Equal(t, 3, len(progress))
+		require.Equal(t, int64(2), progress[0].Processed)
+		require.Equal(t, int64(4), progress[1].Processed)
+		require.Equal(t, int64(1), progress[1].Failed)
+		require.Equal(t, int64(5), progress[2].Processed)
+	})
+}
@@ -514,33 +514,30 @@ func executeValidate(ctx context.Context, client *mongo.Client, database string,
 }
 
 // executeLatencyStats executes a db.collection.latencyStats() command via $collStats aggregation.
-func executeLatencyStats(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
-	collection := client.Database(database).Collection(op.Collection)
-
-	pipeline := bson.A{
-		bson.D{{Key: "$collStats", Value: bson.D{
-			{Key: "latencyStats", Value: bson.D{{Key: "histograms", Value: true}}},
-		}}},
-	}
-
-	cursor, err := collection.Aggregate(ctx, pipeline)
+// MaxRows and MaxBytes apply as they do to aggregate().
+func executeLatencyStats(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, opts Options) (*Result, error) {
+	var truncated bool
+	values, err := collectDocuments(func(fn func(bson.D) error) error {
+		var err error
+		truncated, err = streamAggregate(ctx, client, database, latencyStatsAggregate(op), opts, fn)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("latencyStats failed: %w", err)
 	}
-	defer func() { _ = cursor.Close(ctx) }()
-
-	var values []any
-	for cursor.Next(ctx) {
-		var doc bson.D
-		if err := cursor.Decode(&doc); err != nil {
-			return nil, fmt.Errorf("decode failed: %w", err)
-		}
-		values = append(values, doc)
-	}
+	return &Result{Operation: types.OpLatencyStats, Value: values, Truncated: truncated}, nil
+}
 
-	if err := cursor.Err(); err != nil {
-		return nil, fmt.Errorf("cursor error: %w", err)
+// latencyStatsAggregate returns the $collStats aggregation that
+// db.collection.latencyStats() runs.
+func latencyStatsAggregate(op *translator.Operation) *translator.Operation {
+	return &translator.Operation{
+		OpType:     types.OpAggregate,
+		Collection: op.Collection,
+		Pipeline: bson.A{
+			bson.D{{Key: "$collStats", Value: bson.D{
+				{Key: "latencyStats", Value: bson.D{{Key: "histograms", Value: true}}},
+			}}},
+		},
 	}
-
-	return &Result{Operation: types.OpLatencyStats, Value: values}, nil
 }
@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/bytebase/gomongo/types"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// killTimeout bounds the currentOp/killOp round trip issued after cancellation.
+const killTimeout = 5 * time.Second
+
+// commentPrefix marks the comments gomongo attaches to tagged operations.
+const commentPrefix = "gomongo:"
+
+// commentOperations lists the operation types whose driver options accept a comment.
+var commentOperations = map[types.OperationType]bool{
+	types.OpFind:                   true,
+	types.OpFindOne:                true,
+	types.OpAggregate:              true,
+	types.OpCountDocuments:         true,
+	types.OpEstimatedDocumentCount: true,
+	types.OpDistinct:               true,
+	types.OpInsertOne:              true,
+	types.OpInsertMany:             true,
+	types.OpUpdateOne:              true,
+	types.OpUpdateMany:             true,
+	types.OpReplaceOne:             true,
+	types.OpDeleteOne:              true,
+	types.OpDeleteMany:             true,
+	types.OpFindOneAndUpdate:       true,
+	types.OpFindOneAndReplace:      true,
+	types.OpFindOneAndDelete:       true,
+}
+
+// withKillOnCancel tags the operation with a unique comment and runs it. If ctx is
+// cancelled before run returns, it kills the matching server-side operation.
+//
+// Cancelling the context only closes the client connection; the server keeps running
+// the operation until it finishes on its own. Operations that already carry a user
+// comment, or that do not accept one, are run without tagging.
+func withKillOnCancel(ctx context.Context, client *mongo.Client, op *translator.Operation, run func(*translator.Operation) error) error {
+	if op.Comment != nil || !commentOperations[op.OpType] {
+		return run(op)
+	}
+
+	tag := commentPrefix + uuid.NewString()
+	tagged := *op
+	tagged.Comment = tag
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-done:
+		case <-ctx.Done():
+			killTaggedOperations(client, tag)
+		}
+	}()
+
+	err := run(&tagged)
+	close(done)
+	<-stopped
+	return err
+}
+
+// killTaggedOperations kills every in-progress operation carrying the given comment.
+// This is best-effort: failures are ignored since the caller has already given up.
+func killTaggedOperations(client *mongo.Client, tag string) {
+	ctx, cancel := context.WithTimeout(context.Background(), killTimeout)
+	defer cancel()
+
+	admin := client.Database("admin")
+	inprog, err := runCommand(ctx, admin, bson.D{
+		{Key: "currentOp", Value: int32(1)},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "command.comment", Value: tag}},
+			bson.D{{Key: "cursor.originatingCommand.comment", Value: tag}},
+		}},
+	})
+	if err != nil {
+		return
+	}
+
+	ops, _ := findField(inprog, "inprog").(bson.A)
+	for _, entry := range ops {
+		doc, ok := entry.(bson.D)
+		if !ok {
+			continue
+		}
+		opid := findField(doc, "opid")
+		if opid == nil {
+			continue
+		}
+		_, _ = runCommand(ctx, admin, bson.D{
+			{Key: "killOp", Value: int32(1)},
+			{Key: "op", Value: opid},
+		})
+	}
+}
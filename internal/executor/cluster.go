@@ -0,0 +1,442 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/bytebase/gomongo/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Replica set member states reported by replSetGetStatus.
+const (
+	memberStatePrimary = 1
+	memberStateArbiter = 7
+)
+
+// activeMongosWindow is how recently a mongos must have pinged the config
+// servers to be reported as active by sh.status(), as in mongosh.
+const activeMongosWindow = 60 * time.Second
+
+// executeRsStatus executes an rs.status() command.
+func executeRsStatus(ctx context.Context, client *mongo.Client) (*Result, error) {
+	result, err := runCommand(ctx, client.Database("admin"), bson.D{{Key: "replSetGetStatus", Value: int32(1)}})
+	if err != nil {
+		return nil, fmt.Errorf("rs.status() failed: %w", err)
+	}
+	return &Result{Operation: types.OpRsStatus, Value: []any{result}}, nil
+}
+
+// executeRsConf executes an rs.conf() command, returning the replica set configuration document.
+func executeRsConf(ctx context.Context, client *mongo.Client) (*Result, error) {
+	result, err := runCommand(ctx, client.Database("admin"), bson.D{{Key: "replSetGetConfig", Value: int32(1)}})
+	if err != nil {
+		return nil, fmt.Errorf("rs.conf() failed: %w", err)
+	}
+	config, _ := findField(result, "config").(bson.D)
+	return &Result{Operation: types.OpRsConf, Value: []any{config}}, nil
+}
+
+// executeRsPrintReplicationInfo executes an rs.printReplicationInfo() command.
+// The oplog summary uses the same fields as mongosh's db.getReplicationInfo().
+func executeRsPrintReplicationInfo(ctx context.Context, client *mongo.Client) (*Result, error) {
+	local := client.Database("local")
+	stats, err := runCollStats(ctx, client, "local", "oplog.rs")
+	if err != nil {
+		return nil, fmt.Errorf("rs.printReplicationInfo() failed: replication not detected: %w", err)
+	}
+	maxSize, _ := translator.ToInt64(findField(stats, "maxSize"))
+	size, _ := translator.ToInt64(findField(stats, "size"))
+
+	first, err := oplogEntryTime(ctx, local.Collection("oplog.rs"), 1)
+	if err != nil {
+		return nil, fmt.Errorf("rs.printReplicationInfo() failed: %w", err)
+	}
+	last, err := oplogEntryTime(ctx, local.Collection("oplog.rs"), -1)
+	if err != nil {
+		return nil, fmt.Errorf("rs.printReplicationInfo() failed: %w", err)
+	}
+
+	timeDiff := int64(last.Sub(first) / time.Second)
+	result := bson.D{
+		{Key: "logSizeMB", Value: bytesToMB(maxSize)},
+		{Key: "usedMB", Value: bytesToMB(size)},
+		{Key: "timeDiff", Value: timeDiff},
+		{Key: "timeDiffHours", Value: math.Round(float64(timeDiff)/36) / 100},
+		{Key: "tFirst", Value: bson.NewDateTimeFromTime(first)},
+		{Key: "tLast", Value: bson.NewDateTimeFromTime(last)},
+		{Key: "now", Value: bson.NewDateTimeFromTime(time.Now())},
+	}
+	return &Result{Operation: types.OpRsPrintReplicationInfo, Value: []any{result}}, nil
+}
+
+// oplogEntryTime returns the timestamp of the first (order 1) or last (order -1) oplog entry.
+func oplogEntryTime(ctx context.Context, oplog *mongo.Collection, order int32) (time.Time, error) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "$natural", Value: order}}).
+		SetProjection(bson.D{{Key: "ts", Value: 1}})
+
+	var entry bson.D
+	if err := oplog.FindOne(ctx, bson.D{}, opts).Decode(&entry); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return time.Time{}, fmt.Errorf("oplog is empty")
+		}
+		return time.Time{}, err
+	}
+	ts, ok := findField(entry, "ts").(bson.Timestamp)
+	if !ok {
+		return time.Time{}, fmt.Errorf("oplog entry has no timestamp")
+	}
+	return time.Unix(int64(ts.T), 0).UTC(), nil
+}
+
+// bytesToMB converts a byte count to megabytes, rounded up to two decimals like mongosh.
+func bytesToMB(n int64) float64 {
+	return math.Ceil(float64(n)/(1024*1024)*100) / 100
+}
+
+// executeRsPrintSecondaryReplicationInfo executes an rs.printSecondaryReplicationInfo() command.
+// Each non-primary, non-arbiter member is reported with its last applied optime and how far
+// it lags behind the primary. Without a primary, lag is measured against the freshest member.
+func executeRsPrintSecondaryReplicationInfo(ctx context.Context, client *mongo.Client) (*Result, error) {
+	status, err := runCommand(ctx, client.Database("admin"), bson.D{{Key: "replSetGetStatus", Value: int32(1)}})
+	if err != nil {
+		return nil, fmt.Errorf("rs.printSecondaryReplicationInfo() failed: %w", err)
+	}
+	members, _ := findField(status, "members").(bson.A)
+
+	var reference bson.DateTime
+	havePrimary := false
+	for _, m := range members {
+		member, _ := m.(bson.D)
+		optime, _ := findField(member, "optimeDate").(bson.DateTime)
+		state, _ := translator.ToInt64(findField(member, "state"))
+		if state == memberStatePrimary {
+			reference = optime
+			havePrimary = true
+			break
+		}
+		if optime > reference {
+			reference = optime
+		}
+	}
+
+	values := []any{}
+	for _, m := range members {
+		member, _ := m.(bson.D)
+		state, _ := translator.ToInt64(findField(member, "state"))
+		if state == memberStatePrimary || state == memberStateArbiter {
+			continue
+		}
+		optime, _ := findField(member, "optimeDate").(bson.DateTime)
+		lag := int64(reference.Time().Sub(optime.Time()) / time.Second)
+		values = append(values, bson.D{
+			{Key: "source", Value: findField(member, "name")},
+			{Key: "state", Value: findField(member, "stateStr")},
+			{Key: "syncedTo", Value: optime},
+			{Key: "replLagSecs", Value: lag},
+			{Key: "behindPrimary", Value: havePrimary},
+		})
+	}
+	return &Result{Operation: types.OpRsPrintSecondaryReplicationInfo, Value: values}, nil
+}
+
+// requireMongos returns an error unless the client is connected to a mongos router.
+func requireMongos(ctx context.Context, client *mongo.Client, method string) error {
+	if _, err := runCommand(ctx, client.Database("admin"), bson.D{{Key: "isdbgrid", Value: int32(1)}}); err != nil {
+		return fmt.Errorf("%s must be run against a mongos: %w", method, err)
+	}
+	return nil
+}
+
+// executeShStatus executes an sh.status() command.
+// The report is built from the config database, like mongosh's sh.status().
+func executeShStatus(ctx context.Context, client *mongo.Client) (*Result, error) {
+	if err := requireMongos(ctx, client, "sh.status()"); err != nil {
+		return nil, err
+	}
+	config := client.Database("config")
+
+	var version bson.D
+	err := config.Collection("version").FindOne(ctx, bson.D{}).Decode(&version)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("sh.status() failed: %w", err)
+	}
+
+	shards, err := findAll(ctx, config.Collection("shards"), bson.D{}, bson.D{{Key: "_id", Value: 1}})
+	if err != nil {
+		return nil, fmt.Errorf("sh.status() failed: %w", err)
+	}
+
+	mongoses, err := activeMongoses(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("sh.status() failed: %w", err)
+	}
+
+	balancer, err := runCommand(ctx, client.Database("admin"), bson.D{{Key: "balancerStatus", Value: int32(1)}})
+	if err != nil {
+		return nil, fmt.Errorf("sh.status() failed: %w", err)
+	}
+	mode, _ := findField(balancer, "mode").(string)
+	running, _ := findField(balancer, "inBalancerRound").(bool)
+
+	databases, err := findAll(ctx, config.Collection("databases"), bson.D{}, bson.D{{Key: "_id", Value: 1}})
+	if err != nil {
+		return nil, fmt.Errorf("sh.status() failed: %w", err)
+	}
+	dbValues := make(bson.A, 0, len(databases))
+	for _, db := range databases {
+		name, _ := findField(db, "_id").(string)
+		collections, err := shardedCollections(ctx, config, name)
+		if err != nil {
+			return nil, fmt.Errorf("sh.status() failed: %w", err)
+		}
+		dbValues = append(dbValues, bson.D{
+			{Key: "database", Value: db},
+			{Key: "collections", Value: collections},
+		})
+	}
+
+	result := bson.D{
+		{Key: "shardingVersion", Value: version},
+		{Key: "shards", Value: docsToArray(shards)},
+		{Key: "activeMongoses", Value: mongoses},
+		{Key: "balancer", Value: bson.D{
+			{Key: "currentlyEnabled", Value: mode != "" && mode != "off"},
+			{Key: "currentlyRunning", Value: running},
+			{Key: "mode", Value: mode},
+		}},
+		{Key: "databases", Value: dbValues},
+	}
+	return &Result{Operation: types.OpShStatus, Value: []any{result}}, nil
+}
+
+// activeMongoses counts the recently active mongos routers by version.
+func activeMongoses(ctx context.Context, config *mongo.Database) (bson.A, error) {
+	cutoff := bson.NewDateTimeFromTime(time.Now().Add(-activeMongosWindow))
+	cursor, err := config.Collection("mongos").Aggregate(ctx, bson.A{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "ping", Value: bson.D{{Key: "$gte", Value: cutoff}}}}}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$mongoVersion"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: int32(1)}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "mongoVersion", Value: "$_id"},
+			{Key: "count", Value: 1},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var docs []bson.D
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docsToArray(docs), nil
+}
+
+// shardedCollections returns the sharded collections of a database with their shard key
+// and per-shard chunk counts.
+func shardedCollections(ctx context.Context, config *mongo.Database, database string) (bson.A, error) {
+	filter := bson.D{
+		{Key: "_id", Value: bson.Regex{Pattern: "^" + regexp.QuoteMeta(database) + `\.`}},
+		{Key: "dropped", Value: bson.D{{Key: "$ne", Value: true}}},
+	}
+	collections, err := findAll(ctx, config.Collection("collections"), filter, bson.D{{Key: "_id", Value: 1}})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(bson.A, 0, len(collections))
+	for _, coll := range collections {
+		chunks, err := chunksPerShard(ctx, config, coll)
+		if err != nil {
+			return nil, err
+		}
+		noBalance, _ := findField(coll, "noBalance").(bool)
+		unique, _ := findField(coll, "unique").(bool)
+		values = append(values, bson.D{
+			{Key: "collection", Value: findField(coll, "_id")},
+			{Key: "shardKey", Value: findField(coll, "key")},
+			{Key: "unique", Value: unique},
+			{Key: "balancing", Value: !noBalance},
+			{Key: "chunks", Value: chunks},
+		})
+	}
+	return values, nil
+}
+
+// chunkFilter matches the chunks of a sharded collection. Since MongoDB 5.0 chunks
+// reference the collection by UUID; older servers use the namespace.
+func chunkFilter(coll bson.D) bson.D {
+	if uuid := findField(coll, "uuid"); uuid != nil {
+		return bson.D{{Key: "uuid", Value: uuid}}
+	}
+	return bson.D{{Key: "ns", Value: findField(coll, "_id")}}
+}
+
+// chunksPerShard counts the chunks of a sharded collection on each shard.
+func chunksPerShard(ctx context.Context, config *mongo.Database, coll bson.D) (bson.A, error) {
+	cursor, err := config.Collection("chunks").Aggregate(ctx, bson.A{
+		bson.D{{Key: "$match", Value: chunkFilter(coll)}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$shard"},
+			{Key: "nChunks", Value: bson.D{{Key: "$sum", Value: int32(1)}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "shard", Value: "$_id"},
+			{Key: "nChunks", Value: 1},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var docs []bson.D
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docsToArray(docs), nil
+}
+
+// executeGetShardDistribution executes a db.collection.getShardDistribution() command.
+// Each shard is reported with its data size, document count and chunk count, followed
+// by cluster-wide totals, as in mongosh.
+func executeGetShardDistribution(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	if err := requireMongos(ctx, client, "getShardDistribution()"); err != nil {
+		return nil, err
+	}
+	config := client.Database("config")
+	ns := database + "." + op.Collection
+
+	var coll bson.D
+	err := config.Collection("collections").FindOne(ctx, bson.D{
+		{Key: "_id", Value: ns},
+		{Key: "dropped", Value: bson.D{{Key: "$ne", Value: true}}},
+	}).Decode(&coll)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("collection %s is not sharded", ns)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getShardDistribution() failed: %w", err)
+	}
+
+	cursor, err := client.Database(database).Collection(op.Collection).Aggregate(ctx, bson.A{
+		bson.D{{Key: "$collStats", Value: bson.D{{Key: "storageStats", Value: bson.D{}}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "shard", Value: 1}}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getShardDistribution() failed: %w", err)
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+	var shardStats []bson.D
+	if err := cursor.All(ctx, &shardStats); err != nil {
+		return nil, fmt.Errorf("getShardDistribution() failed: %w", err)
+	}
+
+	type shardInfo struct {
+		shard, host          any
+		size, count, nChunks int64
+	}
+	infos := make([]shardInfo, 0, len(shardStats))
+	var totalSize, totalCount, totalChunks int64
+	for _, stats := range shardStats {
+		shard, _ := findField(stats, "shard").(string)
+		storage, _ := findField(stats, "storageStats").(bson.D)
+		size, _ := translator.ToInt64(findField(storage, "size"))
+		count, _ := translator.ToInt64(findField(storage, "count"))
+
+		var shardDoc bson.D
+		if err := config.Collection("shards").FindOne(ctx, bson.D{{Key: "_id", Value: shard}}).Decode(&shardDoc); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("getShardDistribution() failed: %w", err)
+		}
+		nChunks, err := config.Collection("chunks").CountDocuments(ctx, append(chunkFilter(coll), bson.E{Key: "shard", Value: shard}))
+		if err != nil {
+			return nil, fmt.Errorf("getShardDistribution() failed: %w", err)
+		}
+
+		infos = append(infos, shardInfo{shard: shard, host: findField(shardDoc, "host"), size: size, count: count, nChunks: nChunks})
+		totalSize += size
+		totalCount += count
+		totalChunks += nChunks
+	}
+
+	shards := make(bson.A, 0, len(infos))
+	for _, info := range infos {
+		shards = append(shards, bson.D{
+			{Key: "shard", Value: info.shard},
+			{Key: "host", Value: info.host},
+			{Key: "data", Value: info.size},
+			{Key: "docs", Value: info.count},
+			{Key: "chunks", Value: info.nChunks},
+			{Key: "estimatedDataPerChunk", Value: perUnit(info.size, info.nChunks)},
+			{Key: "estimatedDocsPerChunk", Value: perUnit(info.count, info.nChunks)},
+			{Key: "dataPercent", Value: percent(info.size, totalSize)},
+			{Key: "docsPercent", Value: percent(info.count, totalCount)},
+		})
+	}
+
+	result := bson.D{
+		{Key: "shards", Value: shards},
+		{Key: "totals", Value: bson.D{
+			{Key: "data", Value: totalSize},
+			{Key: "docs", Value: totalCount},
+			{Key: "chunks", Value: totalChunks},
+		}},
+	}
+	return &Result{Operation: types.OpGetShardDistribution, Value: []any{result}}, nil
+}
+
+// perUnit divides n evenly across units, returning 0 when there are none.
+func perUnit(n, units int64) int64 {
+	if units == 0 {
+		return 0
+	}
+	return n / units
+}
+
+// percent returns part as a percentage of total, rounded to two decimals.
+func percent(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
+
+// findAll returns all documents in the collection matching the filter, in sort order.
+func findAll(ctx context.Context, coll *mongo.Collection, filter, sort bson.D) ([]bson.D, error) {
+	cursor, err := coll.Find(ctx, filter, options.Find().SetSort(sort))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var docs []bson.D
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// docsToArray converts decoded documents to a bson.A, so empty results are never nil.
+func docsToArray(docs []bson.D) bson.A {
+	values := make(bson.A, len(docs))
+	for i, doc := range docs {
+		values[i] = doc
+	}
+	return values
+}
@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/bytebase/gomongo/internal/translator"
@@ -31,8 +32,97 @@ func computeEffectiveLimit(opLimit, maxRows *int64) *int64 {
 	return maxRows
 }
 
+// rowLimit returns the limit to send to the server and whether maxRows, rather
+// than the statement's own limit, is the binding one. In that case one extra row
+// is requested, so that a result cut short by maxRows can be told apart from one
+// that fits exactly.
+func rowLimit(opLimit, maxRows *int64) (*int64, bool) {
+	if maxRows == nil || (opLimit != nil && *opLimit <= *maxRows) {
+		return computeEffectiveLimit(opLimit, maxRows), false
+	}
+	probe := *maxRows + 1
+	return &probe, true
+}
+
+// resultCap stops reading a result once it holds maxRows values or the next
+// value would take it past maxBytes bytes of raw BSON. Nil limits are not enforced.
+type resultCap struct {
+	maxRows   *int64
+	maxBytes  *int64
+	rows      int64
+	bytes     int64
+	truncated bool // a value was left out
+}
+
+// admit reports whether a value of size bytes fits, counting it if it does.
+func (c *resultCap) admit(size int) bool {
+	if (c.maxRows != nil && c.rows >= *c.maxRows) || (c.maxBytes != nil && c.bytes+int64(size) > *c.maxBytes) {
+		c.truncated = true
+		return false
+	}
+	c.rows++
+	c.bytes += int64(size)
+	return true
+}
+
+// drainCursor calls fn for each document of the cursor and closes it. If rc is
+// not nil, reading stops at the first document it does not admit.
+func drainCursor(ctx context.Context, cursor *mongo.Cursor, rc *resultCap, fn func(bson.D) error) error {
+	defer func() { _ = cursor.Close(ctx) }()
+
+	for cursor.Next(ctx) {
+		if rc != nil && !rc.admit(len(cursor.Current)) {
+			return nil
+		}
+		var doc bson.D
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("decode failed: %w", err)
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("cursor error: %w", err)
+	}
+	return nil
+}
+
+// collectDocuments runs a streaming operation and collects its documents.
+func collectDocuments(stream func(fn func(bson.D) error) error) ([]any, error) {
+	var values []any
+	err := stream(func(doc bson.D) error {
+		values = append(values, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
 // executeFind executes a find operation.
-func executeFind(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, maxRows *int64) (*Result, error) {
+func executeFind(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, opts Options) (*Result, error) {
+	var truncated bool
+	values, err := collectDocuments(func(fn func(bson.D) error) error {
+		var err error
+		truncated, err = streamFind(ctx, client, database, op, opts, fn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Operation: types.OpFind,
+		Value:     values,
+		Truncated: truncated,
+	}, nil
+}
+
+// streamFind executes a find operation, calling fn for each document as it is read.
+// It reports whether MaxRows or MaxBytes cut the result short.
+func streamFind(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, execOpts Options, fn func(bson.D) error) (bool, error) {
 	collection := client.Database(database).Collection(op.Collection)
 
 	filter := op.Filter
@@ -45,9 +135,9 @@ func executeFind(ctx context.Context, client *mongo.Client, database string, op
 		opts.SetSort(op.Sort)
 	}
 	// Compute effective limit: min(op.Limit, maxRows)
-	effectiveLimit := computeEffectiveLimit(op.Limit, maxRows)
-	if effectiveLimit != nil {
-		opts.SetLimit(*effectiveLimit)
+	limit, capped := rowLimit(op.Limit, execOpts.MaxRows)
+	if limit != nil {
+		opts.SetLimit(*limit)
 	}
 	if op.Skip != nil {
 		opts.SetSkip(*op.Skip)
@@ -64,6 +154,9 @@ func executeFind(ctx context.Context, client *mongo.Client, database string, op
 	if op.Min != nil {
 		opts.SetMin(op.Min)
 	}
+	if op.Comment != nil {
+		opts.SetComment(op.Comment)
+	}
 
 	// Apply maxTimeMS using context timeout.
 	// Note: MongoDB Go driver v2 removed SetMaxTime() from options. The recommended
@@ -77,27 +170,15 @@ func executeFind(ctx context.Context, client *mongo.Client, database string, op
 
 	cursor, err := collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, fmt.Errorf("find failed: %w", err)
-	}
-	defer func() { _ = cursor.Close(ctx) }()
-
-	var values []any
-	for cursor.Next(ctx) {
-		var doc bson.D
-		if err := cursor.Decode(&doc); err != nil {
-			return nil, fmt.Errorf("decode failed: %w", err)
-		}
-		values = append(values, doc)
+		return false, fmt.Errorf("find failed: %w", err)
 	}
 
-	if err := cursor.Err(); err != nil {
-		return nil, fmt.Errorf("cursor error: %w", err)
+	rc := &resultCap{maxBytes: execOpts.MaxBytes}
+	if capped {
+		rc.maxRows = execOpts.MaxRows
 	}
-
-	return &Result{
-		Operation: types.OpFind,
-		Value:     values,
-	}, nil
+	err = drainCursor(ctx, cursor, rc, fn)
+	return rc.truncated, err
 }
 
 // executeFindOne executes a findOne operation.
@@ -128,6 +209,9 @@ func executeFindOne(ctx context.Context, client *mongo.Client, database string,
 	if op.Min != nil {
 		opts.SetMin(op.Min)
 	}
+	if op.Comment != nil {
+		opts.SetComment(op.Comment)
+	}
 
 	// Apply maxTimeMS using context timeout (see comment in executeFind for details).
 	if op.MaxTimeMS != nil {
@@ -155,18 +239,48 @@ func executeFindOne(ctx context.Context, client *mongo.Client, database string,
 }
 
 // executeAggregate executes an aggregation pipeline.
-func executeAggregate(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+func executeAggregate(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, opts Options) (*Result, error) {
+	var truncated bool
+	values, err := collectDocuments(func(fn func(bson.D) error) error {
+		var err error
+		truncated, err = streamAggregate(ctx, client, database, op, opts, fn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Operation: types.OpAggregate,
+		Value:     values,
+		Truncated: truncated,
+	}, nil
+}
+
+// streamAggregate executes an aggregation pipeline, calling fn for each document as it is read.
+// It reports whether MaxRows or MaxBytes cut the result short.
+func streamAggregate(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, execOpts Options, fn func(bson.D) error) (bool, error) {
 	collection := client.Database(database).Collection(op.Collection)
 
 	pipeline := op.Pipeline
 	if pipeline == nil {
 		pipeline = bson.A{}
 	}
+	// Let the server stop after MaxRows documents, requesting one more to detect
+	// truncation. A pipeline that ends in $out or $merge writes its output to a
+	// collection and returns nothing, so a $limit would only cut what it writes.
+	rc := &resultCap{maxBytes: execOpts.MaxBytes}
+	if execOpts.MaxRows != nil && !writesOutput(pipeline) {
+		rc.maxRows = execOpts.MaxRows
+		pipeline = append(slices.Clone(pipeline), bson.D{{Key: "$limit", Value: *execOpts.MaxRows + 1}})
+	}
 
 	opts := options.Aggregate()
 	if op.Hint != nil {
 		opts.SetHint(op.Hint)
 	}
+	if op.Comment != nil {
+		opts.SetComment(op.Comment)
+	}
 
 	// Apply maxTimeMS using context timeout (see comment in executeFind for details).
 	if op.MaxTimeMS != nil {
@@ -177,56 +291,54 @@ func executeAggregate(ctx context.Context, client *mongo.Client, database string
 
 	cursor, err := collection.Aggregate(ctx, pipeline, opts)
 	if err != nil {
-		return nil, fmt.Errorf("aggregate failed: %w", err)
+		return false, fmt.Errorf("aggregate failed: %w", err)
 	}
-	defer func() { _ = cursor.Close(ctx) }()
+	err = drainCursor(ctx, cursor, rc, fn)
+	return rc.truncated, err
+}
 
-	var values []any
-	for cursor.Next(ctx) {
-		var doc bson.D
-		if err := cursor.Decode(&doc); err != nil {
-			return nil, fmt.Errorf("decode failed: %w", err)
-		}
-		values = append(values, doc)
+// writesOutput reports whether a pipeline ends in a $out or $merge stage.
+func writesOutput(pipeline bson.A) bool {
+	if len(pipeline) == 0 {
+		return false
 	}
-
-	if err := cursor.Err(); err != nil {
-		return nil, fmt.Errorf("cursor error: %w", err)
+	stage, ok := pipeline[len(pipeline)-1].(bson.D)
+	if !ok || len(stage) == 0 {
+		return false
 	}
+	return stage[0].Key == "$out" || stage[0].Key == "$merge"
+}
 
+// executeGetIndexes executes a db.collection.getIndexes() command.
+func executeGetIndexes(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, opts Options) (*Result, error) {
+	var truncated bool
+	values, err := collectDocuments(func(fn func(bson.D) error) error {
+		var err error
+		truncated, err = streamGetIndexes(ctx, client, database, op, opts, fn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 	return &Result{
-		Operation: types.OpAggregate,
+		Operation: types.OpGetIndexes,
 		Value:     values,
+		Truncated: truncated,
 	}, nil
 }
 
-// executeGetIndexes executes a db.collection.getIndexes() command.
-func executeGetIndexes(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+// streamGetIndexes lists a collection's indexes, calling fn for each index document.
+// It reports whether MaxRows or MaxBytes cut the result short.
+func streamGetIndexes(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, execOpts Options, fn func(bson.D) error) (bool, error) {
 	collection := client.Database(database).Collection(op.Collection)
 
 	cursor, err := collection.Indexes().List(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("list indexes failed: %w", err)
-	}
-	defer func() { _ = cursor.Close(ctx) }()
-
-	var values []any
-	for cursor.Next(ctx) {
-		var doc bson.D
-		if err := cursor.Decode(&doc); err != nil {
-			return nil, fmt.Errorf("decode failed: %w", err)
-		}
-		values = append(values, doc)
-	}
-
-	if err := cursor.Err(); err != nil {
-		return nil, fmt.Errorf("cursor error: %w", err)
+		return false, fmt.Errorf("list indexes failed: %w", err)
 	}
-
-	return &Result{
-		Operation: types.OpGetIndexes,
-		Value:     values,
-	}, nil
+	rc := &resultCap{maxRows: execOpts.MaxRows, maxBytes: execOpts.MaxBytes}
+	err = drainCursor(ctx, cursor, rc, fn)
+	return rc.truncated, err
 }
 
 // executeCountDocuments executes a db.collection.countDocuments() command.
@@ -243,13 +355,16 @@ func executeCountDocuments(ctx context.Context, client *mongo.Client, database s
 		opts.SetHint(op.Hint)
 	}
 	// Compute effective limit: min(op.Limit, maxRows)
-	effectiveLimit := computeEffectiveLimit(op.Limit, maxRows)
-	if effectiveLimit != nil {
-		opts.SetLimit(*effectiveLimit)
+	limit, capped := rowLimit(op.Limit, maxRows)
+	if limit != nil {
+		opts.SetLimit(*limit)
 	}
 	if op.Skip != nil {
 		opts.SetSkip(*op.Skip)
 	}
+	if op.Comment != nil {
+		opts.SetComment(op.Comment)
+	}
 
 	// Apply maxTimeMS using context timeout (see comment in executeFind for details).
 	if op.MaxTimeMS != nil {
@@ -263,9 +378,15 @@ func executeCountDocuments(ctx context.Context, client *mongo.Client, database s
 		return nil, fmt.Errorf("count documents failed: %w", err)
 	}
 
+	truncated := capped && count > *maxRows
+	if truncated {
+		count = *maxRows
+	}
+
 	return &Result{
 		Operation: types.OpCountDocuments,
 		Value:     []any{count},
+		Truncated: truncated,
 	}, nil
 }
 
@@ -280,7 +401,12 @@ func executeEstimatedDocumentCount(ctx context.Context, client *mongo.Client, da
 		defer cancel()
 	}
 
-	count, err := collection.EstimatedDocumentCount(ctx)
+	opts := options.EstimatedDocumentCount()
+	if op.Comment != nil {
+		opts.SetComment(op.Comment)
+	}
+
+	count, err := collection.EstimatedDocumentCount(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("estimated document count failed: %w", err)
 	}
@@ -307,7 +433,12 @@ func executeDistinct(ctx context.Context, client *mongo.Client, database string,
 		defer cancel()
 	}
 
-	result := collection.Distinct(ctx, op.DistinctField, filter)
+	opts := options.Distinct()
+	if op.Comment != nil {
+		opts.SetComment(op.Comment)
+	}
+
+	result := collection.Distinct(ctx, op.DistinctField, filter, opts)
 	if err := result.Err(); err != nil {
 		return nil, fmt.Errorf("distinct failed: %w", err)
 	}
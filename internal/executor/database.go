@@ -50,7 +50,7 @@ func executeGetCollectionNames(ctx context.Context, client *mongo.Client, databa
 }
 
 // executeGetCollectionInfos executes a db.getCollectionInfos() command.
-func executeGetCollectionInfos(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+func executeGetCollectionInfos(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, execOpts Options) (*Result, error) {
 	filter := op.Filter
 	if filter == nil {
 		filter = bson.D{}
@@ -68,6 +68,80 @@ func executeGetCollectionInfos(ctx context.Context, client *mongo.Client, databa
 	if err != nil {
 		return nil, fmt.Errorf("list collections failed: %w", err)
 	}
+
+	rc := &resultCap{maxRows: execOpts.MaxRows, maxBytes: execOpts.MaxBytes}
+	values, err := collectDocuments(func(fn func(bson.D) error) error {
+		return drainCursor(ctx, cursor, rc, fn)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Operation: types.OpGetCollectionInfos,
+		Value:     values,
+		Truncated: rc.truncated,
+	}, nil
+}
+
+// executeSetProfilingLevel executes a db.setProfilingLevel() command.
+func executeSetProfilingLevel(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	command := bson.D{{Key: "profile", Value: *op.ProfilingLevel}}
+	if op.SlowMS != nil {
+		command = append(command, bson.E{Key: "slowms", Value: *op.SlowMS})
+	}
+	if op.SampleRate != nil {
+		command = append(command, bson.E{Key: "sampleRate", Value: *op.SampleRate})
+	}
+	if op.ProfileFilter != nil {
+		command = append(command, bson.E{Key: "filter", Value: op.ProfileFilter})
+	}
+
+	result, err := runCommand(ctx, client.Database(database), command)
+	if err != nil {
+		return nil, fmt.Errorf("setProfilingLevel failed: %w", err)
+	}
+	return &Result{Operation: types.OpSetProfilingLevel, Value: []any{result}}, nil
+}
+
+// executeGetProfilingLevel executes a db.getProfilingLevel() command.
+func executeGetProfilingLevel(ctx context.Context, client *mongo.Client, database string) (*Result, error) {
+	result, err := runCommand(ctx, client.Database(database), bson.D{{Key: "profile", Value: int32(-1)}})
+	if err != nil {
+		return nil, fmt.Errorf("getProfilingLevel failed: %w", err)
+	}
+	level := findField(result, "was")
+	if level == nil {
+		return nil, fmt.Errorf("getProfilingLevel failed: was field missing in profile result")
+	}
+	return &Result{Operation: types.OpGetProfilingLevel, Value: []any{level}}, nil
+}
+
+// executeGetProfilingStatus executes a db.getProfilingStatus() command.
+func executeGetProfilingStatus(ctx context.Context, client *mongo.Client, database string) (*Result, error) {
+	result, err := runCommand(ctx, client.Database(database), bson.D{{Key: "profile", Value: int32(-1)}})
+	if err != nil {
+		return nil, fmt.Errorf("getProfilingStatus failed: %w", err)
+	}
+	return &Result{Operation: types.OpGetProfilingStatus, Value: []any{result}}, nil
+}
+
+// showProfileLimit is the number of profile entries returned by show profile, matching mongosh.
+const showProfileLimit = 5
+
+// executeShowProfile executes a show profile command.
+// Like mongosh, it returns the most recent system.profile entries that took at least 1ms.
+func executeShowProfile(ctx context.Context, client *mongo.Client, database string) (*Result, error) {
+	collection := client.Database(database).Collection("system.profile")
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "$natural", Value: -1}}).
+		SetLimit(showProfileLimit)
+
+	cursor, err := collection.Find(ctx, bson.D{{Key: "millis", Value: bson.D{{Key: "$gt", Value: 0}}}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("show profile failed: %w", err)
+	}
 	defer func() { _ = cursor.Close(ctx) }()
 
 	var values []any
@@ -84,7 +158,7 @@ func executeGetCollectionInfos(ctx context.Context, client *mongo.Client, databa
 	}
 
 	return &Result{
-		Operation: types.OpGetCollectionInfos,
+		Operation: types.OpShowProfile,
 		Value:     values,
 	}, nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/bytebase/gomongo/internal/translator"
 	"github.com/bytebase/gomongo/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
@@ -13,17 +14,196 @@ import (
 type Result struct {
 	Operation types.OperationType
 	Value     []any // slice of results; element types vary by operation
+	Truncated bool  // MaxRows or MaxBytes cut the result short
+
+	TruncatedValues int // string and binary values shortened to MaxValueBytes
+}
+
+// Options configures how an operation is executed.
+type Options struct {
+	MaxRows       *int64 // cap on rows returned by queries, list operations and countDocuments()
+	MaxBytes      *int64 // cap on the raw BSON size of the values returned by queries and list operations
+	MaxValueBytes int    // if positive, shorten longer string and binary values to this many bytes
+	KillOnCancel  bool   // kill the server-side operation when ctx is cancelled
 }
 
 // Execute executes a parsed operation against MongoDB.
-func Execute(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, statement string, maxRows *int64) (*Result, error) {
+//
+// Writes that were partly applied, such as an unordered insertMany() with some
+// failed documents or an update whose write concern failed, return both the
+// result of what was written and the error.
+func Execute(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, statement string, opts Options) (*Result, error) {
+	var result *Result
+	var err error
+	if !opts.KillOnCancel {
+		result, err = dispatch(ctx, client, database, op, statement, opts)
+	} else {
+		err = withKillOnCancel(ctx, client, op, func(op *translator.Operation) error {
+			var err error
+			result, err = dispatch(ctx, client, database, op, statement, opts)
+			return err
+		})
+	}
+	if result != nil {
+		capValues(result, opts)
+		if opts.MaxValueBytes > 0 {
+			for i, v := range result.Value {
+				result.Value[i] = shortenValue(v, opts.MaxValueBytes, &result.TruncatedValues)
+			}
+		}
+	}
+	return result, err
+}
+
+// Stream executes a parsed operation and calls fn for each document it returns.
+//
+// find(), aggregate() and getIndexes() read their cursor one batch at a time, so
+// results are never held in memory as a whole. Other operations are executed as
+// usual and their documents passed to fn in turn; Stream returns an error if such
+// an operation returns a value that is not a document. An error from fn stops the
+// iteration and is returned as is.
+func Stream(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, statement string, opts Options, fn func(bson.D) error) error {
+	if !opts.KillOnCancel {
+		return stream(ctx, client, database, op, statement, opts, fn)
+	}
+	return withKillOnCancel(ctx, client, op, func(op *translator.Operation) error {
+		return stream(ctx, client, database, op, statement, opts, fn)
+	})
+}
+
+// listOperations are the operations that return one value per item found and
+// read them all at once, so MaxRows and MaxBytes can only cut their result after
+// the fact. find(), aggregate(), latencyStats(), getIndexes() and
+// getCollectionInfos() stop reading their cursor instead.
+var listOperations = map[types.OperationType]bool{
+	types.OpDistinct:                        true,
+	types.OpShowDatabases:                   true,
+	types.OpShowCollections:                 true,
+	types.OpGetCollectionNames:              true,
+	types.OpShowUsers:                       true,
+	types.OpShowRoles:                       true,
+	types.OpGetUsers:                        true,
+	types.OpGetRoles:                        true,
+	types.OpShowLog:                         true,
+	types.OpShowLogs:                        true,
+	types.OpShowProfile:                     true,
+	types.OpRsPrintSecondaryReplicationInfo: true,
+}
+
+// capValues cuts the result of a list operation to MaxRows values and MaxBytes
+// bytes, measuring each value by its BSON encoding.
+func capValues(result *Result, opts Options) {
+	if (opts.MaxRows == nil && opts.MaxBytes == nil) || !listOperations[result.Operation] {
+		return
+	}
+	rc := &resultCap{maxRows: opts.MaxRows, maxBytes: opts.MaxBytes}
+	for i, v := range result.Value {
+		size := 0
+		if opts.MaxBytes != nil {
+			if _, data, err := bson.MarshalValue(v); err == nil {
+				size = len(data)
+			}
+		}
+		if !rc.admit(size) {
+			result.Value = result.Value[:i]
+			result.Truncated = true
+			return
+		}
+	}
+}
+
+// documentOperations are the operations that return documents and do not
+// modify data, which are the only ones that can be streamed. Other operations,
+// and aggregations that end in $out or $merge, are rejected before they run,
+// so streaming a write never performs it.
+var documentOperations = map[types.OperationType]bool{
+	types.OpFind:                            true,
+	types.OpFindOne:                         true,
+	types.OpAggregate:                       true,
+	types.OpShowDatabases:                   true,
+	types.OpGetCollectionInfos:              true,
+	types.OpGetIndexes:                      true,
+	types.OpDbStats:                         true,
+	types.OpCollectionStats:                 true,
+	types.OpServerStatus:                    true,
+	types.OpServerBuildInfo:                 true,
+	types.OpHostInfo:                        true,
+	types.OpListCommands:                    true,
+	types.OpValidate:                        true,
+	types.OpLatencyStats:                    true,
+	types.OpCurrentOp:                       true,
+	types.OpGetProfilingStatus:              true,
+	types.OpShowProfile:                     true,
+	types.OpShowUsers:                       true,
+	types.OpShowRoles:                       true,
+	types.OpGetUsers:                        true,
+	types.OpGetUser:                         true,
+	types.OpGetRoles:                        true,
+	types.OpGetRole:                         true,
+	types.OpRsStatus:                        true,
+	types.OpRsConf:                          true,
+	types.OpRsPrintReplicationInfo:          true,
+	types.OpRsPrintSecondaryReplicationInfo: true,
+	types.OpShStatus:                        true,
+	types.OpGetShardDistribution:            true,
+}
+
+// stream routes an operation to its streaming executor, falling back to dispatch.
+func stream(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, statement string, opts Options, fn func(bson.D) error) error {
+	if opts.MaxValueBytes > 0 {
+		next := fn
+		fn = func(doc bson.D) error {
+			var n int
+			return next(shortenValue(doc, opts.MaxValueBytes, &n).(bson.D))
+		}
+	}
+
+	if !documentOperations[op.OpType] || op.OpType == types.OpAggregate && writesOutput(op.Pipeline) {
+		return fmt.Errorf("statement does not return documents")
+	}
+
+	switch op.OpType {
+	case types.OpFind:
+		_, err := streamFind(ctx, client, database, op, opts, fn)
+		return err
+	case types.OpAggregate:
+		_, err := streamAggregate(ctx, client, database, op, opts, fn)
+		return err
+	case types.OpGetIndexes:
+		_, err := streamGetIndexes(ctx, client, database, op, opts, fn)
+		return err
+	case types.OpLatencyStats:
+		_, err := streamAggregate(ctx, client, database, latencyStatsAggregate(op), opts, fn)
+		return err
+	}
+
+	result, err := dispatch(ctx, client, database, op, statement, opts)
+	if err != nil {
+		return err
+	}
+	capValues(result, opts)
+	for i, v := range result.Value {
+		doc, ok := v.(bson.D)
+		if !ok {
+			return fmt.Errorf("statement does not return documents: value %d is %T", i, v)
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dispatch routes an operation to its executor.
+func dispatch(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, statement string, opts Options) (*Result, error) {
+	maxRows := opts.MaxRows
 	switch op.OpType {
 	case types.OpFind:
-		return executeFind(ctx, client, database, op, maxRows)
+		return executeFind(ctx, client, database, op, opts)
 	case types.OpFindOne:
 		return executeFindOne(ctx, client, database, op)
 	case types.OpAggregate:
-		return executeAggregate(ctx, client, database, op)
+		return executeAggregate(ctx, client, database, op, opts)
 	case types.OpShowDatabases:
 		return executeShowDatabases(ctx, client)
 	case types.OpShowCollections:
@@ -31,9 +211,9 @@ func Execute(ctx context.Context, client *mongo.Client, database string, op *tra
 	case types.OpGetCollectionNames:
 		return executeGetCollectionNames(ctx, client, database)
 	case types.OpGetCollectionInfos:
-		return executeGetCollectionInfos(ctx, client, database, op)
+		return executeGetCollectionInfos(ctx, client, database, op, opts)
 	case types.OpGetIndexes:
-		return executeGetIndexes(ctx, client, database, op)
+		return executeGetIndexes(ctx, client, database, op, opts)
 	case types.OpCountDocuments:
 		return executeCountDocuments(ctx, client, database, op, maxRows)
 	case types.OpEstimatedDocumentCount:
@@ -106,7 +286,69 @@ func Execute(ctx context.Context, client *mongo.Client, database string, op *tra
 	case types.OpValidate:
 		return executeValidate(ctx, client, database, op)
 	case types.OpLatencyStats:
-		return executeLatencyStats(ctx, client, database, op)
+		return executeLatencyStats(ctx, client, database, op, opts)
+	// Operations Console
+	case types.OpCurrentOp:
+		return executeCurrentOp(ctx, client, op)
+	case types.OpKillOp:
+		return executeKillOp(ctx, client, op)
+	// Profiler
+	case types.OpSetProfilingLevel:
+		return executeSetProfilingLevel(ctx, client, database, op)
+	case types.OpGetProfilingLevel:
+		return executeGetProfilingLevel(ctx, client, database)
+	case types.OpGetProfilingStatus:
+		return executeGetProfilingStatus(ctx, client, database)
+	case types.OpShowProfile:
+		return executeShowProfile(ctx, client, database)
+	// Show Commands
+	case types.OpShowUsers:
+		return executeShowUsers(ctx, client, database)
+	case types.OpShowRoles:
+		return executeShowRoles(ctx, client, database)
+	case types.OpShowLog:
+		return executeShowLog(ctx, client, op)
+	case types.OpShowLogs:
+		return executeShowLogs(ctx, client)
+	// User and Role Introspection
+	case types.OpGetUsers:
+		return executeGetUsers(ctx, client, database, op)
+	case types.OpGetUser:
+		return executeGetUser(ctx, client, database, op)
+	case types.OpGetRoles:
+		return executeGetRoles(ctx, client, database, op)
+	case types.OpGetRole:
+		return executeGetRole(ctx, client, database, op)
+	// User and Role Administration
+	case types.OpCreateUser:
+		return executeCreateUser(ctx, client, database, op)
+	case types.OpUpdateUser:
+		return executeUpdateUser(ctx, client, database, op)
+	case types.OpDropUser:
+		return executeDropUser(ctx, client, database, op)
+	case types.OpGrantRolesToUser:
+		return executeGrantRolesToUser(ctx, client, database, op)
+	case types.OpRevokeRolesFromUser:
+		return executeRevokeRolesFromUser(ctx, client, database, op)
+	case types.OpCreateRole:
+		return executeCreateRole(ctx, client, database, op)
+	case types.OpGrantPrivilegesToRole:
+		return executeGrantPrivilegesToRole(ctx, client, database, op)
+	case types.OpChangeUserPassword:
+		return executeChangeUserPassword(ctx, client, database, op)
+	// Replication and Sharding Status
+	case types.OpRsStatus:
+		return executeRsStatus(ctx, client)
+	case types.OpRsConf:
+		return executeRsConf(ctx, client)
+	case types.OpRsPrintReplicationInfo:
+		return executeRsPrintReplicationInfo(ctx, client)
+	case types.OpRsPrintSecondaryReplicationInfo:
+		return executeRsPrintSecondaryReplicationInfo(ctx, client)
+	case types.OpShStatus:
+		return executeShStatus(ctx, client)
+	case types.OpGetShardDistribution:
+		return executeGetShardDistribution(ctx, client, database, op)
 	default:
 		return nil, fmt.Errorf("unsupported operation: %s", statement)
 	}
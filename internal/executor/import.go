@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// UpsertResult reports the counts of a BulkUpsert call.
+type UpsertResult struct {
+	Inserted int64 // documents inserted because they lack a key field
+	Matched  int64 // documents that matched an existing document
+	Modified int64 // matched documents that were changed
+	Upserted int64 // documents inserted because nothing matched
+}
+
+// BulkUpsert writes documents keyed on keyFields in a single bulk write. Each
+// document replaces the existing document whose key fields are equal, or, with
+// merge, has its fields set on it; it is inserted when there is no match. A
+// document that lacks any of the key fields is inserted as is.
+//
+// On error the counts of the writes that succeeded are returned along with it.
+func BulkUpsert(ctx context.Context, client *mongo.Client, database, collection string, docs []bson.D, keyFields []string, merge, ordered bool) (*UpsertResult, error) {
+	models := make([]mongo.WriteModel, len(docs))
+	for i, doc := range docs {
+		filter, ok := keyFilter(doc, keyFields)
+		switch {
+		case !ok:
+			models[i] = mongo.NewInsertOneModel().SetDocument(doc)
+		case merge:
+			models[i] = mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(mergeUpdate(doc)).SetUpsert(true)
+		default:
+			models[i] = mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(doc).SetUpsert(true)
+		}
+	}
+
+	coll := client.Database(database).Collection(collection)
+	result, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(ordered))
+	var counts UpsertResult
+	if result != nil {
+		counts = UpsertResult{
+			Inserted: result.InsertedCount,
+			Matched:  result.MatchedCount,
+			Modified: result.ModifiedCount,
+			Upserted: result.UpsertedCount,
+		}
+	}
+	if err != nil {
+		return &counts, fmt.Errorf("bulk upsert failed: %w", err)
+	}
+	return &counts, nil
+}
+
+// keyFilter builds a filter matching the document's key fields. It reports false
+// if the document lacks any of them. A key field that is null is present, and
+// matches documents whose field is null.
+func keyFilter(doc bson.D, keyFields []string) (bson.D, bool) {
+	filter := make(bson.D, 0, len(keyFields))
+	for _, field := range keyFields {
+		value, ok := findPath(doc, field)
+		if !ok {
+			return nil, false
+		}
+		filter = append(filter, bson.E{Key: field, Value: value})
+	}
+	return filter, true
+}
+
+// findPath finds the value at a dotted path of nested documents. It reports
+// false if the path is absent, telling it apart from a null value.
+func findPath(doc bson.D, path string) (any, bool) {
+	var value any = doc
+	for _, key := range strings.Split(path, ".") {
+		nested, ok := value.(bson.D)
+		if !ok {
+			return nil, false
+		}
+		i := slices.IndexFunc(nested, func(e bson.E) bool { return e.Key == key })
+		if i < 0 {
+			return nil, false
+		}
+		value = nested[i].Value
+	}
+	return value, true
+}
+
+// mergeUpdate builds an update that sets the document's fields on the matched
+// document. The immutable _id is only set when the document is inserted.
+func mergeUpdate(doc bson.D) bson.D {
+	set := make(bson.D, 0, len(doc))
+	var id any
+	for _, elem := range doc {
+		if elem.Key == "_id" {
+			id = elem.Value
+			continue
+		}
+		set = append(set, elem)
+	}
+	update := bson.D{}
+	if len(set) > 0 {
+		update = append(update, bson.E{Key: "$set", Value: set})
+	}
+	if id != nil {
+		update = append(update, bson.E{Key: "$setOnInsert", Value: bson.D{{Key: "_id", Value: id}}})
+	}
+	return update
+}
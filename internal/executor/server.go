@@ -4,22 +4,27 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/bytebase/gomongo/internal/translator"
 	"github.com/bytebase/gomongo/types"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 // executeShowDatabases executes a show dbs/databases command.
+// Like mongosh, each database is reported with its size on disk.
 func executeShowDatabases(ctx context.Context, client *mongo.Client) (*Result, error) {
-	names, err := client.ListDatabaseNames(ctx, bson.D{})
+	result, err := client.ListDatabases(ctx, bson.D{})
 	if err != nil {
 		return nil, fmt.Errorf("list databases failed: %w", err)
 	}
 
-	// Convert []string to []any
-	values := make([]any, len(names))
-	for i, name := range names {
-		values[i] = name
+	values := make([]any, len(result.Databases))
+	for i, spec := range result.Databases {
+		values[i] = bson.D{
+			{Key: "name", Value: spec.Name},
+			{Key: "sizeOnDisk", Value: spec.SizeOnDisk},
+			{Key: "empty", Value: spec.Empty},
+		}
 	}
 
 	return &Result{
@@ -27,3 +32,55 @@ func executeShowDatabases(ctx context.Context, client *mongo.Client) (*Result, e
 		Value:     values,
 	}, nil
 }
+
+// executeShowLog executes a show log <name> command.
+func executeShowLog(ctx context.Context, client *mongo.Client, op *translator.Operation) (*Result, error) {
+	result, err := runCommand(ctx, client.Database("admin"), bson.D{{Key: "getLog", Value: op.LogName}})
+	if err != nil {
+		return nil, fmt.Errorf("show log failed: %w", err)
+	}
+
+	lines, _ := findField(result, "log").(bson.A)
+	return &Result{
+		Operation: types.OpShowLog,
+		Value:     []any(lines),
+	}, nil
+}
+
+// executeShowLogs executes a show logs command.
+func executeShowLogs(ctx context.Context, client *mongo.Client) (*Result, error) {
+	result, err := runCommand(ctx, client.Database("admin"), bson.D{{Key: "getLog", Value: "*"}})
+	if err != nil {
+		return nil, fmt.Errorf("show logs failed: %w", err)
+	}
+
+	names, _ := findField(result, "names").(bson.A)
+	return &Result{
+		Operation: types.OpShowLogs,
+		Value:     []any(names),
+	}, nil
+}
+
+// executeCurrentOp executes a db.currentOp() command.
+func executeCurrentOp(ctx context.Context, client *mongo.Client, op *translator.Operation) (*Result, error) {
+	command := bson.D{{Key: "currentOp", Value: int32(1)}}
+	command = append(command, op.Filter...)
+
+	result, err := runCommand(ctx, client.Database("admin"), command)
+	if err != nil {
+		return nil, fmt.Errorf("currentOp failed: %w", err)
+	}
+	return &Result{Operation: types.OpCurrentOp, Value: []any{result}}, nil
+}
+
+// executeKillOp executes a db.killOp() command.
+func executeKillOp(ctx context.Context, client *mongo.Client, op *translator.Operation) (*Result, error) {
+	result, err := runCommand(ctx, client.Database("admin"), bson.D{
+		{Key: "killOp", Value: int32(1)},
+		{Key: "op", Value: op.OpID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("killOp failed: %w", err)
+	}
+	return &Result{Operation: types.OpKillOp, Value: []any{result}}, nil
+}
@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ellipsis marks the end of a shortened string.
+const ellipsis = "…"
+
+// shortenValue shortens the string and binary values in v, including those
+// nested in documents and arrays, to at most maxBytes bytes. Strings are cut at
+// a character boundary and end in "…", which counts toward maxBytes; a limit
+// too small to hold it cuts without it. It adds the number of values shortened
+// to count. Documents and arrays are updated in place.
+func shortenValue(v any, maxBytes int, count *int) any {
+	switch val := v.(type) {
+	case string:
+		if len(val) <= maxBytes {
+			return val
+		}
+		cut, marker := maxBytes-len(ellipsis), ellipsis
+		if cut < 0 {
+			cut, marker = maxBytes, ""
+		}
+		for cut > 0 && !utf8.RuneStart(val[cut]) {
+			cut--
+		}
+		*count++
+		return val[:cut] + marker
+	case bson.Binary:
+		if len(val.Data) <= maxBytes {
+			return val
+		}
+		*count++
+		return bson.Binary{Subtype: val.Subtype, Data: val.Data[:maxBytes]}
+	case bson.D:
+		for i := range val {
+			val[i].Value = shortenValue(val[i].Value, maxBytes, count)
+		}
+		return val
+	case bson.A:
+		for i := range val {
+			val[i] = shortenValue(val[i], maxBytes, count)
+		}
+		return val
+	case []any:
+		for i := range val {
+			val[i] = shortenValue(val[i], maxBytes, count)
+		}
+		return val
+	}
+	return v
+}
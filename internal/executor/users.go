@@ -0,0 +1,247 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/bytebase/gomongo/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// credentialFields lists user document fields that hold password-derived secrets.
+var credentialFields = map[string]bool{
+	"credentials": true,
+	"pwd":         true,
+}
+
+// redactCredentials removes credential fields from a user document.
+func redactCredentials(doc bson.D) bson.D {
+	redacted := make(bson.D, 0, len(doc))
+	for _, elem := range doc {
+		if credentialFields[elem.Key] {
+			continue
+		}
+		redacted = append(redacted, elem)
+	}
+	return redacted
+}
+
+// userDocuments extracts the user documents from a usersInfo result, with credentials redacted.
+func userDocuments(result bson.D) []any {
+	users, _ := findField(result, "users").(bson.A)
+	values := make([]any, 0, len(users))
+	for _, u := range users {
+		if doc, ok := u.(bson.D); ok {
+			values = append(values, redactCredentials(doc))
+		}
+	}
+	return values
+}
+
+// roleDocuments extracts the role documents from a rolesInfo result.
+func roleDocuments(result bson.D) []any {
+	roles, _ := findField(result, "roles").(bson.A)
+	return []any(roles)
+}
+
+// appendBoolOption appends key: value to the command if the option is set.
+func appendBoolOption(command bson.D, key string, val *bool) bson.D {
+	if val != nil {
+		command = append(command, bson.E{Key: key, Value: *val})
+	}
+	return command
+}
+
+// executeShowUsers executes a show users command.
+func executeShowUsers(ctx context.Context, client *mongo.Client, database string) (*Result, error) {
+	result, err := runCommand(ctx, client.Database(database), bson.D{{Key: "usersInfo", Value: int32(1)}})
+	if err != nil {
+		return nil, fmt.Errorf("show users failed: %w", err)
+	}
+	return &Result{Operation: types.OpShowUsers, Value: userDocuments(result)}, nil
+}
+
+// executeShowRoles executes a show roles command.
+// Like mongosh, built-in roles are included.
+func executeShowRoles(ctx context.Context, client *mongo.Client, database string) (*Result, error) {
+	result, err := runCommand(ctx, client.Database(database), bson.D{
+		{Key: "rolesInfo", Value: int32(1)},
+		{Key: "showBuiltinRoles", Value: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("show roles failed: %w", err)
+	}
+	return &Result{Operation: types.OpShowRoles, Value: roleDocuments(result)}, nil
+}
+
+// executeGetUsers executes a db.getUsers() command.
+func executeGetUsers(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	command := bson.D{{Key: "usersInfo", Value: int32(1)}}
+	command = appendBoolOption(command, "showPrivileges", op.ShowPrivileges)
+	command = appendBoolOption(command, "showAuthenticationRestrictions", op.ShowAuthenticationRestrictions)
+	command = appendBoolOption(command, "showCustomData", op.ShowCustomData)
+	if op.Filter != nil {
+		command = append(command, bson.E{Key: "filter", Value: op.Filter})
+	}
+
+	result, err := runCommand(ctx, client.Database(database), command)
+	if err != nil {
+		return nil, fmt.Errorf("getUsers failed: %w", err)
+	}
+	return &Result{Operation: types.OpGetUsers, Value: userDocuments(result)}, nil
+}
+
+// executeGetUser executes a db.getUser() command.
+// Like findOne, the result is empty if the user does not exist.
+func executeGetUser(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	command := bson.D{{Key: "usersInfo", Value: bson.D{
+		{Key: "user", Value: op.UserName},
+		{Key: "db", Value: database},
+	}}}
+	command = appendBoolOption(command, "showPrivileges", op.ShowPrivileges)
+	command = appendBoolOption(command, "showAuthenticationRestrictions", op.ShowAuthenticationRestrictions)
+	command = appendBoolOption(command, "showCustomData", op.ShowCustomData)
+
+	result, err := runCommand(ctx, client.Database(database), command)
+	if err != nil {
+		return nil, fmt.Errorf("getUser failed: %w", err)
+	}
+	return &Result{Operation: types.OpGetUser, Value: userDocuments(result)}, nil
+}
+
+// executeGetRoles executes a db.getRoles() command.
+func executeGetRoles(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	command := bson.D{{Key: "rolesInfo", Value: int32(1)}}
+	command = appendBoolOption(command, "showPrivileges", op.ShowPrivileges)
+	command = appendBoolOption(command, "showBuiltinRoles", op.ShowBuiltinRoles)
+	command = appendBoolOption(command, "showAuthenticationRestrictions", op.ShowAuthenticationRestrictions)
+
+	result, err := runCommand(ctx, client.Database(database), command)
+	if err != nil {
+		return nil, fmt.Errorf("getRoles failed: %w", err)
+	}
+	return &Result{Operation: types.OpGetRoles, Value: roleDocuments(result)}, nil
+}
+
+// executeGetRole executes a db.getRole() command.
+// Like findOne, the result is empty if the role does not exist.
+func executeGetRole(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	command := bson.D{{Key: "rolesInfo", Value: bson.D{
+		{Key: "role", Value: op.RoleName},
+		{Key: "db", Value: database},
+	}}}
+	command = appendBoolOption(command, "showPrivileges", op.ShowPrivileges)
+	command = appendBoolOption(command, "showBuiltinRoles", op.ShowBuiltinRoles)
+	command = appendBoolOption(command, "showAuthenticationRestrictions", op.ShowAuthenticationRestrictions)
+
+	result, err := runCommand(ctx, client.Database(database), command)
+	if err != nil {
+		return nil, fmt.Errorf("getRole failed: %w", err)
+	}
+	return &Result{Operation: types.OpGetRole, Value: roleDocuments(result)}, nil
+}
+
+// runSecurityCommand runs a user or role administration command.
+// The server reply is not returned to the caller; only {ok: 1} is reported, so
+// no part of the command (such as a password) can be echoed back.
+func runSecurityCommand(ctx context.Context, client *mongo.Client, database string, op *translator.Operation, command bson.D) (*Result, error) {
+	if op.WriteConcern != nil {
+		command = append(command, bson.E{Key: "writeConcern", Value: op.WriteConcern})
+	}
+	if _, err := runCommand(ctx, client.Database(database), command); err != nil {
+		return nil, err
+	}
+	return &Result{
+		Operation: op.OpType,
+		Value:     []any{bson.D{{Key: "ok", Value: int32(1)}}},
+	}, nil
+}
+
+// executeCreateUser executes a db.createUser() command.
+func executeCreateUser(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	command := append(bson.D{{Key: "createUser", Value: op.UserName}}, op.UserSpec...)
+	result, err := runSecurityCommand(ctx, client, database, op, command)
+	if err != nil {
+		return nil, fmt.Errorf("createUser failed: %w", err)
+	}
+	return result, nil
+}
+
+// executeUpdateUser executes a db.updateUser() command.
+func executeUpdateUser(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	command := append(bson.D{{Key: "updateUser", Value: op.UserName}}, op.UserSpec...)
+	result, err := runSecurityCommand(ctx, client, database, op, command)
+	if err != nil {
+		return nil, fmt.Errorf("updateUser failed: %w", err)
+	}
+	return result, nil
+}
+
+// executeDropUser executes a db.dropUser() command.
+func executeDropUser(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	result, err := runSecurityCommand(ctx, client, database, op, bson.D{{Key: "dropUser", Value: op.UserName}})
+	if err != nil {
+		return nil, fmt.Errorf("dropUser failed: %w", err)
+	}
+	return result, nil
+}
+
+// executeGrantRolesToUser executes a db.grantRolesToUser() command.
+func executeGrantRolesToUser(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	result, err := runSecurityCommand(ctx, client, database, op, bson.D{
+		{Key: "grantRolesToUser", Value: op.UserName},
+		{Key: "roles", Value: op.Roles},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("grantRolesToUser failed: %w", err)
+	}
+	return result, nil
+}
+
+// executeRevokeRolesFromUser executes a db.revokeRolesFromUser() command.
+func executeRevokeRolesFromUser(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	result, err := runSecurityCommand(ctx, client, database, op, bson.D{
+		{Key: "revokeRolesFromUser", Value: op.UserName},
+		{Key: "roles", Value: op.Roles},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("revokeRolesFromUser failed: %w", err)
+	}
+	return result, nil
+}
+
+// executeCreateRole executes a db.createRole() command.
+func executeCreateRole(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	command := append(bson.D{{Key: "createRole", Value: op.RoleName}}, op.RoleSpec...)
+	result, err := runSecurityCommand(ctx, client, database, op, command)
+	if err != nil {
+		return nil, fmt.Errorf("createRole failed: %w", err)
+	}
+	return result, nil
+}
+
+// executeGrantPrivilegesToRole executes a db.grantPrivilegesToRole() command.
+func executeGrantPrivilegesToRole(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	result, err := runSecurityCommand(ctx, client, database, op, bson.D{
+		{Key: "grantPrivilegesToRole", Value: op.RoleName},
+		{Key: "privileges", Value: op.Privileges},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("grantPrivilegesToRole failed: %w", err)
+	}
+	return result, nil
+}
+
+// executeChangeUserPassword executes a db.changeUserPassword() command.
+func executeChangeUserPassword(ctx context.Context, client *mongo.Client, database string, op *translator.Operation) (*Result, error) {
+	result, err := runSecurityCommand(ctx, client, database, op, bson.D{
+		{Key: "updateUser", Value: op.UserName},
+		{Key: "pwd", Value: op.Password},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("changeUserPassword failed: %w", err)
+	}
+	return result, nil
+}
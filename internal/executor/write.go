@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/bytebase/gomongo/internal/translator"
@@ -13,7 +14,8 @@ import (
 )
 
 // convertWriteConcern converts a bson.D writeConcern document to *writeconcern.WriteConcern.
-// Note: wtimeout is not supported in MongoDB Go driver v2, so it is ignored if present.
+// Note: wtimeout is not supported in MongoDB Go driver v2, so it is ignored if present;
+// the translator warns about it and the other fields ignored here.
 func convertWriteConcern(doc bson.D) *writeconcern.WriteConcern {
 	if doc == nil {
 		return nil
@@ -110,7 +112,7 @@ func executeInsertOne(ctx context.Context, client *mongo.Client, database string
 	}
 
 	result, err := collection.InsertOne(ctx, op.Document, opts)
-	if err != nil {
+	if err != nil && (result == nil || !writeConcernFailed(err)) {
 		return nil, fmt.Errorf("insertOne failed: %w", err)
 	}
 
@@ -123,7 +125,7 @@ func executeInsertOne(ctx context.Context, client *mongo.Client, database string
 	return &Result{
 		Operation: types.OpInsertOne,
 		Value:     []any{response},
-	}, nil
+	}, wrapWriteError("insertOne", err)
 }
 
 // executeInsertMany executes an insertMany operation.
@@ -148,20 +150,77 @@ func executeInsertMany(ctx context.Context, client *mongo.Client, database strin
 	}
 
 	result, err := collection.InsertMany(ctx, docs, opts)
-	if err != nil {
+	ids, ok := insertedIDs(result, err, op.Ordered == nil || *op.Ordered)
+	if !ok {
 		return nil, fmt.Errorf("insertMany failed: %w", err)
 	}
 
 	// Build response document matching mongosh format
 	response := bson.D{
 		{Key: "acknowledged", Value: true},
-		{Key: "insertedIds", Value: result.InsertedIDs},
+		{Key: "insertedIds", Value: ids},
 	}
 
 	return &Result{
 		Operation: types.OpInsertMany,
 		Value:     []any{response},
-	}, nil
+	}, wrapWriteError("insertMany", err)
+}
+
+// insertedIDs returns the _id values of the documents an insertMany inserted.
+// When some documents failed, they are left out: an ordered insert stops at the
+// first failure, while an unordered one skips only the failed documents. It
+// reports false if err leaves unknown which documents were inserted.
+func insertedIDs(result *mongo.InsertManyResult, err error, ordered bool) ([]any, bool) {
+	if result == nil {
+		return nil, false
+	}
+	if err == nil {
+		return result.InsertedIDs, true
+	}
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) {
+		return nil, false
+	}
+
+	failed := make(map[int]bool, len(bwe.WriteErrors))
+	for _, we := range bwe.WriteErrors {
+		failed[we.Index] = true
+	}
+	ids := make([]any, 0, len(result.InsertedIDs))
+	for i, id := range result.InsertedIDs {
+		if failed[i] {
+			if ordered {
+				break
+			}
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
+
+// writeConcernFailed reports whether err reports only a write concern error,
+// meaning the write was applied but not confirmed at the requested level.
+func writeConcernFailed(err error) bool {
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		return we.WriteConcernError != nil && len(we.WriteErrors) == 0
+	}
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) {
+		return bwe.WriteConcernError != nil && len(bwe.WriteErrors) == 0
+	}
+	return false
+}
+
+// wrapWriteError wraps the error of a write that still produced a result, or
+// returns nil if there is none.
+func wrapWriteError(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s failed: %w", method, err)
 }
 
 // executeUpdateOne executes an updateOne operation.
@@ -195,7 +254,7 @@ func executeUpdateOne(ctx context.Context, client *mongo.Client, database string
 	}
 
 	result, err := collection.UpdateOne(ctx, op.Filter, op.Update, opts)
-	if err != nil {
+	if err != nil && (result == nil || !writeConcernFailed(err)) {
 		return nil, fmt.Errorf("updateOne failed: %w", err)
 	}
 
@@ -212,7 +271,7 @@ func executeUpdateOne(ctx context.Context, client *mongo.Client, database string
 	return &Result{
 		Operation: types.OpUpdateOne,
 		Value:     []any{response},
-	}, nil
+	}, wrapWriteError("updateOne", err)
 }
 
 // executeUpdateMany executes an updateMany operation.
@@ -243,7 +302,7 @@ func executeUpdateMany(ctx context.Context, client *mongo.Client, database strin
 	}
 
 	result, err := collection.UpdateMany(ctx, op.Filter, op.Update, opts)
-	if err != nil {
+	if err != nil && (result == nil || !writeConcernFailed(err)) {
 		return nil, fmt.Errorf("updateMany failed: %w", err)
 	}
 
@@ -259,7 +318,7 @@ func executeUpdateMany(ctx context.Context, client *mongo.Client, database strin
 	return &Result{
 		Operation: types.OpUpdateMany,
 		Value:     []any{response},
-	}, nil
+	}, wrapWriteError("updateMany", err)
 }
 
 // executeReplaceOne executes a replaceOne operation.
@@ -290,7 +349,7 @@ func executeReplaceOne(ctx context.Context, client *mongo.Client, database strin
 	}
 
 	result, err := collection.ReplaceOne(ctx, op.Filter, op.Replacement, opts)
-	if err != nil {
+	if err != nil && (result == nil || !writeConcernFailed(err)) {
 		return nil, fmt.Errorf("replaceOne failed: %w", err)
 	}
 
@@ -306,7 +365,7 @@ func executeReplaceOne(ctx context.Context, client *mongo.Client, database strin
 	return &Result{
 		Operation: types.OpReplaceOne,
 		Value:     []any{response},
-	}, nil
+	}, wrapWriteError("replaceOne", err)
 }
 
 // executeDeleteOne executes a deleteOne operation.
@@ -328,7 +387,7 @@ func executeDeleteOne(ctx context.Context, client *mongo.Client, database string
 	}
 
 	result, err := collection.DeleteOne(ctx, op.Filter, opts)
-	if err != nil {
+	if err != nil && (result == nil || !writeConcernFailed(err)) {
 		return nil, fmt.Errorf("deleteOne failed: %w", err)
 	}
 
@@ -340,7 +399,7 @@ func executeDeleteOne(ctx context.Context, client *mongo.Client, database string
 	return &Result{
 		Operation: types.OpDeleteOne,
 		Value:     []any{response},
-	}, nil
+	}, wrapWriteError("deleteOne", err)
 }
 
 // executeDeleteMany executes a deleteMany operation.
@@ -362,7 +421,7 @@ func executeDeleteMany(ctx context.Context, client *mongo.Client, database strin
 	}
 
 	result, err := collection.DeleteMany(ctx, op.Filter, opts)
-	if err != nil {
+	if err != nil && (result == nil || !writeConcernFailed(err)) {
 		return nil, fmt.Errorf("deleteMany failed: %w", err)
 	}
 
@@ -374,7 +433,7 @@ func executeDeleteMany(ctx context.Context, client *mongo.Client, database strin
 	return &Result{
 		Operation: types.OpDeleteMany,
 		Value:     []any{response},
-	}, nil
+	}, wrapWriteError("deleteMany", err)
 }
 
 // executeFindOneAndUpdate executes a findOneAndUpdate operation.
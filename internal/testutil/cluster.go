@@ -0,0 +1,220 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"github.com/testcontainers/testcontainers-go/network"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// clusterImage is the MongoDB image used for the replica set and sharded cluster.
+const clusterImage = "mongo:8.0"
+
+// clusterReadyTimeout bounds how long cluster setup waits for a primary or a shard.
+const clusterReadyTimeout = 60 * time.Second
+
+var (
+	replicaSetClient *mongo.Client
+	replicaSetOnce   sync.Once
+	replicaSetErr    error
+
+	shardedClient *mongo.Client
+	shardedOnce   sync.Once
+	shardedErr    error
+)
+
+// GetReplicaSetClient returns a client connected to a single-node replica set.
+// The container is started once and reused across all tests.
+func GetReplicaSetClient(t *testing.T) *mongo.Client {
+	t.Helper()
+
+	replicaSetOnce.Do(func() {
+		replicaSetClient, replicaSetErr = setupReplicaSet(context.Background())
+	})
+
+	if replicaSetErr != nil {
+		t.Fatalf("failed to setup replica set: %v", replicaSetErr)
+	}
+
+	return replicaSetClient
+}
+
+// GetShardedClusterClient returns a client connected to the mongos of a minimal
+// sharded cluster: one config server, one shard and one mongos, each a single node.
+// The containers are started once and reused across all tests.
+func GetShardedClusterClient(t *testing.T) *mongo.Client {
+	t.Helper()
+
+	shardedOnce.Do(func() {
+		shardedClient, shardedErr = setupShardedCluster(context.Background())
+	})
+
+	if shardedErr != nil {
+		t.Fatalf("failed to setup sharded cluster: %v", shardedErr)
+	}
+
+	return shardedClient
+}
+
+func setupReplicaSet(ctx context.Context) (*mongo.Client, error) {
+	container, err := mongodb.Run(ctx, clusterImage, mongodb.WithReplicaSet("rs0"))
+	if err != nil {
+		return nil, err
+	}
+
+	// The member is registered under its container IP, which may not be reachable
+	// from the host, so connect directly instead of through replica set discovery.
+	return connectDirect(ctx, container)
+}
+
+func setupShardedCluster(ctx context.Context) (*mongo.Client, error) {
+	nw, err := network.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("network: %w", err)
+	}
+
+	configsvr, err := startClusterNode(ctx, nw, "configsvr",
+		"mongod", "--configsvr", "--replSet", "cfg", "--port", "27017", "--bind_ip_all")
+	if err != nil {
+		return nil, fmt.Errorf("config server: %w", err)
+	}
+	if err := initiateReplicaSet(ctx, configsvr, "cfg", "configsvr:27017", true); err != nil {
+		return nil, fmt.Errorf("config server: %w", err)
+	}
+
+	shard, err := startClusterNode(ctx, nw, "shard0",
+		"mongod", "--shardsvr", "--replSet", "shard0", "--port", "27017", "--bind_ip_all")
+	if err != nil {
+		return nil, fmt.Errorf("shard: %w", err)
+	}
+	if err := initiateReplicaSet(ctx, shard, "shard0", "shard0:27017", false); err != nil {
+		return nil, fmt.Errorf("shard: %w", err)
+	}
+
+	router, err := startClusterNode(ctx, nw, "mongos",
+		"mongos", "--configdb", "cfg/configsvr:27017", "--port", "27017", "--bind_ip_all")
+	if err != nil {
+		return nil, fmt.Errorf("mongos: %w", err)
+	}
+	client, err := connectDirect(ctx, router)
+	if err != nil {
+		return nil, fmt.Errorf("mongos: %w", err)
+	}
+
+	// mongos may not have loaded the cluster metadata yet, so retry until the shard is added.
+	err = retryUntil(ctx, func() error {
+		return client.Database("admin").RunCommand(ctx, bson.D{{Key: "addShard", Value: "shard0/shard0:27017"}}).Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("addShard: %w", err)
+	}
+
+	return client, nil
+}
+
+// startClusterNode starts a MongoDB process on the cluster network under the given alias.
+func startClusterNode(ctx context.Context, nw *testcontainers.DockerNetwork, alias string, cmd ...string) (testcontainers.Container, error) {
+	req := testcontainers.ContainerRequest{
+		Image:          clusterImage,
+		ExposedPorts:   []string{"27017/tcp"},
+		Cmd:            cmd,
+		Networks:       []string{nw.Name},
+		NetworkAliases: map[string][]string{nw.Name: {alias}},
+		WaitingFor:     wait.ForListeningPort("27017/tcp").WithStartupTimeout(60 * time.Second),
+	}
+
+	return testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+}
+
+// initiateReplicaSet initiates a single-member replica set and waits until the member is primary.
+func initiateReplicaSet(ctx context.Context, container testcontainers.Container, name, host string, configsvr bool) error {
+	client, err := connectDirect(ctx, container)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Disconnect(ctx) }()
+
+	config := bson.D{
+		{Key: "_id", Value: name},
+		{Key: "members", Value: bson.A{bson.D{{Key: "_id", Value: 0}, {Key: "host", Value: host}}}},
+	}
+	if configsvr {
+		config = append(config, bson.E{Key: "configsvr", Value: true})
+	}
+	admin := client.Database("admin")
+	if err := admin.RunCommand(ctx, bson.D{{Key: "replSetInitiate", Value: config}}).Err(); err != nil {
+		return fmt.Errorf("replSetInitiate: %w", err)
+	}
+
+	return retryUntil(ctx, func() error {
+		var hello struct {
+			IsWritablePrimary bool `bson:"isWritablePrimary"`
+		}
+		if err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+			return err
+		}
+		if !hello.IsWritablePrimary {
+			return fmt.Errorf("%s is not primary yet", host)
+		}
+		return nil
+	})
+}
+
+// connectDirect connects to a single container without server discovery.
+func connectDirect(ctx context.Context, container testcontainers.Container) (*mongo.Client, error) {
+	host, err := container.Host(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mapped, err := container.MappedPort(ctx, "27017/tcp")
+	if err != nil {
+		return nil, err
+	}
+
+	connStr := fmt.Sprintf("mongodb://%s:%s/?directConnection=true", host, mapped.Port())
+	client, err := mongo.Connect(options.Client().ApplyURI(connStr))
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify connection
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		return nil, fmt.Errorf("ping failed: %w", err)
+	}
+
+	return client, nil
+}
+
+// retryUntil calls fn until it succeeds or clusterReadyTimeout elapses.
+func retryUntil(ctx context.Context, fn func() error) error {
+	deadline := time.Now().Add(clusterReadyTimeout)
+	for {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
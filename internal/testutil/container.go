@@ -20,6 +20,7 @@ import (
 type TestDB struct {
 	Name   string
 	Client *mongo.Client
+	URI    string // connection string, for tests that need a client with their own options
 }
 
 var (
@@ -153,7 +154,7 @@ func setupMongoDB(ctx context.Context, name, image string) (TestDB, error) {
 		return TestDB{}, fmt.Errorf("ping failed: %w", err)
 	}
 
-	return TestDB{Name: name, Client: client}, nil
+	return TestDB{Name: name, Client: client, URI: connStr}, nil
 }
 
 func setupDocumentDB(ctx context.Context) (TestDB, error) {
@@ -201,7 +202,7 @@ func setupDocumentDB(ctx context.Context) (TestDB, error) {
 		return TestDB{}, fmt.Errorf("ping failed: %w", err)
 	}
 
-	return TestDB{Name: "documentdb", Client: client}, nil
+	return TestDB{Name: "documentdb", Client: client, URI: connStr}, nil
 }
 
 // CleanupDatabase drops the specified database after a test.
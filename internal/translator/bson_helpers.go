@@ -3,6 +3,7 @@ package translator
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"strconv"
 	"time"
 
@@ -77,9 +78,13 @@ func convertDate(args []ast.Node) (any, error) {
 	case *ast.StringLiteral:
 		return parseDateTime(a.Value)
 	case *ast.NumberLiteral:
-		ts, err := strconv.ParseInt(a.Value, 10, 64)
+		n, err := parseNumber(a.Value)
 		if err != nil {
-			return nil, fmt.Errorf("invalid timestamp: %w", err)
+			return nil, err
+		}
+		ts, ok := ToInt64(n)
+		if !ok || ts > maxDateMillis || ts < -maxDateMillis {
+			return nil, fmt.Errorf("invalid timestamp: %s", a.Value)
 		}
 		return bson.DateTime(ts), nil
 	default:
@@ -87,23 +92,6 @@ func convertDate(args []ast.Node) (any, error) {
 	}
 }
 
-// parseDateTime parses various date formats to bson.DateTime.
-func parseDateTime(s string) (bson.DateTime, error) {
-	formats := []string{
-		time.RFC3339,
-		time.RFC3339Nano,
-		"2006-01-02T15:04:05.000Z",
-		"2006-01-02T15:04:05Z",
-		"2006-01-02",
-	}
-	for _, format := range formats {
-		if t, err := time.Parse(format, s); err == nil {
-			return bson.DateTime(t.UnixMilli()), nil
-		}
-	}
-	return 0, fmt.Errorf("invalid date format: %s", s)
-}
-
 func convertUUID(args []ast.Node) (bson.Binary, error) {
 	if len(args) == 0 {
 		return bson.Binary{}, fmt.Errorf("UUID requires a string argument")
@@ -128,7 +116,15 @@ func convertLong(args []ast.Node) (int64, error) {
 	}
 	switch a := args[0].(type) {
 	case *ast.NumberLiteral:
-		return strconv.ParseInt(a.Value, 10, 64)
+		n, err := parseNumber(a.Value)
+		if err != nil {
+			return 0, err
+		}
+		i, ok := ToInt64(n)
+		if !ok {
+			return 0, fmt.Errorf("Long() argument %s is not a 64-bit integer", a.Value)
+		}
+		return i, nil
 	case *ast.StringLiteral:
 		return strconv.ParseInt(a.Value, 10, 64)
 	default:
@@ -140,20 +136,26 @@ func convertInt32(args []ast.Node) (int32, error) {
 	if len(args) == 0 {
 		return 0, nil
 	}
-	var numStr string
 	switch a := args[0].(type) {
 	case *ast.NumberLiteral:
-		numStr = a.Value
+		n, err := parseNumber(a.Value)
+		if err != nil {
+			return 0, err
+		}
+		i, ok := ToInt32(n)
+		if !ok {
+			return 0, fmt.Errorf("Int32() argument %s is not a 32-bit integer", a.Value)
+		}
+		return i, nil
 	case *ast.StringLiteral:
-		numStr = a.Value
+		i, err := strconv.ParseInt(a.Value, 10, 32)
+		if err != nil {
+			return 0, err
+		}
+		return int32(i), nil
 	default:
 		return 0, fmt.Errorf("Int32() argument must be a number or string")
 	}
-	i, err := strconv.ParseInt(numStr, 10, 32)
-	if err != nil {
-		return 0, err
-	}
-	return int32(i), nil
 }
 
 func convertDouble(args []ast.Node) (float64, error) {
@@ -164,7 +166,17 @@ func convertDouble(args []ast.Node) (float64, error) {
 	if !ok {
 		return 0, fmt.Errorf("Double() argument must be a number")
 	}
-	return strconv.ParseFloat(num.Value, 64)
+	n, err := parseNumber(num.Value)
+	if err != nil {
+		return 0, err
+	}
+	switch n := n.(type) {
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	}
+	return n.(float64), nil
 }
 
 func convertDecimal128(args []ast.Node) (bson.Decimal128, error) {
@@ -202,15 +214,28 @@ func convertTimestamp(args []ast.Node) (bson.Timestamp, error) {
 	if !ok {
 		return bson.Timestamp{}, fmt.Errorf("timestamp i must be a number")
 	}
-	t, err := strconv.ParseUint(tNum.Value, 10, 32)
+	t, err := parseUint32(tNum.Value)
 	if err != nil {
 		return bson.Timestamp{}, fmt.Errorf("invalid Timestamp t value: %w", err)
 	}
-	i, err := strconv.ParseUint(iNum.Value, 10, 32)
+	i, err := parseUint32(iNum.Value)
 	if err != nil {
 		return bson.Timestamp{}, fmt.Errorf("invalid Timestamp i value: %w", err)
 	}
-	return bson.Timestamp{T: uint32(t), I: uint32(i)}, nil
+	return bson.Timestamp{T: t, I: i}, nil
+}
+
+// parseUint32 parses a number literal that must be an unsigned 32-bit integer.
+func parseUint32(s string) (uint32, error) {
+	n, err := parseNumber(s)
+	if err != nil {
+		return 0, err
+	}
+	i, ok := ToInt64(n)
+	if !ok || i < 0 || i > math.MaxUint32 {
+		return 0, fmt.Errorf("%s is not an unsigned 32-bit integer", s)
+	}
+	return uint32(i), nil
 }
 
 func convertTimestampDoc(doc *ast.Document) (bson.Timestamp, error) {
@@ -222,25 +247,15 @@ func convertTimestampDoc(doc *ast.Document) (bson.Timestamp, error) {
 	var hasT, hasI bool
 	for _, elem := range d {
 		switch elem.Key {
-		case "t":
-			if v, ok := elem.Value.(int32); ok {
-				t = uint32(v)
-				hasT = true
-			} else if v, ok := elem.Value.(int64); ok {
-				t = uint32(v)
-				hasT = true
-			} else {
-				return bson.Timestamp{}, fmt.Errorf("timestamp t must be an integer")
+		case "t", "i":
+			n, ok := ToInt64(elem.Value)
+			if !ok || n < 0 || n > math.MaxUint32 {
+				return bson.Timestamp{}, fmt.Errorf("timestamp %s must be an unsigned 32-bit integer", elem.Key)
 			}
-		case "i":
-			if v, ok := elem.Value.(int32); ok {
-				i = uint32(v)
-				hasI = true
-			} else if v, ok := elem.Value.(int64); ok {
-				i = uint32(v)
-				hasI = true
+			if elem.Key == "t" {
+				t, hasT = uint32(n), true
 			} else {
-				return bson.Timestamp{}, fmt.Errorf("timestamp i must be an integer")
+				i, hasI = uint32(n), true
 			}
 		}
 	}
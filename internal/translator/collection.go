@@ -2,8 +2,8 @@ package translator
 
 import (
 	"fmt"
-	"strconv"
 
+	"github.com/bytebase/gomongo/types"
 	"github.com/bytebase/omni/mongo/ast"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -41,7 +41,7 @@ func extractFindArgs(op *Operation, args []ast.Node) error {
 	}
 
 	if len(args) > 3 {
-		return fmt.Errorf("find() takes at most 3 arguments")
+		return errorAt(args[3], "find() takes at most 3 arguments")
 	}
 	return nil
 }
@@ -79,7 +79,7 @@ func extractFindOneArgs(op *Operation, args []ast.Node) error {
 	}
 
 	if len(args) > 3 {
-		return fmt.Errorf("findOne() takes at most 3 arguments")
+		return errorAt(args[3], "findOne() takes at most 3 arguments")
 	}
 	return nil
 }
@@ -94,13 +94,13 @@ func extractFindOptions(op *Operation, methodName string, options bson.D) error
 			if doc, ok := opt.Value.(bson.D); ok {
 				op.Max = doc
 			} else {
-				return fmt.Errorf("%s() max must be a document", methodName)
+				return optionError(opt.Key, "%s() max must be a document", methodName)
 			}
 		case "min":
 			if doc, ok := opt.Value.(bson.D); ok {
 				op.Min = doc
 			} else {
-				return fmt.Errorf("%s() min must be a document", methodName)
+				return optionError(opt.Key, "%s() min must be a document", methodName)
 			}
 		case "maxTimeMS":
 			if val, ok := opt.Value.(int32); ok {
@@ -109,7 +109,7 @@ func extractFindOptions(op *Operation, methodName string, options bson.D) error
 			} else if val, ok := opt.Value.(int64); ok {
 				op.MaxTimeMS = &val
 			} else {
-				return fmt.Errorf("%s() maxTimeMS must be a number", methodName)
+				return optionError(opt.Key, "%s() maxTimeMS must be a number", methodName)
 			}
 		default:
 			return &UnsupportedOptionError{
@@ -137,13 +137,26 @@ func extractLimit(op *Operation, args []ast.Node) error {
 	if len(args) == 0 {
 		return fmt.Errorf("limit() requires a number argument")
 	}
-	num, ok := args[0].(*ast.NumberLiteral)
-	if !ok {
-		return fmt.Errorf("limit() requires a number argument")
-	}
-	limit, err := strconv.ParseInt(num.Value, 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid limit: %w", err)
+	var limit int64
+	switch n := args[0].(type) {
+	case *ast.NumberLiteral:
+		num, err := parseNumber(n.Value)
+		if err != nil {
+			return errorAt(n, "invalid limit: %w", err)
+		}
+		v, ok := ToInt64(num)
+		if !ok {
+			return errorAt(n, "limit() requires an integer argument")
+		}
+		limit = v
+	case *param:
+		v, ok := n.integer()
+		if !ok {
+			return errorAt(n, "limit() requires an integer argument")
+		}
+		limit = v
+	default:
+		return errorAt(args[0], "limit() requires a number argument")
 	}
 	op.Limit = &limit
 	return nil
@@ -153,13 +166,26 @@ func extractSkip(op *Operation, args []ast.Node) error {
 	if len(args) == 0 {
 		return fmt.Errorf("skip() requires a number argument")
 	}
-	num, ok := args[0].(*ast.NumberLiteral)
-	if !ok {
-		return fmt.Errorf("skip() requires a number argument")
-	}
-	skip, err := strconv.ParseInt(num.Value, 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid skip: %w", err)
+	var skip int64
+	switch n := args[0].(type) {
+	case *ast.NumberLiteral:
+		num, err := parseNumber(n.Value)
+		if err != nil {
+			return errorAt(n, "invalid skip: %w", err)
+		}
+		v, ok := ToInt64(num)
+		if !ok {
+			return errorAt(n, "skip() requires an integer argument")
+		}
+		skip = v
+	case *param:
+		v, ok := n.integer()
+		if !ok {
+			return errorAt(n, "skip() requires an integer argument")
+		}
+		skip = v
+	default:
+		return errorAt(args[0], "skip() requires a number argument")
 	}
 	op.Skip = &skip
 	return nil
@@ -191,7 +217,7 @@ func extractCursorHint(op *Operation, args []ast.Node) error {
 		}
 		op.Hint = doc
 	default:
-		return fmt.Errorf("hint() argument must be a string or document")
+		return errorAt(args[0], "hint() argument must be a string or document")
 	}
 	return nil
 }
@@ -227,15 +253,22 @@ func extractAggregateArgs(op *Operation, args []ast.Node) error {
 	}
 
 	// First argument: pipeline array
-	arr, ok := args[0].(*ast.Array)
-	if !ok {
-		return fmt.Errorf("aggregate() requires an array argument, got %T", args[0])
-	}
-	pipeline, err := convertArray(arr)
-	if err != nil {
-		return fmt.Errorf("invalid aggregation pipeline: %w", err)
+	switch a := args[0].(type) {
+	case *ast.Array:
+		pipeline, err := convertArray(a)
+		if err != nil {
+			return fmt.Errorf("invalid aggregation pipeline: %w", err)
+		}
+		op.Pipeline = pipeline
+	case *param:
+		pipeline, ok := a.value.(bson.A)
+		if !ok {
+			return errorAt(a, "aggregate() requires an array argument, got %T", a.value)
+		}
+		op.Pipeline = pipeline
+	default:
+		return errorAt(args[0], "aggregate() requires an array argument, got %T", args[0])
 	}
-	op.Pipeline = pipeline
 
 	// Second argument: options (optional)
 	if len(args) >= 2 {
@@ -254,7 +287,7 @@ func extractAggregateArgs(op *Operation, args []ast.Node) error {
 				} else if val, ok := opt.Value.(int64); ok {
 					op.MaxTimeMS = &val
 				} else {
-					return fmt.Errorf("aggregate() maxTimeMS must be a number")
+					return optionError(opt.Key, "aggregate() maxTimeMS must be a number")
 				}
 			default:
 				return &UnsupportedOptionError{
@@ -266,7 +299,7 @@ func extractAggregateArgs(op *Operation, args []ast.Node) error {
 	}
 
 	if len(args) > 2 {
-		return fmt.Errorf("aggregate() takes at most 2 arguments")
+		return errorAt(args[2], "aggregate() takes at most 2 arguments")
 	}
 	return nil
 }
@@ -299,6 +332,9 @@ func extractCountDocumentsArgs(op *Operation, args []ast.Node) error {
 					op.Limit = &limit
 				} else if val, ok := elem.Value.(int64); ok {
 					op.Limit = &val
+				} else {
+					op.warn(types.WarningIgnoredOption, keyOffset(args[1], elem.Key),
+						"countDocuments() limit must be an integer and was ignored")
 				}
 			case "skip":
 				if val, ok := elem.Value.(int32); ok {
@@ -306,6 +342,9 @@ func extractCountDocumentsArgs(op *Operation, args []ast.Node) error {
 					op.Skip = &skip
 				} else if val, ok := elem.Value.(int64); ok {
 					op.Skip = &val
+				} else {
+					op.warn(types.WarningIgnoredOption, keyOffset(args[1], elem.Key),
+						"countDocuments() skip must be an integer and was ignored")
 				}
 			case "maxTimeMS":
 				if val, ok := elem.Value.(int32); ok {
@@ -314,7 +353,7 @@ func extractCountDocumentsArgs(op *Operation, args []ast.Node) error {
 				} else if val, ok := elem.Value.(int64); ok {
 					op.MaxTimeMS = &val
 				} else {
-					return fmt.Errorf("countDocuments() maxTimeMS must be a number")
+					return optionError(elem.Key, "countDocuments() maxTimeMS must be a number")
 				}
 			default:
 				return &UnsupportedOptionError{
@@ -347,7 +386,7 @@ func extractEstimatedDocumentCountArgs(op *Operation, args []ast.Node) error {
 			} else if val, ok := opt.Value.(int64); ok {
 				op.MaxTimeMS = &val
 			} else {
-				return fmt.Errorf("estimatedDocumentCount() maxTimeMS must be a number")
+				return optionError(opt.Key, "estimatedDocumentCount() maxTimeMS must be a number")
 			}
 		default:
 			return &UnsupportedOptionError{
@@ -395,7 +434,7 @@ func extractDistinctArgs(op *Operation, args []ast.Node) error {
 				} else if val, ok := opt.Value.(int64); ok {
 					op.MaxTimeMS = &val
 				} else {
-					return fmt.Errorf("distinct() maxTimeMS must be a number")
+					return optionError(opt.Key, "distinct() maxTimeMS must be a number")
 				}
 			default:
 				return &UnsupportedOptionError{
@@ -407,7 +446,7 @@ func extractDistinctArgs(op *Operation, args []ast.Node) error {
 	}
 
 	if len(args) > 3 {
-		return fmt.Errorf("distinct() takes at most 3 arguments")
+		return errorAt(args[3], "distinct() takes at most 3 arguments")
 	}
 	return nil
 }
@@ -422,7 +461,7 @@ func extractInsertOneArgs(op *Operation, args []ast.Node) error {
 	if err != nil {
 		// Provide a better error for non-document
 		if _, ok := args[0].(*ast.Document); !ok {
-			return fmt.Errorf("insertOne() document must be an object")
+			return errorAt(args[0], "insertOne() document must be an object")
 		}
 		return err
 	}
@@ -440,15 +479,16 @@ func extractInsertOneArgs(op *Operation, args []ast.Node) error {
 				if val, ok := opt.Value.(bool); ok {
 					op.BypassDocumentValidation = &val
 				} else {
-					return fmt.Errorf("insertOne() bypassDocumentValidation must be a boolean")
+					return optionError(opt.Key, "insertOne() bypassDocumentValidation must be a boolean")
 				}
 			case "comment":
 				op.Comment = opt.Value
 			case "writeConcern":
 				if doc, ok := opt.Value.(bson.D); ok {
 					op.WriteConcern = doc
+					op.warnWriteConcern(args, opt.Key, doc)
 				} else {
-					return fmt.Errorf("insertOne() writeConcern must be a document")
+					return optionError(opt.Key, "insertOne() writeConcern must be a document")
 				}
 			default:
 				return &UnsupportedOptionError{
@@ -460,7 +500,7 @@ func extractInsertOneArgs(op *Operation, args []ast.Node) error {
 	}
 
 	if len(args) > 2 {
-		return fmt.Errorf("insertOne() takes at most 2 arguments")
+		return errorAt(args[2], "insertOne() takes at most 2 arguments")
 	}
 	return nil
 }
@@ -471,19 +511,18 @@ func extractInsertManyArgs(op *Operation, args []ast.Node) error {
 	}
 
 	// First argument: array of documents (required)
-	arr, ok := args[0].(*ast.Array)
-	if !ok {
-		return fmt.Errorf("insertMany() requires an array argument")
-	}
-	bsonArr, err := convertArray(arr)
+	bsonArr, err := requireArray(args, 0, "insertMany() documents")
 	if err != nil {
+		if _, ok := args[0].(*ast.Array); !ok {
+			return errorAt(args[0], "insertMany() requires an array argument")
+		}
 		return fmt.Errorf("invalid documents array: %w", err)
 	}
 	var docs []bson.D
 	for i, elem := range bsonArr {
 		doc, ok := elem.(bson.D)
 		if !ok {
-			return fmt.Errorf("insertMany() element %d must be a document", i)
+			return elementError(args[0], i, "insertMany() element %d must be a document", i)
 		}
 		docs = append(docs, doc)
 	}
@@ -501,21 +540,22 @@ func extractInsertManyArgs(op *Operation, args []ast.Node) error {
 				if val, ok := opt.Value.(bool); ok {
 					op.Ordered = &val
 				} else {
-					return fmt.Errorf("insertMany() ordered must be a boolean")
+					return optionError(opt.Key, "insertMany() ordered must be a boolean")
 				}
 			case "bypassDocumentValidation":
 				if val, ok := opt.Value.(bool); ok {
 					op.BypassDocumentValidation = &val
 				} else {
-					return fmt.Errorf("insertMany() bypassDocumentValidation must be a boolean")
+					return optionError(opt.Key, "insertMany() bypassDocumentValidation must be a boolean")
 				}
 			case "comment":
 				op.Comment = opt.Value
 			case "writeConcern":
 				if doc, ok := opt.Value.(bson.D); ok {
 					op.WriteConcern = doc
+					op.warnWriteConcern(args, opt.Key, doc)
 				} else {
-					return fmt.Errorf("insertMany() writeConcern must be a document")
+					return optionError(opt.Key, "insertMany() writeConcern must be a document")
 				}
 			default:
 				return &UnsupportedOptionError{
@@ -527,7 +567,7 @@ func extractInsertManyArgs(op *Operation, args []ast.Node) error {
 	}
 
 	if len(args) > 2 {
-		return fmt.Errorf("insertMany() takes at most 2 arguments")
+		return errorAt(args[2], "insertMany() takes at most 2 arguments")
 	}
 	return nil
 }
@@ -558,8 +598,13 @@ func extractUpdateArgs(op *Operation, methodName string, args []ast.Node) error
 			return fmt.Errorf("invalid update pipeline: %w", err)
 		}
 		op.Update = pipeline
+	case *param:
+		if !isUpdate(u.value) {
+			return errorAt(args[1], "%s() update must be a document or array", methodName)
+		}
+		op.Update = u.value
 	default:
-		return fmt.Errorf("%s() update must be a document or array", methodName)
+		return errorAt(args[1], "%s() update must be a document or array", methodName)
 	}
 
 	// Third argument: options (optional)
@@ -568,51 +613,52 @@ func extractUpdateArgs(op *Operation, methodName string, args []ast.Node) error
 		if err != nil {
 			return err
 		}
-		if err := extractUpdateOptions(op, methodName, options); err != nil {
+		if err := extractUpdateOptions(op, methodName, args, options); err != nil {
 			return err
 		}
 	}
 
 	if len(args) > 3 {
-		return fmt.Errorf("%s() takes at most 3 arguments", methodName)
+		return errorAt(args[3], "%s() takes at most 3 arguments", methodName)
 	}
 	return nil
 }
 
-func extractUpdateOptions(op *Operation, methodName string, options bson.D) error {
+func extractUpdateOptions(op *Operation, methodName string, args []ast.Node, options bson.D) error {
 	for _, opt := range options {
 		switch opt.Key {
 		case "upsert":
 			if val, ok := opt.Value.(bool); ok {
 				op.Upsert = &val
 			} else {
-				return fmt.Errorf("%s() upsert must be a boolean", methodName)
+				return optionError(opt.Key, "%s() upsert must be a boolean", methodName)
 			}
 		case "hint":
 			op.Hint = opt.Value
 		case "collation":
 			if doc, ok := opt.Value.(bson.D); ok {
 				op.Collation = doc
+				op.warnCollation(args, opt.Key, doc)
 			} else {
-				return fmt.Errorf("%s() collation must be a document", methodName)
+				return optionError(opt.Key, "%s() collation must be a document", methodName)
 			}
 		case "arrayFilters":
 			if arr, ok := opt.Value.(bson.A); ok {
 				op.ArrayFilters = arr
 			} else {
-				return fmt.Errorf("%s() arrayFilters must be an array", methodName)
+				return optionError(opt.Key, "%s() arrayFilters must be an array", methodName)
 			}
 		case "let":
 			if doc, ok := opt.Value.(bson.D); ok {
 				op.Let = doc
 			} else {
-				return fmt.Errorf("%s() let must be a document", methodName)
+				return optionError(opt.Key, "%s() let must be a document", methodName)
 			}
 		case "bypassDocumentValidation":
 			if val, ok := opt.Value.(bool); ok {
 				op.BypassDocumentValidation = &val
 			} else {
-				return fmt.Errorf("%s() bypassDocumentValidation must be a boolean", methodName)
+				return optionError(opt.Key, "%s() bypassDocumentValidation must be a boolean", methodName)
 			}
 		case "comment":
 			op.Comment = opt.Value
@@ -626,13 +672,14 @@ func extractUpdateOptions(op *Operation, methodName string, options bson.D) erro
 			if doc, ok := opt.Value.(bson.D); ok {
 				op.Sort = doc
 			} else {
-				return fmt.Errorf("%s() sort must be a document", methodName)
+				return optionError(opt.Key, "%s() sort must be a document", methodName)
 			}
 		case "writeConcern":
 			if doc, ok := opt.Value.(bson.D); ok {
 				op.WriteConcern = doc
+				op.warnWriteConcern(args, opt.Key, doc)
 			} else {
-				return fmt.Errorf("%s() writeConcern must be a document", methodName)
+				return optionError(opt.Key, "%s() writeConcern must be a document", methodName)
 			}
 		default:
 			return &UnsupportedOptionError{
@@ -675,27 +722,28 @@ func extractReplaceOneArgs(op *Operation, args []ast.Node) error {
 				if val, ok := opt.Value.(bool); ok {
 					op.Upsert = &val
 				} else {
-					return fmt.Errorf("replaceOne() upsert must be a boolean")
+					return optionError(opt.Key, "replaceOne() upsert must be a boolean")
 				}
 			case "hint":
 				op.Hint = opt.Value
 			case "collation":
 				if doc, ok := opt.Value.(bson.D); ok {
 					op.Collation = doc
+					op.warnCollation(args, opt.Key, doc)
 				} else {
-					return fmt.Errorf("replaceOne() collation must be a document")
+					return optionError(opt.Key, "replaceOne() collation must be a document")
 				}
 			case "let":
 				if doc, ok := opt.Value.(bson.D); ok {
 					op.Let = doc
 				} else {
-					return fmt.Errorf("replaceOne() let must be a document")
+					return optionError(opt.Key, "replaceOne() let must be a document")
 				}
 			case "bypassDocumentValidation":
 				if val, ok := opt.Value.(bool); ok {
 					op.BypassDocumentValidation = &val
 				} else {
-					return fmt.Errorf("replaceOne() bypassDocumentValidation must be a boolean")
+					return optionError(opt.Key, "replaceOne() bypassDocumentValidation must be a boolean")
 				}
 			case "comment":
 				op.Comment = opt.Value
@@ -703,13 +751,14 @@ func extractReplaceOneArgs(op *Operation, args []ast.Node) error {
 				if doc, ok := opt.Value.(bson.D); ok {
 					op.Sort = doc
 				} else {
-					return fmt.Errorf("replaceOne() sort must be a document")
+					return optionError(opt.Key, "replaceOne() sort must be a document")
 				}
 			case "writeConcern":
 				if doc, ok := opt.Value.(bson.D); ok {
 					op.WriteConcern = doc
+					op.warnWriteConcern(args, opt.Key, doc)
 				} else {
-					return fmt.Errorf("replaceOne() writeConcern must be a document")
+					return optionError(opt.Key, "replaceOne() writeConcern must be a document")
 				}
 			default:
 				return &UnsupportedOptionError{
@@ -721,7 +770,7 @@ func extractReplaceOneArgs(op *Operation, args []ast.Node) error {
 	}
 
 	if len(args) > 3 {
-		return fmt.Errorf("replaceOne() takes at most 3 arguments")
+		return errorAt(args[3], "replaceOne() takes at most 3 arguments")
 	}
 	return nil
 }
@@ -751,22 +800,24 @@ func extractDeleteArgs(op *Operation, methodName string, args []ast.Node) error
 			case "collation":
 				if doc, ok := opt.Value.(bson.D); ok {
 					op.Collation = doc
+					op.warnCollation(args, opt.Key, doc)
 				} else {
-					return fmt.Errorf("%s() collation must be a document", methodName)
+					return optionError(opt.Key, "%s() collation must be a document", methodName)
 				}
 			case "let":
 				if doc, ok := opt.Value.(bson.D); ok {
 					op.Let = doc
 				} else {
-					return fmt.Errorf("%s() let must be a document", methodName)
+					return optionError(opt.Key, "%s() let must be a document", methodName)
 				}
 			case "comment":
 				op.Comment = opt.Value
 			case "writeConcern":
 				if doc, ok := opt.Value.(bson.D); ok {
 					op.WriteConcern = doc
+					op.warnWriteConcern(args, opt.Key, doc)
 				} else {
-					return fmt.Errorf("%s() writeConcern must be a document", methodName)
+					return optionError(opt.Key, "%s() writeConcern must be a document", methodName)
 				}
 			default:
 				return &UnsupportedOptionError{
@@ -778,7 +829,7 @@ func extractDeleteArgs(op *Operation, methodName string, args []ast.Node) error
 	}
 
 	if len(args) > 2 {
-		return fmt.Errorf("%s() takes at most 2 arguments", methodName)
+		return errorAt(args[2], "%s() takes at most 2 arguments", methodName)
 	}
 	return nil
 }
@@ -825,8 +876,13 @@ func extractFindOneAndModifyArgs(op *Operation, methodName string, args []ast.No
 					return fmt.Errorf("invalid update pipeline: %w", err)
 				}
 				op.Update = pipeline
+			case *param:
+				if !isUpdate(u.value) {
+					return errorAt(args[1], "%s() update must be a document or array", methodName)
+				}
+				op.Update = u.value
 			default:
-				return fmt.Errorf("%s() update must be a document or array", methodName)
+				return errorAt(args[1], "%s() update must be a document or array", methodName)
 			}
 		}
 		optionsArgIdx = 2
@@ -838,19 +894,19 @@ func extractFindOneAndModifyArgs(op *Operation, methodName string, args []ast.No
 		if err != nil {
 			return err
 		}
-		if err := extractFindOneAndModifyOptions(op, methodName, opts); err != nil {
+		if err := extractFindOneAndModifyOptions(op, methodName, args, opts); err != nil {
 			return err
 		}
 	}
 
 	maxArgs := optionsArgIdx + 1
 	if len(args) > maxArgs {
-		return fmt.Errorf("%s() takes at most %d arguments", methodName, maxArgs)
+		return errorAt(args[maxArgs], "%s() takes at most %d arguments", methodName, maxArgs)
 	}
 	return nil
 }
 
-func extractFindOneAndModifyOptions(op *Operation, methodName string, opts bson.D) error {
+func extractFindOneAndModifyOptions(op *Operation, methodName string, args []ast.Node, opts bson.D) error {
 	for _, opt := range opts {
 		switch opt.Key {
 		case "upsert":
@@ -860,36 +916,37 @@ func extractFindOneAndModifyOptions(op *Operation, methodName string, opts bson.
 			if val, ok := opt.Value.(bool); ok {
 				op.Upsert = &val
 			} else {
-				return fmt.Errorf("%s() upsert must be a boolean", methodName)
+				return optionError(opt.Key, "%s() upsert must be a boolean", methodName)
 			}
 		case "returnDocument":
 			if val, ok := opt.Value.(string); ok {
 				if val != "before" && val != "after" {
-					return fmt.Errorf("%s() returnDocument must be 'before' or 'after'", methodName)
+					return optionError(opt.Key, "%s() returnDocument must be 'before' or 'after'", methodName)
 				}
 				op.ReturnDocument = &val
 			} else {
-				return fmt.Errorf("%s() returnDocument must be a string", methodName)
+				return optionError(opt.Key, "%s() returnDocument must be a string", methodName)
 			}
 		case "projection":
 			if doc, ok := opt.Value.(bson.D); ok {
 				op.Projection = doc
 			} else {
-				return fmt.Errorf("%s() projection must be a document", methodName)
+				return optionError(opt.Key, "%s() projection must be a document", methodName)
 			}
 		case "sort":
 			if doc, ok := opt.Value.(bson.D); ok {
 				op.Sort = doc
 			} else {
-				return fmt.Errorf("%s() sort must be a document", methodName)
+				return optionError(opt.Key, "%s() sort must be a document", methodName)
 			}
 		case "hint":
 			op.Hint = opt.Value
 		case "collation":
 			if doc, ok := opt.Value.(bson.D); ok {
 				op.Collation = doc
+				op.warnCollation(args, opt.Key, doc)
 			} else {
-				return fmt.Errorf("%s() collation must be a document", methodName)
+				return optionError(opt.Key, "%s() collation must be a document", methodName)
 			}
 		case "arrayFilters":
 			if methodName == "findOneAndDelete" || methodName == "findOneAndReplace" {
@@ -898,13 +955,13 @@ func extractFindOneAndModifyOptions(op *Operation, methodName string, opts bson.
 			if arr, ok := opt.Value.(bson.A); ok {
 				op.ArrayFilters = arr
 			} else {
-				return fmt.Errorf("%s() arrayFilters must be an array", methodName)
+				return optionError(opt.Key, "%s() arrayFilters must be an array", methodName)
 			}
 		case "let":
 			if doc, ok := opt.Value.(bson.D); ok {
 				op.Let = doc
 			} else {
-				return fmt.Errorf("%s() let must be a document", methodName)
+				return optionError(opt.Key, "%s() let must be a document", methodName)
 			}
 		case "bypassDocumentValidation":
 			if methodName == "findOneAndDelete" {
@@ -913,15 +970,16 @@ func extractFindOneAndModifyOptions(op *Operation, methodName string, opts bson.
 			if val, ok := opt.Value.(bool); ok {
 				op.BypassDocumentValidation = &val
 			} else {
-				return fmt.Errorf("%s() bypassDocumentValidation must be a boolean", methodName)
+				return optionError(opt.Key, "%s() bypassDocumentValidation must be a boolean", methodName)
 			}
 		case "comment":
 			op.Comment = opt.Value
 		case "writeConcern":
 			if doc, ok := opt.Value.(bson.D); ok {
 				op.WriteConcern = doc
+				op.warnWriteConcern(args, opt.Key, doc)
 			} else {
-				return fmt.Errorf("%s() writeConcern must be a document", methodName)
+				return optionError(opt.Key, "%s() writeConcern must be a document", methodName)
 			}
 		default:
 			return &UnsupportedOptionError{
@@ -957,31 +1015,32 @@ func extractCreateIndexArgs(op *Operation, args []ast.Node) error {
 				if val, ok := opt.Value.(string); ok {
 					op.IndexName = val
 				} else {
-					return fmt.Errorf("createIndex() name must be a string")
+					return optionError(opt.Key, "createIndex() name must be a string")
 				}
 			case "unique":
 				if val, ok := opt.Value.(bool); ok {
 					op.IndexUnique = &val
 				} else {
-					return fmt.Errorf("createIndex() unique must be a boolean")
+					return optionError(opt.Key, "createIndex() unique must be a boolean")
 				}
 			case "sparse":
 				if val, ok := opt.Value.(bool); ok {
 					op.IndexSparse = &val
 				} else {
-					return fmt.Errorf("createIndex() sparse must be a boolean")
+					return optionError(opt.Key, "createIndex() sparse must be a boolean")
 				}
 			case "expireAfterSeconds":
 				if val, ok := ToInt32(opt.Value); ok {
 					op.IndexTTL = &val
 				} else {
-					return fmt.Errorf("createIndex() expireAfterSeconds must be a number")
+					return optionError(opt.Key, "createIndex() expireAfterSeconds must be a number")
 				}
 			case "background":
 				if _, ok := opt.Value.(bool); !ok {
-					return fmt.Errorf("createIndex() background must be a boolean")
+					return optionError(opt.Key, "createIndex() background must be a boolean")
 				}
-				// Silently ignore - deprecated and has no effect
+				op.warn(types.WarningIgnoredOption, keyOffset(args[1], opt.Key),
+					"createIndex() background is deprecated and was ignored")
 			default:
 				return &UnsupportedOptionError{
 					Method: "createIndex()",
@@ -992,7 +1051,7 @@ func extractCreateIndexArgs(op *Operation, args []ast.Node) error {
 	}
 
 	if len(args) > 2 {
-		return fmt.Errorf("createIndex() takes at most 2 arguments")
+		return errorAt(args[2], "createIndex() takes at most 2 arguments")
 	}
 	return nil
 }
@@ -1005,7 +1064,7 @@ func extractCreateIndexesArgs(op *Operation, args []ast.Node) error {
 	// First argument: array of index spec documents (required)
 	arr, ok := args[0].(*ast.Array)
 	if !ok {
-		return fmt.Errorf("createIndexes() requires an array argument")
+		return errorAt(args[0], "createIndexes() requires an array argument")
 	}
 	bsonArr, err := convertArray(arr)
 	if err != nil {
@@ -1016,7 +1075,7 @@ func extractCreateIndexesArgs(op *Operation, args []ast.Node) error {
 	for i, elem := range bsonArr {
 		doc, ok := elem.(bson.D)
 		if !ok {
-			return fmt.Errorf("createIndexes() element %d must be a document", i)
+			return elementError(arr, i, "createIndexes() element %d must be a document", i)
 		}
 		var keyDoc bson.D
 		for _, field := range doc {
@@ -1026,14 +1085,22 @@ func extractCreateIndexesArgs(op *Operation, args []ast.Node) error {
 			}
 		}
 		if len(keyDoc) == 0 {
-			return fmt.Errorf("createIndexes() element %d must have a non-empty 'key' document", i)
+			return elementError(arr, i, "createIndexes() element %d must have a non-empty 'key' document", i)
+		}
+		for _, field := range doc {
+			switch field.Key {
+			case "key", "name", "unique", "sparse", "expireAfterSeconds":
+			default:
+				op.warn(types.WarningIgnoredOption, keyOffset(arr.Elements[i], field.Key),
+					"createIndexes() option %s of index %d is not supported and was ignored", field.Key, i)
+			}
 		}
 		specs = append(specs, doc)
 	}
 	op.IndexSpecs = specs
 
 	if len(args) > 1 {
-		return fmt.Errorf("createIndexes() takes exactly 1 argument")
+		return errorAt(args[1], "createIndexes() takes exactly 1 argument")
 	}
 	return nil
 }
@@ -1053,7 +1120,7 @@ func extractDropIndexArgs(op *Operation, args []ast.Node) error {
 		}
 		op.IndexKeys = doc
 	default:
-		return fmt.Errorf("dropIndex() argument must be a string or document")
+		return errorAt(args[0], "dropIndex() argument must be a string or document")
 	}
 	return nil
 }
@@ -1077,13 +1144,13 @@ func extractDropIndexesArgs(op *Operation, args []ast.Node) error {
 		for i, elem := range arr {
 			name, ok := elem.(string)
 			if !ok {
-				return fmt.Errorf("dropIndexes() array element %d must be a string", i)
+				return elementError(a, i, "dropIndexes() array element %d must be a string", i)
 			}
 			indexNames = append(indexNames, name)
 		}
 		op.IndexNames = indexNames
 	default:
-		return fmt.Errorf("dropIndexes() argument must be a string or array")
+		return errorAt(args[0], "dropIndexes() argument must be a string or array")
 	}
 	return nil
 }
@@ -1104,14 +1171,14 @@ func extractRenameCollectionArgs(op *Operation, args []ast.Node) error {
 	if len(args) >= 2 {
 		boolNode, ok := args[1].(*ast.BoolLiteral)
 		if !ok {
-			return fmt.Errorf("renameCollection() dropTarget must be a boolean")
+			return errorAt(args[1], "renameCollection() dropTarget must be a boolean")
 		}
 		dropTarget := boolNode.Value
 		op.DropTarget = &dropTarget
 	}
 
 	if len(args) > 2 {
-		return fmt.Errorf("renameCollection() takes at most 2 arguments")
+		return errorAt(args[2], "renameCollection() takes at most 2 arguments")
 	}
 	return nil
 }
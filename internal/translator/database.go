@@ -2,6 +2,7 @@ package translator
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/bytebase/omni/mongo/ast"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -31,13 +32,13 @@ func extractGetCollectionInfosArgs(op *Operation, args []ast.Node) (*Operation,
 				if val, ok := opt.Value.(bool); ok {
 					op.NameOnly = &val
 				} else {
-					return nil, fmt.Errorf("getCollectionInfos() nameOnly must be a boolean")
+					return nil, optionError(opt.Key, "getCollectionInfos() nameOnly must be a boolean")
 				}
 			case "authorizedCollections":
 				if val, ok := opt.Value.(bool); ok {
 					op.AuthorizedCollections = &val
 				} else {
-					return nil, fmt.Errorf("getCollectionInfos() authorizedCollections must be a boolean")
+					return nil, optionError(opt.Key, "getCollectionInfos() authorizedCollections must be a boolean")
 				}
 			default:
 				return nil, &UnsupportedOptionError{
@@ -49,7 +50,7 @@ func extractGetCollectionInfosArgs(op *Operation, args []ast.Node) (*Operation,
 	}
 
 	if len(args) > 2 {
-		return nil, fmt.Errorf("getCollectionInfos() takes at most 2 arguments")
+		return nil, errorAt(args[2], "getCollectionInfos() takes at most 2 arguments")
 	}
 	return op, nil
 }
@@ -78,37 +79,37 @@ func extractCreateCollectionArgs(op *Operation, args []ast.Node) (*Operation, er
 				if val, ok := opt.Value.(bool); ok {
 					op.Capped = &val
 				} else {
-					return nil, fmt.Errorf("createCollection() capped must be a boolean")
+					return nil, optionError(opt.Key, "createCollection() capped must be a boolean")
 				}
 			case "size":
 				if val, ok := ToInt64(opt.Value); ok {
 					op.CollectionSize = &val
 				} else {
-					return nil, fmt.Errorf("createCollection() size must be a number")
+					return nil, optionError(opt.Key, "createCollection() size must be a number")
 				}
 			case "max":
 				if val, ok := ToInt64(opt.Value); ok {
 					op.CollectionMax = &val
 				} else {
-					return nil, fmt.Errorf("createCollection() max must be a number")
+					return nil, optionError(opt.Key, "createCollection() max must be a number")
 				}
 			case "validator":
 				if doc, ok := opt.Value.(bson.D); ok {
 					op.Validator = doc
 				} else {
-					return nil, fmt.Errorf("createCollection() validator must be a document")
+					return nil, optionError(opt.Key, "createCollection() validator must be a document")
 				}
 			case "validationLevel":
 				if val, ok := opt.Value.(string); ok {
 					op.ValidationLevel = val
 				} else {
-					return nil, fmt.Errorf("createCollection() validationLevel must be a string")
+					return nil, optionError(opt.Key, "createCollection() validationLevel must be a string")
 				}
 			case "validationAction":
 				if val, ok := opt.Value.(string); ok {
 					op.ValidationAction = val
 				} else {
-					return nil, fmt.Errorf("createCollection() validationAction must be a string")
+					return nil, optionError(opt.Key, "createCollection() validationAction must be a string")
 				}
 			default:
 				return nil, &UnsupportedOptionError{
@@ -120,7 +121,165 @@ func extractCreateCollectionArgs(op *Operation, args []ast.Node) (*Operation, er
 	}
 
 	if len(args) > 2 {
-		return nil, fmt.Errorf("createCollection() takes at most 2 arguments")
+		return nil, errorAt(args[2], "createCollection() takes at most 2 arguments")
+	}
+	return op, nil
+}
+
+func extractCurrentOpArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) == 0 {
+		return op, nil
+	}
+
+	// First argument: filter document, or true to include idle and system operations
+	switch a := args[0].(type) {
+	case *ast.BoolLiteral:
+		if a.Value {
+			op.Filter = bson.D{{Key: "$all", Value: true}}
+		}
+	case *ast.Document:
+		filter, err := convertDocument(a)
+		if err != nil {
+			return nil, fmt.Errorf("invalid currentOp() filter: %w", err)
+		}
+		op.Filter = filter
+	default:
+		return nil, errorAt(args[0], "currentOp() argument must be a document or boolean")
+	}
+
+	if len(args) > 1 {
+		return nil, errorAt(args[1], "currentOp() takes at most 1 argument")
+	}
+	return op, nil
+}
+
+func extractKillOpArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("killOp() requires an operation id")
+	}
+
+	// First argument: opid (number on mongod, "shard:opid" string on mongos)
+	switch a := args[0].(type) {
+	case *ast.NumberLiteral:
+		if a.IsFloat {
+			return nil, errorAt(a, "killOp() operation id must be an integer")
+		}
+		val, err := parseNumber(a.Value)
+		if err != nil {
+			return nil, locate(a.Loc, err)
+		}
+		op.OpID = val
+	case *ast.StringLiteral:
+		op.OpID = a.Value
+	default:
+		return nil, errorAt(args[0], "killOp() operation id must be a number or string")
+	}
+
+	if len(args) > 1 {
+		return nil, errorAt(args[1], "killOp() takes exactly 1 argument")
+	}
+	return op, nil
+}
+
+// profilingInt32 converts a setProfilingLevel() level or slowms value,
+// reporting whether it is not a number, not a whole number or out of range.
+func profilingInt32(name string, v any) (int32, error) {
+	switch n := v.(type) {
+	case int32:
+		return n, nil
+	case int64, float64:
+		if f, ok := n.(float64); ok && f != math.Trunc(f) {
+			return 0, fmt.Errorf("setProfilingLevel() %s must be an integer", name)
+		}
+		if i, ok := ToInt32(n); ok {
+			return i, nil
+		}
+		return 0, fmt.Errorf("setProfilingLevel() %s %v is out of range", name, n)
+	}
+	return 0, fmt.Errorf("setProfilingLevel() %s must be a number", name)
+}
+
+func extractSetProfilingLevelArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("setProfilingLevel() requires a profiling level")
+	}
+
+	// First argument: level (required)
+	value, err := convertNode(args[0])
+	if err != nil {
+		return nil, locate(args[0].GetLoc(), err)
+	}
+	level, err := profilingInt32("level", value)
+	if err != nil {
+		return nil, locate(args[0].GetLoc(), err)
+	}
+	if level < 0 || level > 2 {
+		return nil, errorAt(args[0], "setProfilingLevel() level %d is out of range [0..2]", level)
+	}
+	op.ProfilingLevel = &level
+
+	// Second argument: slowms number or options document (optional)
+	if len(args) >= 2 {
+		switch a := args[1].(type) {
+		case *ast.NumberLiteral:
+			value, err := convertNode(a)
+			if err != nil {
+				return nil, locate(a.GetLoc(), err)
+			}
+			slowms, err := profilingInt32("slowms", value)
+			if err != nil {
+				return nil, locate(a.GetLoc(), err)
+			}
+			op.SlowMS = &slowms
+		case *ast.Document:
+			options, err := convertDocument(a)
+			if err != nil {
+				return nil, fmt.Errorf("invalid setProfilingLevel() options: %w", err)
+			}
+			for _, opt := range options {
+				switch opt.Key {
+				case "slowms":
+					slowms, err := profilingInt32("slowms", opt.Value)
+					if err != nil {
+						return nil, optionError(opt.Key, "%w", err)
+					}
+					op.SlowMS = &slowms
+				case "sampleRate":
+					var rate float64
+					switch v := opt.Value.(type) {
+					case float64:
+						rate = v
+					case int32:
+						rate = float64(v)
+					case int64:
+						rate = float64(v)
+					default:
+						return nil, optionError(opt.Key, "setProfilingLevel() sampleRate must be a number")
+					}
+					if !(rate >= 0 && rate <= 1) {
+						return nil, optionError(opt.Key, "setProfilingLevel() sampleRate must be between 0 and 1")
+					}
+					op.SampleRate = &rate
+				case "filter":
+					if doc, ok := opt.Value.(bson.D); ok {
+						op.ProfileFilter = doc
+					} else {
+						return nil, optionError(opt.Key, "setProfilingLevel() filter must be a document")
+					}
+				default:
+					return nil, &UnsupportedOptionError{
+						Method: "setProfilingLevel()",
+						Option: opt.Key,
+					}
+				}
+			}
+		default:
+			return nil, errorAt(args[1], "setProfilingLevel() options must be a number or document")
+		}
+	}
+
+	if len(args) > 2 {
+		return nil, errorAt(args[2], "setProfilingLevel() takes at most 2 arguments")
 	}
 	return op, nil
 }
@@ -0,0 +1,321 @@
+package translator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// maxDateMillis is the largest distance from the epoch that a JavaScript Date
+// can represent, in milliseconds.
+const maxDateMillis = 8.64e15
+
+// isoDate matches the ISO 8601 dates that mongosh's ISODate() accepts, extended
+// with the year-only, year-month and six-digit year forms of JavaScript's Date.
+var isoDate = regexp.MustCompile(`^([+-]\d{6}|\d{4})(?:-?(\d{2})(?:-?(\d{2}))?)?` +
+	`(?:[T ](\d{2})(?::?(\d{2})(?::?(\d{2})(?:[.,](\d+))?)?)?)?` +
+	`(Z|[+-]\d{2}(?::?\d{2})?)?$`)
+
+// parseDateTime parses a date string the way JavaScript's Date does. ISO 8601
+// dates are read as mongosh's ISODate() reads them: with a T or a space before
+// the time, any number of fractional digits and an offset of Z, ±HH, ±HHMM or
+// ±HH:MM. Other strings are read as V8 reads them, which accepts forms such as
+// "Tue, 02 Jan 2024 10:00:00 GMT", "Jan 2, 2024 10:00 AM" and "2024/01/02".
+// Unlike JavaScript, a date without an offset is read as UTC, not local time.
+func parseDateTime(s string) (bson.DateTime, error) {
+	s = strings.TrimSpace(s)
+	var ms int64
+	var ok bool
+	if m := isoDate.FindStringSubmatch(s); m != nil {
+		ms, ok = isoDateMillis(m)
+	} else {
+		ms, ok = legacyDateMillis(s)
+	}
+	if !ok {
+		return 0, fmt.Errorf("invalid date format: %s", s)
+	}
+	if ms > maxDateMillis || ms < -maxDateMillis {
+		return 0, fmt.Errorf("invalid date: %s is out of range", s)
+	}
+	return bson.DateTime(ms), nil
+}
+
+// isoDateMillis converts the submatches of isoDate to milliseconds since the
+// epoch, reporting false if a field is out of range.
+func isoDateMillis(m []string) (int64, bool) {
+	year, _ := strconv.Atoi(m[1])
+	if m[1] == "-000000" {
+		return 0, false
+	}
+	month, day := 1, 1
+	if m[2] != "" {
+		month, _ = strconv.Atoi(m[2])
+	}
+	if m[3] != "" {
+		day, _ = strconv.Atoi(m[3])
+	}
+	var hour, minute, second, milli int
+	if m[4] != "" {
+		hour, _ = strconv.Atoi(m[4])
+	}
+	if m[5] != "" {
+		minute, _ = strconv.Atoi(m[5])
+	}
+	if m[6] != "" {
+		second, _ = strconv.Atoi(m[6])
+	}
+	if m[7] != "" {
+		// Digits beyond milliseconds are dropped, as JavaScript does.
+		frac := (m[7] + "00")[:3]
+		milli, _ = strconv.Atoi(frac)
+	}
+	offset := 0
+	if tz := m[8]; tz != "" && tz != "Z" {
+		digits := strings.ReplaceAll(tz[1:], ":", "")
+		h, _ := strconv.Atoi(digits[:2])
+		mins := 0
+		if len(digits) == 4 {
+			mins, _ = strconv.Atoi(digits[2:])
+		}
+		if h > 23 || mins > 59 {
+			return 0, false
+		}
+		offset = h*60 + mins
+		if tz[0] == '-' {
+			offset = -offset
+		}
+	}
+	return dateMillis(year, month, day, hour, minute, second, milli, offset)
+}
+
+// dateMillis validates the fields of a date and converts them to milliseconds
+// since the epoch. offset is the time zone offset in minutes east of UTC.
+func dateMillis(year, month, day, hour, minute, second, milli, offset int) (int64, bool) {
+	if month < 1 || month > 12 || day < 1 || day > daysIn(year, month) {
+		return 0, false
+	}
+	if hour > 24 || minute > 59 || second > 59 || hour == 24 && (minute != 0 || second != 0 || milli != 0) {
+		return 0, false
+	}
+	t := time.Date(year, time.Month(month), day, hour, minute, second, milli*int(time.Millisecond), time.UTC)
+	return t.UnixMilli() - int64(offset)*60*1000, true
+}
+
+// daysIn returns the number of days in a month.
+func daysIn(year, month int) int {
+	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// dateToken is a token of a date string read by legacyDateMillis.
+type dateToken struct {
+	num    int    // value of a number
+	digits int    // number of digits of a number; 0 for other tokens
+	word   string // lowercase word
+	symbol byte   // punctuation
+}
+
+// tokenizeDate splits a date string into numbers, words and punctuation,
+// skipping spaces and parenthesized comments such as "(Coordinated Universal Time)".
+func tokenizeDate(s string) ([]dateToken, bool) {
+	var tokens []dateToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(':
+			depth := 0
+			for ; i < len(s); i++ {
+				if s[i] == '(' {
+					depth++
+				} else if s[i] == ')' {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+			}
+			i++
+		case isDigit(c):
+			start := i
+			for i < len(s) && isDigit(s[i]) {
+				i++
+			}
+			if i-start > 9 {
+				return nil, false
+			}
+			n, _ := strconv.Atoi(s[start:i])
+			tokens = append(tokens, dateToken{num: n, digits: i - start})
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := i
+			for i < len(s) && (s[i] >= 'a' && s[i] <= 'z' || s[i] >= 'A' && s[i] <= 'Z') {
+				i++
+			}
+			tokens = append(tokens, dateToken{word: strings.ToLower(s[start:i])})
+		default:
+			tokens = append(tokens, dateToken{symbol: c})
+			i++
+		}
+	}
+	return tokens, true
+}
+
+var (
+	monthNames   = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// nameIndex returns the index of the name that word abbreviates or spells out,
+// or -1.
+func nameIndex(names []string, word string) int {
+	if len(word) < 3 {
+		return -1
+	}
+	for i, name := range names {
+		if strings.HasPrefix(word, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// legacyDateMillis parses the date forms that V8 accepts besides ISO 8601: a
+// day, month and year, with the month as a name or a number, followed by an
+// optional time, AM or PM and time zone. Numeric dates are month/day/year
+// unless they start with the year. Week days and comments are ignored.
+func legacyDateMillis(s string) (int64, bool) {
+	tokens, ok := tokenizeDate(s)
+	if !ok {
+		return 0, false
+	}
+	isSymbol := func(i int, c byte) bool {
+		return i < len(tokens) && tokens[i].digits == 0 && tokens[i].word == "" && tokens[i].symbol == c
+	}
+	isNumber := func(i int) bool {
+		return i < len(tokens) && tokens[i].digits > 0
+	}
+
+	var nums []dateToken
+	month := -1
+	hour, minute, second, milli := -1, 0, 0, 0
+	ampm := ""
+	hasZone := false
+	offset := 0
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.digits > 0 && isSymbol(i+1, ':') && hour < 0:
+			// A time: h:mm, h:mm:ss or h:mm:ss.fff.
+			if !isNumber(i + 2) {
+				return 0, false
+			}
+			hour, minute = t.num, tokens[i+2].num
+			i += 2
+			if isSymbol(i+1, ':') && isNumber(i+2) {
+				second = tokens[i+2].num
+				i += 2
+				if isSymbol(i+1, '.') && isNumber(i+2) {
+					frac := tokens[i+2]
+					milli = frac.num
+					for d := frac.digits; d < 3; d++ {
+						milli *= 10
+					}
+					for d := frac.digits; d > 3; d-- {
+						milli /= 10
+					}
+					i += 2
+				}
+			}
+		case (t.symbol == '+' || t.symbol == '-') && t.word == "" && (hour >= 0 || hasZone) && isNumber(i+1):
+			// A time zone offset: ±h, ±hh, ±hhmm or ±hh:mm.
+			n := tokens[i+1]
+			i++
+			h, m := n.num, 0
+			switch {
+			case isSymbol(i+1, ':') && isNumber(i+2):
+				m = tokens[i+2].num
+				i += 2
+			case n.digits > 2:
+				h, m = n.num/100, n.num%100
+			}
+			if h > 23 || m > 59 {
+				return 0, false
+			}
+			offset = h*60 + m
+			if t.symbol == '-' {
+				offset = -offset
+			}
+			hasZone = true
+		case t.digits > 0:
+			if len(nums) == 3 {
+				return 0, false
+			}
+			nums = append(nums, t)
+		case t.word != "":
+			switch {
+			case t.word == "am" || t.word == "pm":
+				ampm = t.word
+			case t.word == "utc" || t.word == "ut" || t.word == "gmt" || t.word == "z":
+				hasZone = true
+			case t.word == "t" && hour < 0:
+			case nameIndex(monthNames, t.word) >= 0:
+				if month >= 0 {
+					return 0, false
+				}
+				month = nameIndex(monthNames, t.word) + 1
+			case nameIndex(weekdayNames, t.word) >= 0:
+			default:
+				return 0, false
+			}
+		case t.symbol == ',' || t.symbol == '/' || t.symbol == '-' || t.symbol == '.':
+		default:
+			return 0, false
+		}
+	}
+
+	var year, day dateToken
+	switch {
+	case month > 0 && len(nums) == 2:
+		day, year = nums[0], nums[1]
+		if day.digits > 2 || day.num > 31 {
+			year, day = nums[0], nums[1]
+		}
+	case month < 0 && len(nums) == 3:
+		if nums[0].digits > 2 || nums[0].num > 31 {
+			year, day = nums[0], nums[2]
+			month = nums[1].num
+		} else {
+			month, day, year = nums[0].num, nums[1], nums[2]
+		}
+	default:
+		return 0, false
+	}
+	y := year.num
+	if year.digits <= 2 {
+		if y < 50 {
+			y += 2000
+		} else {
+			y += 1900
+		}
+	}
+
+	switch {
+	case hour < 0:
+		if ampm != "" {
+			return 0, false
+		}
+		hour = 0
+	case ampm != "":
+		if hour < 1 || hour > 12 {
+			return 0, false
+		}
+		hour %= 12
+		if ampm == "pm" {
+			hour += 12
+		}
+	}
+	return dateMillis(y, month, day.num, hour, minute, second, milli, offset)
+}
@@ -1,6 +1,12 @@
 package translator
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bytebase/omni/mongo/ast"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
 
 // ParseError represents a syntax error during parsing.
 type ParseError struct {
@@ -47,3 +53,167 @@ type UnsupportedOptionError struct {
 func (e *UnsupportedOptionError) Error() string {
 	return fmt.Sprintf("unsupported option '%s' in %s", e.Option, e.Method)
 }
+
+// SemanticError represents a statement that parses but cannot be translated,
+// such as a method argument of the wrong type. Start and End are the byte
+// offsets of the offending source text: an argument, an option value or a
+// nested value, or the whole method call if no single node is at fault.
+type SemanticError struct {
+	Message string
+	Method  string // method the error was found in, such as "find()"; empty outside a method call
+	Arg     int    // index of the method argument that contains the error, or -1
+	Start   int
+	End     int
+}
+
+func (e *SemanticError) Error() string {
+	return e.Message
+}
+
+// nodeError is an error located at a node of the statement. Errors wrapping it
+// with %w keep its location.
+type nodeError struct {
+	loc ast.Loc
+	err error
+}
+
+func (e *nodeError) Error() string { return e.err.Error() }
+func (e *nodeError) Unwrap() error { return e.err }
+
+// errorAt returns an error located at node.
+func errorAt(node ast.Node, format string, args ...any) error {
+	return &nodeError{loc: node.GetLoc(), err: fmt.Errorf(format, args...)}
+}
+
+// elementError returns an error located at element i of node if node is an
+// array literal, or at node otherwise.
+func elementError(node ast.Node, i int, format string, args ...any) error {
+	if arr, ok := node.(*ast.Array); ok && i < len(arr.Elements) {
+		node = arr.Elements[i]
+	}
+	return errorAt(node, format, args...)
+}
+
+// locate locates err at loc unless it already has a location.
+func locate(loc ast.Loc, err error) error {
+	var ne *nodeError
+	if err == nil || errors.As(err, &ne) {
+		return err
+	}
+	return &nodeError{loc: loc, err: err}
+}
+
+// optionKeyError is an error in the value of an option. Options are validated
+// after their document is converted, so the error only records the option's
+// key, and locateError finds its value in the method's arguments.
+type optionKeyError struct {
+	key string
+	err error
+}
+
+func (e *optionKeyError) Error() string { return e.err.Error() }
+func (e *optionKeyError) Unwrap() error { return e.err }
+
+// optionError returns an error in the value of the option key.
+func optionError(key string, format string, args ...any) error {
+	return &optionKeyError{key: key, err: fmt.Errorf(format, args...)}
+}
+
+// optionLoc returns the location of the value of an option key in the last
+// argument that has it.
+func optionLoc(args []ast.Node, key string) (ast.Loc, bool) {
+	if node := optionNode(args, key); node != nil {
+		return node.GetLoc(), true
+	}
+	return ast.Loc{}, false
+}
+
+// optionNode returns the value node of an option key in the last argument that
+// has it, or the placeholder that supplied the argument, or nil if no argument
+// has the key.
+func optionNode(args []ast.Node, key string) ast.Node {
+	for i := len(args) - 1; i >= 0; i-- {
+		switch n := args[i].(type) {
+		case *ast.Document:
+			for _, kv := range n.Pairs {
+				if kv.Key == key {
+					return kv.Value
+				}
+			}
+		case *param:
+			if doc, ok := n.value.(bson.D); ok {
+				for _, e := range doc {
+					if e.Key == key {
+						return n
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// locateError converts an error found while translating a statement to a
+// *SemanticError located at the offending node. Errors of the other types
+// in this file are returned unchanged.
+func locateError(node ast.Node, err error) error {
+	switch err.(type) {
+	case nil, *ParseError, *UnsupportedOperationError, *PlannedOperationError, *UnsupportedOptionError, *SemanticError:
+		return err
+	}
+
+	// Find the method call the error belongs to.
+	call := node.GetLoc()
+	var method string
+	var args []ast.Node
+	switch n := node.(type) {
+	case *ast.CollectionStatement:
+		method, args = n.Method, n.Args
+		var ne *nodeError
+		if errors.As(err, &ne) {
+			for _, cm := range n.CursorMethods {
+				if within(ne.loc, cm.Loc) {
+					method, args, call = cm.Method, cm.Args, cm.Loc
+				}
+			}
+		}
+	case *ast.DatabaseStatement:
+		method, args = n.Method, n.Args
+	case *ast.RsStatement:
+		method, args = "rs."+n.MethodName, n.Args
+	case *ast.ShStatement:
+		method, args = "sh."+n.MethodName, n.Args
+	}
+
+	loc, located := call, false
+	var ne *nodeError
+	var oe *optionKeyError
+	switch {
+	case errors.As(err, &ne):
+		loc, located = ne.loc, true
+	case errors.As(err, &oe):
+		loc, located = optionLoc(args, oe.key)
+		if !located {
+			loc = call
+		}
+	}
+
+	e := &SemanticError{Message: err.Error(), Arg: -1, Start: loc.Start, End: loc.End}
+	if method != "" && within(loc, node.GetLoc()) {
+		e.Method = method + "()"
+	}
+	if located {
+		for i, arg := range args {
+			if within(loc, arg.GetLoc()) {
+				e.Arg = i
+				break
+			}
+		}
+	}
+	return e
+}
+
+// within reports whether loc lies inside outer.
+func within(loc, outer ast.Loc) bool {
+	return loc.Start >= outer.Start && loc.End <= outer.End
+}
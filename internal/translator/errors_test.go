@@ -0,0 +1,94 @@
+package translator_test
+
+import (
+	"testing"
+
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemanticErrors(t *testing.T) {
+	tests := []struct {
+		statement string
+		message   string
+		method    string
+		arg       int
+		text      string // source text the error points at
+	}{
+		{`db.c.find({ a: 1 }, 5)`, "find() projection must be a document", "find()", 1, `5`},
+		{`db.c.find({}, {}, {}, {})`, "find() takes at most 3 arguments", "find()", 3, `{}`},
+		{`db.c.find({}, {}, { maxTimeMS: "x" })`, "find() maxTimeMS must be a number", "find()", 2, `"x"`},
+		{`db.createCollection("logs", { capped: true, size: "big" })`, "createCollection() size must be a number", "createCollection()", 1, `"big"`},
+		{`db.c.find({ a: { b: ObjectId("xyz") } })`, `invalid ObjectId: "xyz" is not a valid 24-character hex string`, "find()", 0, `ObjectId("xyz")`},
+		{`db.c.insertMany([{ a: 1 }, 2])`, "insertMany() element 1 must be a document", "insertMany()", 0, `2`},
+		{`db.c.updateOne({}, 5)`, "updateOne() update must be a document or array", "updateOne()", 1, `5`},
+		{`db.c.find().sort({ a: 1 }).limit(1.5)`, "limit() requires an integer argument", "limit()", 0, `1.5`},
+		{`db.c.find().limit()`, "limit() requires a number argument", "limit()", -1, `limit()`},
+		{`db.c.updateOne({})`, "updateOne() requires filter and update arguments", "updateOne()", -1, `db.c.updateOne({})`},
+		{`db.setProfilingLevel(1, { sampleRate: "all" })`, "setProfilingLevel() sampleRate must be a number", "setProfilingLevel()", 1, `"all"`},
+		{`db.createUser({ user: 1, pwd: "x", roles: [] })`, "createUser() user must be a string", "createUser()", 0, `1`},
+		{`rs.status(1)`, "rs.status() takes no arguments", "rs.status()", 0, `1`},
+		{`db.c.find({ a: 1 + b })`, "cannot evaluate 1 + b: b is not defined", "find()", 0, `1 + b`},
+		{`const x = y; db.c.find({ a: x })`, "cannot evaluate y: y is not defined", "", -1, `y`},
+		{`db.c.find({ a: 99999999999999999999n })`, "invalid number: 99999999999999999999n does not fit in a 64-bit integer", "find()", 0, `99999999999999999999n`},
+		{`db.c.find({ a: [1, -99999999999999999999n] })`, "does not fit in a 64-bit integer", "find()", 0, `-99999999999999999999n`},
+		{`const x = -9223372036854775809n; db.c.find({ a: x })`, "does not fit in a 64-bit integer", "", -1, `-9223372036854775809n`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.statement, func(t *testing.T) {
+			_, err := translator.Parse(tc.statement)
+			var semanticErr *translator.SemanticError
+			require.ErrorAs(t, err, &semanticErr)
+			require.Contains(t, semanticErr.Message, tc.message)
+			require.Equal(t, tc.method, semanticErr.Method)
+			require.Equal(t, tc.arg, semanticErr.Arg)
+			require.Equal(t, tc.text, tc.statement[semanticErr.Start:semanticErr.End])
+		})
+	}
+
+	// Errors found while binding parameters point at the placeholder.
+	stmt, err := translator.Prepare(`db.c.find({ a: 1 }, { b: :fields })`)
+	require.NoError(t, err)
+	_, err = stmt.Bind(nil)
+	var semanticErr *translator.SemanticError
+	require.ErrorAs(t, err, &semanticErr)
+	require.Equal(t, "no value bound to parameter :fields", semanticErr.Message)
+	require.Equal(t, 1, semanticErr.Arg)
+	require.Equal(t, 25, semanticErr.Start)
+
+	// Malformed Extended JSON wrappers point at the wrapper.
+	statement := `db.c.find({ a: { $oid: 1 } })`
+	stmt, err = translator.PrepareWithOptions(statement, translator.Options{ExtendedJSON: true})
+	require.ErrorAs(t, err, &semanticErr)
+	require.Equal(t, "invalid $oid: must be a string", semanticErr.Message)
+	require.Equal(t, 0, semanticErr.Arg)
+	require.Equal(t, `{ $oid: 1 }`, statement[semanticErr.Start:semanticErr.End])
+
+	// Unsupported operations and options keep their own error types.
+	_, err = translator.Parse(`db.c.find({}, {}, { batchSize: 10 })`)
+	var optErr *translator.UnsupportedOptionError
+	require.ErrorAs(t, err, &optErr)
+}
+
+func TestParseErrorTokens(t *testing.T) {
+	tests := []struct {
+		statement string
+		found     string
+		expected  string
+	}{
+		{`db.c.find({ a 1 })`, "1", ":"},
+		{`db.c.find({ a: 1 }`, "", ","},
+		{`db.c.find({ a: })`, "}", ""},
+		{`db.changeUserPassword("alice" "secret")`, "***", ","},
+	}
+	for _, tc := range tests {
+		t.Run(tc.statement, func(t *testing.T) {
+			_, err := translator.Parse(tc.statement)
+			var parseErr *translator.ParseError
+			require.ErrorAs(t, err, &parseErr)
+			require.Equal(t, tc.found, parseErr.Found)
+			require.Equal(t, tc.expected, parseErr.Expected)
+			require.NotContains(t, parseErr.Error(), "secret")
+		})
+	}
+}
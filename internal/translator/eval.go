@@ -0,0 +1,681 @@
+package translator
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytebase/omni/mongo/ast"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// evaluator evaluates the expressions of a script. It only knows the values,
+// functions and methods listed here, so an expression cannot reach anything
+// outside the statement.
+type evaluator struct {
+	source       string
+	vars         map[string]any
+	bind         func(id *ast.Identifier) (ast.Node, error) // binds a placeholder
+	extendedJSON bool                                       // convert Extended JSON wrapper objects
+}
+
+// evaluate evaluates an expression to a BSON value.
+func (ev *evaluator) evaluate(e jsExpr) (any, error) {
+	v, err := ev.value(e)
+	if err != nil {
+		return nil, err
+	}
+	return bsonValue(v), nil
+}
+
+// value evaluates an expression to a JavaScript value, such as a jsBigInt,
+// locating any error at the expression.
+func (ev *evaluator) value(e jsExpr) (any, error) {
+	v, err := ev.eval(e)
+	if err != nil {
+		s := e.span()
+		loc := ast.Loc{Start: s.start, End: s.end}
+		return nil, &nodeError{loc: loc, err: fmt.Errorf("cannot evaluate %s: %w", ev.source[s.start:s.end], err)}
+	}
+	return v, nil
+}
+
+func (ev *evaluator) eval(e jsExpr) (any, error) {
+	switch n := e.(type) {
+	case *jsLiteral:
+		return n.value, nil
+	case *jsInvalid:
+		return nil, n.err
+	case *jsFunction:
+		return bson.JavaScript(n.code), nil
+	case *jsParen:
+		return ev.eval(n.inner)
+	case *jsIdent:
+		return ev.evalIdent(n)
+	case *jsObject:
+		doc := make(bson.D, len(n.keys))
+		for i, key := range n.keys {
+			v, err := ev.eval(n.values[i])
+			if err != nil {
+				return nil, err
+			}
+			doc[i] = bson.E{Key: key, Value: bsonValue(v)}
+		}
+		if ev.extendedJSON {
+			if v, ok, err := extendedJSONValue(doc); ok {
+				return v, err
+			}
+		}
+		return doc, nil
+	case *jsArray:
+		arr := make(bson.A, len(n.elements))
+		for i, elem := range n.elements {
+			v, err := ev.eval(elem)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = bsonValue(v)
+		}
+		return arr, nil
+	case *jsUnary:
+		v, err := ev.eval(n.operand)
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := v.(jsBigInt); ok {
+			if n.op == "+" {
+				return nil, errBigIntToNumber
+			}
+			return bigIntBinary("-", 0, i)
+		}
+		if n.op == "-" {
+			return -toNumber(v), nil
+		}
+		return toNumber(v), nil
+	case *jsBinary:
+		return ev.evalBinary(n)
+	case *jsMember:
+		return ev.evalMember(n)
+	case *jsCall:
+		return ev.evalCall(n)
+	default:
+		return nil, fmt.Errorf("unsupported expression")
+	}
+}
+
+func (ev *evaluator) evalIdent(n *jsIdent) (any, error) {
+	if v, ok := ev.vars[n.name]; ok {
+		return v, nil
+	}
+	switch n.name {
+	case "Infinity":
+		return jsNumber(math.Inf(1)), nil
+	case "NaN":
+		return jsNumber(math.NaN()), nil
+	}
+	if strings.HasPrefix(n.name, "$") {
+		node, err := ev.bind(&ast.Identifier{Name: n.name, Loc: ast.Loc{Start: n.start, End: n.end}})
+		if err != nil {
+			return nil, err
+		}
+		if p, ok := node.(*param); ok {
+			return p.value, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not defined", n.name)
+}
+
+func (ev *evaluator) evalBinary(n *jsBinary) (any, error) {
+	left, err := ev.eval(n.left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := ev.eval(n.right)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "+" {
+		_, ls := left.(string)
+		_, rs := right.(string)
+		if ls || rs {
+			return toString(left) + toString(right), nil
+		}
+	}
+	lb, lok := left.(jsBigInt)
+	rb, rok := right.(jsBigInt)
+	if lok && rok {
+		return bigIntBinary(n.op, lb, rb)
+	}
+	if lok || rok {
+		return nil, fmt.Errorf("TypeError: Cannot mix BigInt and other types, use explicit conversions")
+	}
+	a, b := float64(toNumber(left)), float64(toNumber(right))
+	switch n.op {
+	case "+":
+		return jsNumber(a + b), nil
+	case "-":
+		return jsNumber(a - b), nil
+	case "*":
+		return jsNumber(a * b), nil
+	case "/":
+		return jsNumber(a / b), nil
+	case "%":
+		return jsNumber(math.Mod(a, b)), nil
+	case "**":
+		return jsNumber(math.Pow(a, b)), nil
+	}
+	return nil, fmt.Errorf("unsupported operator %s", n.op)
+}
+
+// errBigIntToNumber is the error JavaScript raises when a BigInt is converted
+// to a number implicitly, such as by unary plus or Math.abs().
+var errBigIntToNumber = errors.New("TypeError: Cannot convert a BigInt value to a number")
+
+// bigIntBinary applies a binary operator to two BigInts. BigInts are kept as
+// int64, so a result that does not fit is an error rather than a double.
+func bigIntBinary(op string, a, b jsBigInt) (any, error) {
+	x, y := big.NewInt(int64(a)), big.NewInt(int64(b))
+	z := new(big.Int)
+	switch op {
+	case "+":
+		z.Add(x, y)
+	case "-":
+		z.Sub(x, y)
+	case "*":
+		z.Mul(x, y)
+	case "/", "%":
+		if y.Sign() == 0 {
+			return nil, fmt.Errorf("RangeError: Division by zero")
+		}
+		if op == "/" {
+			z.Quo(x, y)
+		} else {
+			z.Rem(x, y)
+		}
+	case "**":
+		if y.Sign() < 0 {
+			return nil, fmt.Errorf("RangeError: Exponent must be non-negative")
+		}
+		// Any base other than -1, 0 and 1 overflows int64 beyond exponent 63.
+		if x.CmpAbs(big.NewInt(1)) > 0 && y.Cmp(big.NewInt(64)) > 0 {
+			return nil, errBigIntRange
+		}
+		z.Exp(x, y, nil)
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", op)
+	}
+	if !z.IsInt64() {
+		return nil, errBigIntRange
+	}
+	return jsBigInt(z.Int64()), nil
+}
+
+// errBigIntRange reports a BigInt result that cannot be stored as a BSON long.
+var errBigIntRange = errors.New("BigInt result does not fit in a 64-bit integer")
+
+// checkNumbers returns errBigIntToNumber if any of args is a BigInt, which
+// functions taking numbers cannot convert implicitly.
+func checkNumbers(args []any) error {
+	for _, arg := range args {
+		if _, ok := arg.(jsBigInt); ok {
+			return errBigIntToNumber
+		}
+	}
+	return nil
+}
+
+func (ev *evaluator) evalMember(n *jsMember) (any, error) {
+	if n.index != nil {
+		return nil, fmt.Errorf("computed property access is not supported")
+	}
+	if obj, ok := n.object.(*jsIdent); ok && obj.name == "Math" {
+		if c, ok := mathConstants[n.name]; ok {
+			return jsNumber(c), nil
+		}
+		return nil, fmt.Errorf("Math.%s is not supported", n.name)
+	}
+	obj, err := ev.eval(n.object)
+	if err != nil {
+		return nil, err
+	}
+	switch v := obj.(type) {
+	case string:
+		if n.name == "length" {
+			return jsNumber(len([]rune(v))), nil
+		}
+	case bson.ObjectID:
+		if n.name == "str" {
+			return v.Hex(), nil
+		}
+	}
+	return nil, fmt.Errorf("property %s is not supported", n.name)
+}
+
+func (ev *evaluator) evalCall(n *jsCall) (any, error) {
+	args := make([]any, len(n.args))
+	for i, arg := range n.args {
+		v, err := ev.eval(arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+
+	switch callee := n.callee.(type) {
+	case *jsIdent:
+		return ev.callFunction(callee.name, args, n.isNew)
+	case *jsMember:
+		if callee.index != nil || n.isNew {
+			break
+		}
+		if obj, ok := callee.object.(*jsIdent); ok {
+			switch obj.name {
+			case "Math":
+				return callMath(callee.name, args)
+			case "Date":
+				return callDateStatic(callee.name, args)
+			case "ObjectId":
+				return callObjectIdStatic(callee.name, args)
+			}
+		}
+		obj, err := ev.eval(callee.object)
+		if err != nil {
+			return nil, err
+		}
+		return callMethod(obj, callee.name, args)
+	}
+	return nil, fmt.Errorf("unsupported call")
+}
+
+// callFunction calls a global function, such as Date() or ObjectId().
+func (ev *evaluator) callFunction(name string, args []any, isNew bool) (any, error) {
+	switch name {
+	case "Date", "ISODate":
+		return newDate(args)
+	case "ObjectId", "UUID", "NumberLong", "Long", "NumberInt", "Int32", "Double",
+		"NumberDecimal", "Decimal128", "Timestamp":
+		if isNew && name != "ObjectId" {
+			break
+		}
+		nodes := make([]ast.Node, len(args))
+		for i, arg := range args {
+			node, err := literalNode(arg)
+			if err != nil {
+				return nil, fmt.Errorf("%s() %w", name, err)
+			}
+			nodes[i] = node
+		}
+		return convertHelper(&ast.HelperCall{Name: name, Args: nodes})
+	case "String":
+		if len(args) == 0 {
+			return "", nil
+		}
+		return toString(args[0]), nil
+	case "Number":
+		if len(args) == 0 {
+			return jsNumber(0), nil
+		}
+		return toNumber(args[0]), nil
+	}
+	if isNew {
+		return nil, fmt.Errorf("new %s() is not supported", name)
+	}
+	return nil, fmt.Errorf("%s() is not supported", name)
+}
+
+// newDate implements Date(), new Date() and ISODate(). Dates built from
+// components use UTC.
+func newDate(args []any) (any, error) {
+	if len(args) == 0 {
+		return bson.NewDateTimeFromTime(time.Now()), nil
+	}
+	if err := checkNumbers(args); err != nil {
+		return nil, err
+	}
+	if len(args) == 1 {
+		switch v := args[0].(type) {
+		case string:
+			return parseDateTime(v)
+		case bson.DateTime:
+			return v, nil
+		}
+		return timeClip(float64(toNumber(args[0])))
+	}
+	parts := [7]float64{0, 0, 1, 0, 0, 0, 0}
+	for i := 0; i < len(args) && i < len(parts); i++ {
+		f := float64(toNumber(args[i]))
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("invalid date")
+		}
+		parts[i] = math.Trunc(f)
+	}
+	// As in JavaScript, the month may overflow into the year and the other
+	// fields are added up as milliseconds.
+	year, month := parts[0]+math.Floor(parts[1]/12), math.Mod(parts[1], 12)
+	if month < 0 {
+		month += 12
+	}
+	if math.Abs(year) > maxDateYears {
+		return nil, fmt.Errorf("invalid date")
+	}
+	ms := float64(time.Date(int(year), time.Month(month+1), 1, 0, 0, 0, 0, time.UTC).UnixMilli()) +
+		(parts[2]-1)*864e5 + parts[3]*36e5 + parts[4]*6e4 + parts[5]*1e3 + parts[6]
+	return timeClip(ms)
+}
+
+// maxDateYears bounds the year of a date built from components, well beyond
+// the years a date can hold, so that it fits in an int.
+const maxDateYears = 400000
+
+// timeClip converts milliseconds since the epoch to a date as JavaScript's
+// TimeClip does: it truncates toward zero and rejects times outside the range
+// of a JavaScript date.
+func timeClip(ms float64) (any, error) {
+	if math.IsNaN(ms) || math.Abs(ms) > maxDateMillis {
+		return nil, fmt.Errorf("invalid date")
+	}
+	return bson.DateTime(int64(math.Trunc(ms))), nil
+}
+
+func callDateStatic(name string, args []any) (any, error) {
+	switch name {
+	case "now":
+		return jsNumber(time.Now().UnixMilli()), nil
+	case "UTC":
+		d, err := newDate(append(args, make([]any, max(0, 2-len(args)))...))
+		if err != nil {
+			return nil, err
+		}
+		return jsNumber(d.(bson.DateTime)), nil
+	case "parse":
+		if len(args) == 1 {
+			if s, ok := args[0].(string); ok {
+				d, err := parseDateTime(s)
+				if err != nil {
+					return jsNumber(math.NaN()), nil
+				}
+				return jsNumber(d), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Date.%s() is not supported", name)
+}
+
+func callObjectIdStatic(name string, args []any) (any, error) {
+	switch name {
+	case "createFromTime":
+		if len(args) == 1 {
+			secs := float64(toNumber(args[0]))
+			if secs < 0 || secs > math.MaxUint32 || math.IsNaN(secs) {
+				return nil, fmt.Errorf("ObjectId.createFromTime() argument must be a number of seconds")
+			}
+			var oid bson.ObjectID
+			binary.BigEndian.PutUint32(oid[:4], uint32(secs))
+			return oid, nil
+		}
+	case "createFromHexString":
+		if len(args) == 1 {
+			if s, ok := args[0].(string); ok {
+				return bson.ObjectIDFromHex(s)
+			}
+		}
+	}
+	return nil, fmt.Errorf("ObjectId.%s() is not supported", name)
+}
+
+// callMethod calls a method of a value.
+func callMethod(obj any, name string, args []any) (any, error) {
+	switch v := obj.(type) {
+	case bson.ObjectID:
+		switch name {
+		case "getTimestamp":
+			return bson.NewDateTimeFromTime(v.Timestamp()), nil
+		case "toHexString", "toString", "valueOf":
+			return v.Hex(), nil
+		}
+	case bson.DateTime:
+		switch name {
+		case "getTime", "valueOf":
+			return jsNumber(v), nil
+		case "toISOString", "toJSON":
+			return v.Time().UTC().Format("2006-01-02T15:04:05.000Z"), nil
+		}
+	case string:
+		switch name {
+		case "toUpperCase":
+			return strings.ToUpper(v), nil
+		case "toLowerCase":
+			return strings.ToLower(v), nil
+		case "trim":
+			return strings.TrimSpace(v), nil
+		case "toString", "valueOf":
+			return v, nil
+		}
+	case jsNumber:
+		switch name {
+		case "toString":
+			return toString(v), nil
+		case "valueOf":
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%s() is not supported on %s", name, typeName(obj))
+}
+
+var mathConstants = map[string]float64{
+	"PI":      math.Pi,
+	"E":       math.E,
+	"LN2":     math.Ln2,
+	"LN10":    math.Ln10,
+	"LOG2E":   math.Log2E,
+	"LOG10E":  math.Log10E,
+	"SQRT2":   math.Sqrt2,
+	"SQRT1_2": math.Sqrt2 / 2,
+}
+
+var mathFunctions = map[string]func(float64) float64{
+	"abs":   math.Abs,
+	"ceil":  math.Ceil,
+	"floor": math.Floor,
+	"round": func(x float64) float64 { return math.Floor(x + 0.5) },
+	"trunc": math.Trunc,
+	"sign": func(x float64) float64 {
+		switch {
+		case x > 0:
+			return 1
+		case x < 0:
+			return -1
+		}
+		return x
+	},
+	"sqrt":  math.Sqrt,
+	"cbrt":  math.Cbrt,
+	"exp":   math.Exp,
+	"log":   math.Log,
+	"log2":  math.Log2,
+	"log10": math.Log10,
+	"sin":   math.Sin,
+	"cos":   math.Cos,
+	"tan":   math.Tan,
+}
+
+func callMath(name string, args []any) (any, error) {
+	if err := checkNumbers(args); err != nil {
+		return nil, err
+	}
+	nums := make([]float64, len(args))
+	for i, arg := range args {
+		nums[i] = float64(toNumber(arg))
+	}
+	if fn, ok := mathFunctions[name]; ok {
+		if len(nums) == 0 {
+			return jsNumber(math.NaN()), nil
+		}
+		return jsNumber(fn(nums[0])), nil
+	}
+	switch name {
+	case "pow":
+		if len(nums) < 2 {
+			return jsNumber(math.NaN()), nil
+		}
+		return jsNumber(math.Pow(nums[0], nums[1])), nil
+	case "min", "max":
+		result := math.Inf(1)
+		pick := math.Min
+		if name == "max" {
+			result, pick = math.Inf(-1), math.Max
+		}
+		for _, n := range nums {
+			result = pick(result, n)
+		}
+		return jsNumber(result), nil
+	case "random":
+		return jsNumber(rand.Float64()), nil
+	}
+	return nil, fmt.Errorf("Math.%s() is not supported", name)
+}
+
+// toNumber converts a value to a number as JavaScript does. Dates convert to
+// milliseconds since the epoch.
+func toNumber(v any) jsNumber {
+	switch n := v.(type) {
+	case jsNumber:
+		return n
+	case int32:
+		return jsNumber(n)
+	case int64:
+		return jsNumber(n)
+	case jsBigInt:
+		return jsNumber(n)
+	case float64:
+		return jsNumber(n)
+	case bson.DateTime:
+		return jsNumber(n)
+	case bool:
+		if n {
+			return 1
+		}
+		return 0
+	case nil:
+		return 0
+	case string:
+		s := strings.TrimSpace(n)
+		if s == "" {
+			return 0
+		}
+		if f, err := parseJSNumber(s); err == nil {
+			return f
+		}
+	}
+	return jsNumber(math.NaN())
+}
+
+// toString converts a value to a string as JavaScript does.
+func toString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case jsNumber:
+		f := float64(s)
+		switch {
+		case math.IsNaN(f):
+			return "NaN"
+		case math.IsInf(f, 1):
+			return "Infinity"
+		case math.IsInf(f, -1):
+			return "-Infinity"
+		}
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	case bson.ObjectID:
+		return s.Hex()
+	case bson.DateTime:
+		return s.Time().UTC().Format("2006-01-02T15:04:05.000Z")
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(v)
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case jsNumber:
+		return "a number"
+	case jsBigInt:
+		return "a BigInt"
+	case string:
+		return "a string"
+	case bson.DateTime:
+		return "a date"
+	case bson.ObjectID:
+		return "an ObjectId"
+	case bson.JavaScript:
+		return "a function"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// bsonValue converts an evaluated value to a BSON value. Numbers become the
+// smallest of int32, int64 and float64 that holds them exactly.
+func bsonValue(v any) any {
+	if i, ok := v.(jsBigInt); ok {
+		return int64(i)
+	}
+	n, ok := v.(jsNumber)
+	if !ok {
+		return v
+	}
+	f := float64(n)
+	if f != math.Trunc(f) || math.IsInf(f, 0) || (f == 0 && math.Signbit(f)) {
+		return f
+	}
+	if f >= math.MinInt32 && f <= math.MaxInt32 {
+		return int32(f)
+	}
+	if math.Abs(f) <= 1<<53 {
+		return int64(f)
+	}
+	return f
+}
+
+// literalNode converts an evaluated value to a literal node, so that helpers
+// called in an expression are converted exactly as when they are written out.
+func literalNode(v any) (ast.Node, error) {
+	switch x := v.(type) {
+	case string:
+		return &ast.StringLiteral{Value: x}, nil
+	case jsBigInt:
+		return &ast.NumberLiteral{Value: strconv.FormatInt(int64(x), 10)}, nil
+	case jsNumber, int32, int64, float64:
+		b := bsonValue(toNumber(x))
+		if f, ok := b.(float64); ok {
+			return &ast.NumberLiteral{Value: strconv.FormatFloat(f, 'g', -1, 64)}, nil
+		}
+		return &ast.NumberLiteral{Value: fmt.Sprint(b)}, nil
+	case bool:
+		return &ast.BoolLiteral{Value: x}, nil
+	case nil:
+		return &ast.NullLiteral{}, nil
+	case bson.D:
+		doc := &ast.Document{}
+		for _, e := range x {
+			value, err := literalNode(e.Value)
+			if err != nil {
+				return nil, err
+			}
+			doc.Pairs = append(doc.Pairs, ast.KeyValue{Key: e.Key, Value: value})
+		}
+		return doc, nil
+	case bson.ObjectID:
+		return &ast.StringLiteral{Value: hex.EncodeToString(x[:])}, nil
+	}
+	return nil, fmt.Errorf("argument cannot be %s", typeName(v))
+}
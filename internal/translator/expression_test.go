@@ -0,0 +1,153 @@
+package translator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestExpressions(t *testing.T) {
+	oid, err := bson.ObjectIDFromHex("507f1f77bcf86cd799439011")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		statement string
+		want      any
+	}{
+		{"integer arithmetic", `db.c.find({ v: 1 + 2 * 3 })`, int32(7)},
+		{"parentheses", `db.c.find({ v: (1 + 2) * 3 })`, int32(9)},
+		{"division", `db.c.find({ v: 7 / 2 })`, 3.5},
+		{"remainder", `db.c.find({ v: 7 % 4 })`, int32(3)},
+		{"exponent", `db.c.find({ v: 2 ** 3 ** 2 })`, int32(512)},
+		{"large integer", `db.c.find({ v: 24 * 60 * 60 * 1000 * 100 })`, int64(8640000000)},
+		{"unary minus", `db.c.find({ v: -(2 + 3) })`, int32(-5)},
+		{"string concatenation", `db.c.find({ v: "user-" + 42 })`, "user-42"},
+		{"Math", `db.c.find({ v: Math.max(1, Math.floor(7.8), 3) })`, int32(7)},
+		{"Math constant", `db.c.find({ v: Math.round(Math.PI * 100) })`, int32(314)},
+		{"new Date from components", `db.c.find({ v: new Date(2024, 0, 15) })`, bson.DateTime(1705276800000)},
+		{"date arithmetic", `db.c.find({ v: new Date(ISODate("2024-01-02T00:00:00Z") - 24 * 60 * 60 * 1000) })`, bson.DateTime(1704067200000)},
+		{"date method", `db.c.find({ v: ISODate("2024-01-01T00:00:00Z").getTime() })`, int64(1704067200000)},
+		{"ObjectId timestamp", `db.c.find({ v: ObjectId("507f1f77bcf86cd799439011").getTimestamp() })`, bson.NewDateTimeFromTime(oid.Timestamp())},
+		{"ObjectId from time", `db.c.find({ v: ObjectId.createFromTime(1700000000) })`, bson.ObjectID{0x65, 0x53, 0xf1, 0x00}},
+		{"helper with expression argument", `db.c.find({ v: NumberLong(2 * 3) })`, int64(6)},
+		{"const", `const n = 5; db.c.find({ v: n * 2 })`, int32(10)},
+		{"let and var", "let a = 2\nvar b = a + 1\ndb.c.find({ v: a * b })", int32(6)},
+		{"multiple declarations", `const a = 1, b = a + 1; db.c.find({ v: b })`, int32(2)},
+		{"variable document", `const f = { v: [1, 1 + 1] }; db.c.find({ v: f })`, bson.D{{Key: "v", Value: bson.A{int32(1), int32(2)}}}},
+		{"var redeclared", `var a = 1; var a = a + 1; db.c.find({ v: a })`, int32(2)},
+		{"BigInt arithmetic", `db.c.find({ v: 2n ** 62n + (2n ** 62n - 1n) })`, int64(9223372036854775807)},
+		{"BigInt division truncates", `db.c.find({ v: -7n / 2n })`, int64(-3)},
+		{"BigInt variable", `const big = 10n; db.c.find({ v: big * big })`, int64(100)},
+		{"BigInt to Number", `db.c.find({ v: Number(5n) + 1 })`, int32(6)},
+		{"date truncated toward zero", `db.c.find({ v: new Date(-1.5) })`, bson.DateTime(-1)},
+		{"date month overflow", `db.c.find({ v: new Date(2023, 12, 1) })`, bson.DateTime(1704067200000)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			op, err := translator.Parse(tc.statement)
+			require.NoError(t, err)
+			require.Len(t, op.Filter, 1)
+			require.Equal(t, tc.want, op.Filter[0].Value)
+		})
+	}
+}
+
+func TestExpressionDates(t *testing.T) {
+	before := time.Now().Add(-24 * time.Hour).UnixMilli()
+	op, err := translator.Parse(`db.logs.find({ ts: { $gt: new Date(Date.now() - 24*60*60*1000) } })`)
+	require.NoError(t, err)
+	after := time.Now().Add(-24 * time.Hour).UnixMilli()
+
+	gt := op.Filter[0].Value.(bson.D)[0].Value.(bson.DateTime)
+	require.GreaterOrEqual(t, int64(gt), before)
+	require.LessOrEqual(t, int64(gt), after)
+
+	// Expressions are evaluated on every execution of a prepared statement.
+	stmt, err := translator.Prepare(`const cutoff = ISODate(); db.x.deleteMany({ ts: { $lt: cutoff } })`)
+	require.NoError(t, err)
+	first, err := stmt.Bind(nil)
+	require.NoError(t, err)
+	time.Sleep(2 * time.Millisecond)
+	second, err := stmt.Bind(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, first.Filter, second.Filter)
+}
+
+func TestExpressionParams(t *testing.T) {
+	op, err := translator.ParseWithParams(
+		`const day = 24 * 60 * 60 * 1000; db.c.find({ v: :days * day }).limit($1 + 1)`,
+		&translator.Params{Positional: []any{9}, Named: map[string]any{"days": 2}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, bson.D{{Key: "v", Value: int32(172800000)}}, op.Filter)
+	require.Equal(t, int64(10), *op.Limit)
+}
+
+func TestExpressionErrors(t *testing.T) {
+	tests := []struct {
+		statement string
+		err       string
+	}{
+		{`db.c.find({ v: x + 1 })`, "cannot evaluate x + 1: x is not defined"},
+		{`db.c.find({ v: Math.nope(1) })`, "Math.nope() is not supported"},
+		{`db.c.find({ v: new Map() })`, "new Map() is not supported"},
+		{`db.c.find({ v: "a".repeat(3) })`, "repeat() is not supported on a string"},
+		{`db.c.find({ v: ObjectId("bad" + "hex") })`, "invalid ObjectId"},
+		{`const a = b; db.c.find({ v: a })`, "cannot evaluate b: b is not defined"},
+		{`let a = 1; let a = 2; db.c.find({ v: a })`, "SyntaxError: Identifier 'a' has already been declared"},
+		{`var a = 1; const a = 2; db.c.find({ v: a })`, "SyntaxError: Identifier 'a' has already been declared"},
+		{`db.c.find({ v: 1n + 1 })`, "cannot evaluate 1n + 1: TypeError: Cannot mix BigInt and other types"},
+		{`const n = 1n; db.c.find({ v: n * 2 })`, "TypeError: Cannot mix BigInt and other types"},
+		{`db.c.find({ v: +1n })`, "TypeError: Cannot convert a BigInt value to a number"},
+		{`db.c.find({ v: Math.abs(1n) })`, "TypeError: Cannot convert a BigInt value to a number"},
+		{`db.c.find({ v: 10n ** 30n })`, "BigInt result does not fit in a 64-bit integer"},
+		{`db.c.find({ v: 2n ** 63n })`, "BigInt result does not fit in a 64-bit integer"},
+		{`db.c.find({ v: 1n / 0n })`, "RangeError: Division by zero"},
+		{`db.c.find({ v: 2n ** -1n })`, "RangeError: Exponent must be non-negative"},
+		{`db.c.find({ v: new Date(8.64e15 + 1) })`, "invalid date"},
+		{`db.c.find({ v: new Date(-8.64e15 - 1) })`, "invalid date"},
+		{`db.c.find({ v: new Date(275761, 0) })`, "invalid date"},
+		{`db.c.find({ v: new Date(1e20, 0) })`, "invalid date"},
+	}
+	for _, tc := range tests {
+		_, err := translator.Parse(tc.statement)
+		require.ErrorContains(t, err, tc.err, tc.statement)
+	}
+}
+
+func TestFunctionLiterals(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      bson.JavaScript
+	}{
+		{`db.c.find({ $where: function() { return this.a > this.b } })`, "function() { return this.a > this.b }"},
+		{`db.c.find({ $where: function check() { return /}"/.test(this.s) } })`, `function check() { return /}"/.test(this.s) }`},
+		{"db.c.find({ $where: () => { return `${this.a}` === '1' } })", "() => { return `${this.a}` === '1' }"},
+		{`db.c.find({ $where: (a, b) => a > b })`, "(a, b) => a > b"},
+		{`db.c.find({ $where: doc => doc.a > (doc.b || 0) })`, "doc => doc.a > (doc.b || 0)"},
+	}
+	for _, tc := range tests {
+		op, err := translator.Parse(tc.statement)
+		require.NoError(t, err, tc.statement)
+		require.Equal(t, bson.D{{Key: "$where", Value: tc.want}}, op.Filter, tc.statement)
+	}
+
+	op, err := translator.Parse(`db.c.aggregate([{ $group: { _id: null, n: { $accumulator: {
+		init: function() { return 0 },
+		accumulate: (n) => n + 1,
+		merge: (a, b) => a + b,
+		lang: "js",
+	} } } }])`)
+	require.NoError(t, err)
+	acc := op.Pipeline[0].(bson.D)[0].Value.(bson.D)[1].Value.(bson.D)[0].Value.(bson.D)
+	require.Equal(t, bson.D{
+		{Key: "init", Value: bson.JavaScript("function() { return 0 }")},
+		{Key: "accumulate", Value: bson.JavaScript("(n) => n + 1")},
+		{Key: "merge", Value: bson.JavaScript("(a, b) => a + b")},
+		{Key: "lang", Value: "js"},
+	}, acc)
+}
@@ -0,0 +1,334 @@
+package translator
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/omni/mongo/ast"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Extended JSON represents the BSON types that JSON lacks as wrapper documents,
+// such as {"$oid": "..."} and {"$date": "..."}. Documents copied from Compass or
+// exported by mongoexport are full of them, so with Options.ExtendedJSON a
+// document that contains a wrapper key is converted to the value it stands for
+// instead of being kept as a nested document that no stored value would match.
+// The option is off by default because it changes the meaning of documents
+// that mongosh sends as written, such as a field named $date. Both the canonical
+// and the relaxed forms of Extended JSON v2 are accepted, along with the legacy
+// {"$binary": "...", "$type": "..."} and {"$date": <milliseconds>} forms.
+
+// extendedJSONKeys are the keys that make a document an Extended JSON wrapper,
+// mapped to the other key the wrapper may have.
+var extendedJSONKeys = map[string]string{
+	"$oid":               "",
+	"$symbol":            "",
+	"$numberInt":         "",
+	"$numberLong":        "",
+	"$numberDouble":      "",
+	"$numberDecimal":     "",
+	"$binary":            "$type",
+	"$uuid":              "",
+	"$code":              "$scope",
+	"$timestamp":         "",
+	"$regularExpression": "",
+	"$dbPointer":         "",
+	"$date":              "",
+	"$minKey":            "",
+	"$maxKey":            "",
+	"$undefined":         "",
+}
+
+// extendedJSONValue converts an Extended JSON wrapper document to the BSON value
+// it represents. It reports false if doc is not a wrapper, and returns an error
+// if it is a malformed one.
+func extendedJSONValue(doc bson.D) (any, bool, error) {
+	key := ""
+	for _, e := range doc {
+		if _, ok := extendedJSONKeys[e.Key]; ok {
+			key = e.Key
+			break
+		}
+	}
+	if key == "" {
+		return nil, false, nil
+	}
+
+	var value, extra any
+	hasExtra := false
+	for _, e := range doc {
+		switch {
+		case e.Key == key:
+			value = e.Value
+		case e.Key == extendedJSONKeys[key] && e.Key != "" && !hasExtra:
+			extra, hasExtra = e.Value, true
+		default:
+			return nil, true, fmt.Errorf("invalid %s: unexpected key %q", key, e.Key)
+		}
+	}
+
+	v, err := convertExtendedJSON(key, value, extra, hasExtra)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return v, true, nil
+}
+
+// extendedJSONNode returns the value of an Extended JSON wrapper document as a
+// node located at the document, or the document unchanged if it is not a
+// wrapper.
+func extendedJSONNode(doc *ast.Document) (ast.Node, error) {
+	if !hasExtendedJSONKey(doc) {
+		return doc, nil
+	}
+	d, err := convertDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	v, _, err := extendedJSONValue(d)
+	if err != nil {
+		return nil, errorAt(doc, "%w", err)
+	}
+	return &param{name: "Extended JSON value", value: v, loc: doc.Loc}, nil
+}
+
+// hasExtendedJSONKey reports whether a document has a key that makes it an
+// Extended JSON wrapper.
+func hasExtendedJSONKey(doc *ast.Document) bool {
+	for _, kv := range doc.Pairs {
+		if _, ok := extendedJSONKeys[kv.Key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func convertExtendedJSON(key string, value, extra any, hasExtra bool) (any, error) {
+	if key == "$binary" {
+		return convertExtendedBinary(value, extra, hasExtra)
+	}
+	if key == "$code" {
+		code, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string")
+		}
+		if !hasExtra {
+			return bson.JavaScript(code), nil
+		}
+		scope, ok := extra.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("$scope must be a document")
+		}
+		return bson.CodeWithScope{Code: bson.JavaScript(code), Scope: scope}, nil
+	}
+
+	switch key {
+	case "$timestamp", "$regularExpression", "$dbPointer":
+		doc, ok := value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("must be a document")
+		}
+		return convertExtendedDocument(key, doc)
+	case "$date":
+		return convertExtendedDate(value)
+	case "$minKey", "$maxKey":
+		if n, ok := ToInt64(value); !ok || n != 1 {
+			return nil, fmt.Errorf("must be 1")
+		}
+		if key == "$minKey" {
+			return bson.MinKey{}, nil
+		}
+		return bson.MaxKey{}, nil
+	case "$undefined":
+		if value != true {
+			return nil, fmt.Errorf("must be true")
+		}
+		return bson.Undefined{}, nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("must be a string")
+	}
+	switch key {
+	case "$oid":
+		oid, err := bson.ObjectIDFromHex(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid 24-character hex string", s)
+		}
+		return oid, nil
+	case "$symbol":
+		return bson.Symbol(s), nil
+	case "$numberInt":
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a 32-bit integer", s)
+		}
+		return int32(n), nil
+	case "$numberLong":
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a 64-bit integer", s)
+		}
+		return n, nil
+	case "$numberDouble":
+		switch s {
+		case "Infinity":
+			return math.Inf(1), nil
+		case "-Infinity":
+			return math.Inf(-1), nil
+		case "NaN":
+			return math.NaN(), nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, fmt.Errorf("%q is not a number", s)
+		}
+		return f, nil
+	case "$numberDecimal":
+		d, err := bson.ParseDecimal128(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a decimal", s)
+		}
+		return d, nil
+	case "$uuid":
+		if len(s) != 36 {
+			return nil, fmt.Errorf("%q is not a hyphenated UUID", s)
+		}
+		parsed, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a hyphenated UUID", s)
+		}
+		return bson.Binary{Subtype: bson.TypeBinaryUUID, Data: parsed[:]}, nil
+	}
+	return nil, fmt.Errorf("unsupported Extended JSON type")
+}
+
+// convertExtendedBinary converts {"$binary": {"base64": ..., "subType": ...}},
+// or the legacy {"$binary": ..., "$type": ...}.
+func convertExtendedBinary(value, extra any, hasExtra bool) (any, error) {
+	var data, subtype any
+	switch v := value.(type) {
+	case bson.D:
+		if hasExtra {
+			return nil, fmt.Errorf("unexpected key \"$type\"")
+		}
+		for _, e := range v {
+			switch e.Key {
+			case "base64":
+				data = e.Value
+			case "subType":
+				subtype = e.Value
+			default:
+				return nil, fmt.Errorf("unexpected key %q", e.Key)
+			}
+		}
+	case string:
+		if !hasExtra {
+			return nil, fmt.Errorf("legacy form requires $type")
+		}
+		data, subtype = v, extra
+	default:
+		return nil, fmt.Errorf("must be a document")
+	}
+
+	b64, ok := data.(string)
+	if !ok {
+		return nil, fmt.Errorf("base64 must be a string")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, fmt.Errorf("base64 is not valid base64")
+	}
+	st, ok := subtype.(string)
+	if !ok || len(st) == 0 || len(st) > 2 {
+		return nil, fmt.Errorf("subType must be a 1 or 2 digit hex string")
+	}
+	n, err := strconv.ParseUint(st, 16, 8)
+	if err != nil {
+		return nil, fmt.Errorf("subType must be a 1 or 2 digit hex string")
+	}
+	return bson.Binary{Subtype: byte(n), Data: decoded}, nil
+}
+
+// convertExtendedDocument converts the wrappers whose value is a document.
+func convertExtendedDocument(key string, doc bson.D) (any, error) {
+	fields := make(map[string]any, len(doc))
+	for _, e := range doc {
+		fields[e.Key] = e.Value
+	}
+	var want []string
+	switch key {
+	case "$timestamp":
+		want = []string{"t", "i"}
+	case "$regularExpression":
+		want = []string{"pattern", "options"}
+	case "$dbPointer":
+		want = []string{"$ref", "$id"}
+	}
+	for _, name := range want {
+		if _, ok := fields[name]; !ok {
+			return nil, fmt.Errorf("missing %q", name)
+		}
+	}
+	if len(fields) != len(want) || len(doc) != len(want) {
+		return nil, fmt.Errorf("must contain only %q and %q", want[0], want[1])
+	}
+
+	switch key {
+	case "$timestamp":
+		t, ok := ToInt64(fields["t"])
+		if !ok || t < 0 || t > math.MaxUint32 {
+			return nil, fmt.Errorf("t must be an unsigned 32-bit integer")
+		}
+		i, ok := ToInt64(fields["i"])
+		if !ok || i < 0 || i > math.MaxUint32 {
+			return nil, fmt.Errorf("i must be an unsigned 32-bit integer")
+		}
+		return bson.Timestamp{T: uint32(t), I: uint32(i)}, nil
+	case "$regularExpression":
+		pattern, ok := fields["pattern"].(string)
+		if !ok {
+			return nil, fmt.Errorf("pattern must be a string")
+		}
+		options, ok := fields["options"].(string)
+		if !ok || strings.Trim(options, "ilmsux") != "" {
+			return nil, fmt.Errorf("options must be a string of the flags i, l, m, s, u and x")
+		}
+		return bson.Regex{Pattern: pattern, Options: options}, nil
+	default: // $dbPointer
+		ref, ok := fields["$ref"].(string)
+		if !ok {
+			return nil, fmt.Errorf("$ref must be a string")
+		}
+		id, ok := fields["$id"].(bson.ObjectID)
+		if !ok {
+			return nil, fmt.Errorf("$id must be an ObjectId")
+		}
+		return bson.DBPointer{DB: ref, Pointer: id}, nil
+	}
+}
+
+// convertExtendedDate converts the value of $date: an ISO-8601 string in the
+// relaxed form, or milliseconds since the epoch in the canonical form, where
+// {"$numberLong": ...} has already been converted, and the legacy form.
+func convertExtendedDate(value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return parseDateTime(v)
+	case int32:
+		return bson.DateTime(v), nil
+	case int64:
+		return bson.DateTime(v), nil
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return nil, fmt.Errorf("%v is not a whole number of milliseconds", v)
+		}
+		return bson.DateTime(int64(v)), nil
+	}
+	return nil, fmt.Errorf("must be an ISO-8601 string or milliseconds since the epoch")
+}
@@ -0,0 +1,122 @@
+package translator_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestExtendedJSONValues(t *testing.T) {
+	oid, err := bson.ObjectIDFromHex("507f1f77bcf86cd799439011")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		value string
+		want  any
+	}{
+		{"oid", `{"$oid": "507f1f77bcf86cd799439011"}`, oid},
+		{"relaxed date", `{"$date": "2024-01-01T00:00:00Z"}`, bson.DateTime(1704067200000)},
+		{"canonical date", `{"$date": {"$numberLong": "1704067200000"}}`, bson.DateTime(1704067200000)},
+		{"legacy date", `{"$date": 1704067200000}`, bson.DateTime(1704067200000)},
+		{"numberInt", `{"$numberInt": "42"}`, int32(42)},
+		{"numberLong", `{"$numberLong": "42"}`, int64(42)},
+		{"numberDouble", `{"$numberDouble": "1.5"}`, 1.5},
+		{"numberDouble infinity", `{"$numberDouble": "-Infinity"}`, math.Inf(-1)},
+		{"numberDecimal", `{"$numberDecimal": "1.10"}`, func() bson.Decimal128 { d, _ := bson.ParseDecimal128("1.10"); return d }()},
+		{"binary", `{"$binary": {"base64": "AQI=", "subType": "80"}}`, bson.Binary{Subtype: 0x80, Data: []byte{1, 2}}},
+		{"legacy binary", `{"$binary": "AQI=", "$type": "0"}`, bson.Binary{Subtype: 0, Data: []byte{1, 2}}},
+		{"uuid", `{"$uuid": "3b241101-e2bb-4255-8caf-4136c566a962"}`, bson.Binary{Subtype: bson.TypeBinaryUUID, Data: []byte{0x3b, 0x24, 0x11, 0x01, 0xe2, 0xbb, 0x42, 0x55, 0x8c, 0xaf, 0x41, 0x36, 0xc5, 0x66, 0xa9, 0x62}}},
+		{"regularExpression", `{"$regularExpression": {"pattern": "^a", "options": "im"}}`, bson.Regex{Pattern: "^a", Options: "im"}},
+		{"timestamp", `{"$timestamp": {"t": 4294967295, "i": 1}}`, bson.Timestamp{T: math.MaxUint32, I: 1}},
+		{"code", `{"$code": "function() {}"}`, bson.JavaScript("function() {}")},
+		{"code with scope", `{"$code": "x", "$scope": {"x": 1}}`, bson.CodeWithScope{Code: "x", Scope: bson.D{{Key: "x", Value: int32(1)}}}},
+		{"symbol", `{"$symbol": "s"}`, bson.Symbol("s")},
+		{"dbPointer", `{"$dbPointer": {"$ref": "c", "$id": {"$oid": "507f1f77bcf86cd799439011"}}}`, bson.DBPointer{DB: "c", Pointer: oid}},
+		{"minKey", `{"$minKey": 1}`, bson.MinKey{}},
+		{"maxKey", `{"$maxKey": 1}`, bson.MaxKey{}},
+		{"undefined", `{"$undefined": true}`, bson.Undefined{}},
+		{"nested in array", `[{"$numberLong": "1"}]`, bson.A{int64(1)}},
+		{"variable", `v`, oid},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			op, err := parseExtendedJSON(`const v = {"$oid": "507f1f77bcf86cd799439011"}; db.c.find({ x: ` + tc.value + ` })`)
+			require.NoError(t, err)
+			require.Equal(t, bson.D{{Key: "x", Value: tc.want}}, op.Filter)
+		})
+	}
+}
+
+func TestExtendedJSONOff(t *testing.T) {
+	for _, statement := range []string{
+		`db.c.find({ x: {"$date": "2024-01-01T00:00:00Z"} })`,
+		`const v = {"$date": "2024-01-01T00:00:00Z"}; db.c.find({ x: v })`,
+	} {
+		op, err := translator.Parse(statement)
+		require.NoError(t, err, statement)
+		require.Equal(t, bson.D{{Key: "x", Value: bson.D{{Key: "$date", Value: "2024-01-01T00:00:00Z"}}}}, op.Filter, statement)
+	}
+}
+
+func TestExtendedJSONErrors(t *testing.T) {
+	tests := []struct {
+		value string
+		err   string
+	}{
+		{`{"$oid": 1}`, "invalid $oid: must be a string"},
+		{`{"$oid": "xyz"}`, `invalid $oid: "xyz" is not a valid 24-character hex string`},
+		{`{"$oid": "507f1f77bcf86cd799439011", "extra": 1}`, `invalid $oid: unexpected key "extra"`},
+		{`{"$numberInt": "3000000000"}`, `invalid $numberInt: "3000000000" is not a 32-bit integer`},
+		{`{"$numberLong": 5}`, "invalid $numberLong: must be a string"},
+		{`{"$date": "yesterday"}`, "invalid $date: invalid date format: yesterday"},
+		{`{"$date": true}`, "invalid $date: must be an ISO-8601 string or milliseconds since the epoch"},
+		{`{"$binary": {"base64": "!!", "subType": "00"}}`, "invalid $binary: base64 is not valid base64"},
+		{`{"$binary": {"base64": "AQI=", "subType": "100"}}`, "invalid $binary: subType must be a 1 or 2 digit hex string"},
+		{`{"$binary": "AQI="}`, "invalid $binary: legacy form requires $type"},
+		{`{"$timestamp": {"t": 1}}`, `invalid $timestamp: missing "i"`},
+		{`{"$timestamp": {"t": -1, "i": 1}}`, "invalid $timestamp: t must be an unsigned 32-bit integer"},
+		{`{"$regularExpression": {"pattern": "a", "options": "q"}}`, "invalid $regularExpression: options must be a string of the flags"},
+		{`{"$minKey": 2}`, "invalid $minKey: must be 1"},
+	}
+	for _, tc := range tests {
+		_, err := parseExtendedJSON(`db.c.find({ x: ` + tc.value + ` })`)
+		require.ErrorContains(t, err, tc.err, tc.value)
+	}
+}
+
+func parseExtendedJSON(statement string) (*translator.Operation, error) {
+	stmt, err := translator.PrepareWithOptions(statement, translator.Options{ExtendedJSON: true})
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Bind(nil)
+}
+
+func TestExtendedJSONFilter(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := "testdb_extjson_filter_" + db.Name
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client, gomongo.WithExtendedJSONInput())
+		ctx := context.Background()
+
+		_, err := gc.Execute(ctx, dbName, `db.test.insertOne({_id: ObjectId("507f1f77bcf86cd799439011"), ts: ISODate("2024-01-01T00:00:00Z"), n: NumberLong(5)})`)
+		require.NoError(t, err)
+
+		// A document pasted from Compass or mongoexport.
+		result, err := gc.Execute(ctx, dbName, `db.test.find({"_id": {"$oid": "507f1f77bcf86cd799439011"}, "ts": {"$date": "2024-01-01T00:00:00Z"}, "n": {"$numberLong": "5"}})`)
+		require.NoError(t, err)
+		require.Len(t, result.Value, 1)
+
+		result, err = gc.Execute(ctx, dbName, `db.test.find({"ts": {"$gte": {"$date": {"$numberLong": "1704067200000"}}}})`)
+		require.NoError(t, err)
+		require.Len(t, result.Value, 1)
+	})
+}
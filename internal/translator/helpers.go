@@ -1,7 +1,10 @@
 package translator
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"strconv"
 	"strings"
 
@@ -9,8 +12,17 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-// convertNode converts an omni AST node to a Go value for BSON.
+// convertNode converts an omni AST node to a Go value for BSON. An error is
+// located at the innermost node that caused it.
 func convertNode(node ast.Node) (any, error) {
+	v, err := convertValue(node)
+	if err != nil {
+		return nil, locate(node.GetLoc(), err)
+	}
+	return v, nil
+}
+
+func convertValue(node ast.Node) (any, error) {
 	switch n := node.(type) {
 	case *ast.Document:
 		return convertDocument(n)
@@ -28,7 +40,15 @@ func convertNode(node ast.Node) (any, error) {
 		return bson.Regex{Pattern: n.Pattern, Options: n.Flags}, nil
 	case *ast.HelperCall:
 		return convertHelper(n)
+	case *param:
+		return n.value, nil
 	case *ast.Identifier:
+		switch n.Name {
+		case "NaN":
+			return math.NaN(), nil
+		case "Infinity":
+			return math.Inf(1), nil
+		}
 		return nil, fmt.Errorf("unsupported value: identifier %q", n.Name)
 	default:
 		return nil, fmt.Errorf("unsupported AST node type: %T", node)
@@ -61,28 +81,110 @@ func convertArray(arr *ast.Array) (bson.A, error) {
 	return result, nil
 }
 
-// parseNumber parses a number string to int32, int64, or float64.
+// errNumberRange reports a BigInt literal that does not fit in an int64.
+var errNumberRange = errors.New("does not fit in a 64-bit integer")
+
+// parseNumber parses a JavaScript number literal to int32, int64, or float64.
+// Besides decimal integers and floats it accepts hex (0x1F), octal (0o17 and
+// the legacy 017), binary (0b101) and BigInt (123n) literals, numeric
+// separators (1_000), NaN and Infinity. Integers that do not fit in int64
+// become float64, as in JavaScript, and -0 is a double, as mongosh stores it.
 func parseNumber(s string) (any, error) {
-	if strings.Contains(s, ".") || strings.Contains(s, "e") || strings.Contains(s, "E") {
-		f, err := strconv.ParseFloat(s, 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid number: %s", s)
+	invalid := fmt.Errorf("invalid number: %s", s)
+	text := s
+	neg := false
+	if text != "" && (text[0] == '-' || text[0] == '+') {
+		neg = text[0] == '-'
+		text = text[1:]
+	}
+	sign := 1.0
+	if neg {
+		sign = -1
+	}
+	switch text {
+	case "NaN":
+		return math.NaN(), nil
+	case "Infinity":
+		return math.Inf(int(sign)), nil
+	}
+	if !validSeparators(text) {
+		return nil, invalid
+	}
+	text = strings.ReplaceAll(text, "_", "")
+
+	bigint := strings.HasSuffix(text, "n")
+	if bigint {
+		text = text[:len(text)-1]
+	}
+	base := 10
+	switch {
+	case strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X"):
+		base, text = 16, text[2:]
+	case strings.HasPrefix(text, "0o") || strings.HasPrefix(text, "0O"):
+		base, text = 8, text[2:]
+	case strings.HasPrefix(text, "0b") || strings.HasPrefix(text, "0B"):
+		base, text = 2, text[2:]
+	case len(text) > 1 && text[0] == '0' && !bigint && strings.Trim(text, "01234567") == "":
+		base = 8 // legacy octal
+	case bigint && len(text) > 1 && text[0] == '0':
+		return nil, invalid
+	}
+
+	if base == 10 && !bigint && strings.ContainsAny(text, ".eE") {
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return nil, invalid
 		}
-		return f, nil
+		return sign * f, nil
 	}
 
-	i, err := strconv.ParseInt(s, 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("invalid number: %s", s)
+	if neg {
+		text = "-" + text
+	}
+	i, err := strconv.ParseInt(text, base, 64)
+	switch {
+	case err == nil:
+	case errors.Is(err, strconv.ErrRange) && !bigint:
+		n, ok := new(big.Int).SetString(text, base)
+		if !ok {
+			return nil, invalid
+		}
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return f, nil
+	case errors.Is(err, strconv.ErrRange):
+		return nil, fmt.Errorf("invalid number: %s %w", s, errNumberRange)
+	default:
+		return nil, invalid
 	}
 
-	if i >= -2147483648 && i <= 2147483647 {
+	if bigint {
+		return i, nil
+	}
+	if i == 0 && neg {
+		return math.Copysign(0, -1), nil
+	}
+	if i >= math.MinInt32 && i <= math.MaxInt32 {
 		return int32(i), nil
 	}
 	return i, nil
 }
 
-// ToInt64 converts various numeric types to int64.
+// validSeparators reports whether every numeric separator in a number literal
+// is between two digits.
+func validSeparators(s string) bool {
+	isHexDigit := func(c byte) bool {
+		return isDigit(c) || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '_' && (i == 0 || i == len(s)-1 || !isHexDigit(s[i-1]) || !isHexDigit(s[i+1])) {
+			return false
+		}
+	}
+	return true
+}
+
+// ToInt64 converts various numeric types to int64. It reports false for a
+// double that is not a whole number or does not fit in an int64.
 func ToInt64(v any) (int64, bool) {
 	switch n := v.(type) {
 	case int:
@@ -92,24 +194,22 @@ func ToInt64(v any) (int64, bool) {
 	case int64:
 		return n, true
 	case float64:
+		if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 {
+			return 0, false
+		}
 		return int64(n), true
 	}
 	return 0, false
 }
 
-// ToInt32 converts various numeric types to int32.
+// ToInt32 converts various numeric types to int32. It reports false for a
+// value that is not a whole number or does not fit in an int32.
 func ToInt32(v any) (int32, bool) {
-	switch n := v.(type) {
-	case int:
-		return int32(n), true
-	case int32:
-		return n, true
-	case int64:
-		return int32(n), true
-	case float64:
-		return int32(n), true
+	n, ok := ToInt64(v)
+	if !ok || n < math.MinInt32 || n > math.MaxInt32 {
+		return 0, false
 	}
-	return 0, false
+	return int32(n), true
 }
 
 // requireDocument extracts and converts a document node from args at the given index.
@@ -117,11 +217,15 @@ func requireDocument(args []ast.Node, idx int, context string) (bson.D, error) {
 	if idx >= len(args) {
 		return nil, fmt.Errorf("%s must be a document", context)
 	}
-	doc, ok := args[idx].(*ast.Document)
-	if !ok {
-		return nil, fmt.Errorf("%s must be a document", context)
+	switch n := args[idx].(type) {
+	case *ast.Document:
+		return convertDocument(n)
+	case *param:
+		if v, ok := n.value.(bson.D); ok {
+			return v, nil
+		}
 	}
-	return convertDocument(doc)
+	return nil, errorAt(args[idx], "%s must be a document", context)
 }
 
 // requireString extracts a string value from args at the given index.
@@ -129,9 +233,29 @@ func requireString(args []ast.Node, idx int, context string) (string, error) {
 	if idx >= len(args) {
 		return "", fmt.Errorf("%s must be a string", context)
 	}
-	str, ok := args[idx].(*ast.StringLiteral)
-	if !ok {
-		return "", fmt.Errorf("%s must be a string", context)
+	switch n := args[idx].(type) {
+	case *ast.StringLiteral:
+		return n.Value, nil
+	case *param:
+		if v, ok := n.value.(string); ok {
+			return v, nil
+		}
+	}
+	return "", errorAt(args[idx], "%s must be a string", context)
+}
+
+// requireArray extracts and converts an array node from args at the given index.
+func requireArray(args []ast.Node, idx int, context string) (bson.A, error) {
+	if idx >= len(args) {
+		return nil, fmt.Errorf("%s must be an array", context)
+	}
+	switch n := args[idx].(type) {
+	case *ast.Array:
+		return convertArray(n)
+	case *param:
+		if v, ok := n.value.(bson.A); ok {
+			return v, nil
+		}
 	}
-	return str.Value, nil
+	return nil, errorAt(args[idx], "%s must be an array", context)
 }
@@ -0,0 +1,163 @@
+package translator_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestNumberLiterals(t *testing.T) {
+	tests := []struct {
+		literal string
+		want    any
+	}{
+		{"42", int32(42)},
+		{"-42", int32(-42)},
+		{"3000000000", int64(3000000000)},
+		{"9223372036854775807", int64(math.MaxInt64)},
+		{"99999999999999999999", 1e20},
+		{"1.5", 1.5},
+		{".5", 0.5},
+		{"5.", 5.0},
+		{"1e3", 1000.0},
+		{"1e400", math.Inf(1)},
+		{"0x1F", int32(31)},
+		{"-0x10", int32(-16)},
+		{"0o17", int32(15)},
+		{"0b101", int32(5)},
+		{"017", int32(15)},
+		{"019", int32(19)},
+		{"1_000_000", int32(1000000)},
+		{"+5", int32(5)},
+		{"Infinity", math.Inf(1)},
+		{"-Infinity", math.Inf(-1)},
+		{"12n", int64(12)},
+		{"-12n", int64(-12)},
+		{"9223372036854775807n", int64(math.MaxInt64)},
+		{"-9223372036854775808n", int64(math.MinInt64)},
+		{"0x10n", int64(16)},
+		{"NumberLong(0x10)", int64(16)},
+		{"NumberInt(1e3)", int32(1000)},
+		{"Double(0x10)", 16.0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.literal, func(t *testing.T) {
+			op, err := translator.Parse(`db.c.find({ a: ` + tc.literal + ` })`)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, op.Filter[0].Value)
+		})
+	}
+
+	op, err := translator.Parse(`db.c.find({ a: -0, b: NaN })`)
+	require.NoError(t, err)
+	negZero := op.Filter[0].Value.(float64)
+	require.True(t, negZero == 0 && math.Signbit(negZero))
+	require.True(t, math.IsNaN(op.Filter[1].Value.(float64)))
+
+	op, err = translator.Parse(`db.c.find().limit(0x10).skip(1e1)`)
+	require.NoError(t, err)
+	require.Equal(t, int64(16), *op.Limit)
+	require.Equal(t, int64(10), *op.Skip)
+
+	op, err = translator.Parse(`db.setProfilingLevel(0x1, 1e2)`)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), *op.ProfilingLevel)
+	require.Equal(t, int32(100), *op.SlowMS)
+}
+
+func TestNumberLiteralErrors(t *testing.T) {
+	tests := []struct {
+		statement string
+		err       string
+	}{
+		{`db.c.find().limit(2.5)`, "limit() requires an integer argument"},
+		{`db.c.find({ a: NumberInt(3000000000) })`, "Int32() argument 3000000000 is not a 32-bit integer"},
+		{`db.c.find({ a: NumberLong(1.5) })`, "Long() argument 1.5 is not a 64-bit integer"},
+		{`db.c.find({ a: Timestamp(4294967296, 1) })`, "invalid Timestamp t value: 4294967296 is not an unsigned 32-bit integer"},
+		{`db.c.find({ a: Timestamp({ t: -1, i: 1 }) })`, "timestamp t must be an unsigned 32-bit integer"},
+		{`db.c.find({ a: Date(1e16) })`, "invalid timestamp: 1e16"},
+		{`db.setProfilingLevel(1, 2.5)`, "setProfilingLevel() slowms must be an integer"},
+		{`db.setProfilingLevel(1, 1e12)`, "setProfilingLevel() slowms 1e+12 is out of range"},
+		{`db.setProfilingLevel(1, { slowms: NumberLong("3000000000") })`, "setProfilingLevel() slowms 3000000000 is out of range"},
+		{`db.setProfilingLevel(1.5)`, "setProfilingLevel() level must be an integer"},
+		{`db.setProfilingLevel(0x3)`, "setProfilingLevel() level 3 is out of range [0..2]"},
+	}
+	for _, tc := range tests {
+		_, err := translator.Parse(tc.statement)
+		require.ErrorContains(t, err, tc.err, tc.statement)
+	}
+}
+
+func TestToInt(t *testing.T) {
+	tests := []struct {
+		value  any
+		want64 int64
+		ok64   bool
+		want32 int32
+		ok32   bool
+	}{
+		{int32(7), 7, true, 7, true},
+		{int64(1) << 40, 1 << 40, true, 0, false},
+		{7.0, 7, true, 7, true},
+		{7.5, 0, false, 0, false},
+		{1e19, 0, false, 0, false},
+		{math.NaN(), 0, false, 0, false},
+		{math.Inf(1), 0, false, 0, false},
+		{-2147483648.0, -2147483648, true, math.MinInt32, true},
+		{"7", 0, false, 0, false},
+	}
+	for _, tc := range tests {
+		n64, ok64 := translator.ToInt64(tc.value)
+		require.Equal(t, tc.ok64, ok64, "%v", tc.value)
+		require.Equal(t, tc.want64, n64, "%v", tc.value)
+		n32, ok32 := translator.ToInt32(tc.value)
+		require.Equal(t, tc.ok32, ok32, "%v", tc.value)
+		require.Equal(t, tc.want32, n32, "%v", tc.value)
+	}
+}
+
+func TestDateLiterals(t *testing.T) {
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"2024-01-02", "2024-01-02T00:00:00Z"},
+		{"2024-01-02 10:00:00", "2024-01-02T10:00:00Z"},
+		{"2024-01-02T10:00:00+05:30", "2024-01-02T04:30:00Z"},
+		{"2024-01-02T10:00:00-0130", "2024-01-02T11:30:00Z"},
+		{"2024-01-02T10:00:00.1234567Z", "2024-01-02T10:00:00.123Z"},
+		{"2024-01-02T10:00", "2024-01-02T10:00:00Z"},
+		{"20240102", "2024-01-02T00:00:00Z"},
+		{"2024", "2024-01-01T00:00:00Z"},
+		{"2024-03", "2024-03-01T00:00:00Z"},
+		{"+002024-01-02", "2024-01-02T00:00:00Z"},
+		{"2024-01-02T24:00:00Z", "2024-01-03T00:00:00Z"},
+		{"Tue, 02 Jan 2024 10:00:00 GMT", "2024-01-02T10:00:00Z"},
+		{"Tue Jan 02 2024 10:00:00 GMT+0530 (India Standard Time)", "2024-01-02T04:30:00Z"},
+		{"Jan 2, 2024 10:00 PM", "2024-01-02T22:00:00Z"},
+		{"January 2, 2024", "2024-01-02T00:00:00Z"},
+		{"2 January 2024 10:00:00 UTC", "2024-01-02T10:00:00Z"},
+		{"2024/01/02", "2024-01-02T00:00:00Z"},
+		{"01/02/2024 10:00:00", "2024-01-02T10:00:00Z"},
+		{"1/2/24", "2024-01-02T00:00:00Z"},
+		{"12/31/1969 11:59:59 PM", "1969-12-31T23:59:59Z"},
+		{"2024-1-2 3:04:05.6 -07:00", "2024-01-02T10:04:05.6Z"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.date, func(t *testing.T) {
+			op, err := translator.Parse(`db.c.find({ a: ISODate("` + tc.date + `"), b: Date("` + tc.date + `") })`)
+			require.NoError(t, err)
+			got := op.Filter[0].Value.(bson.DateTime).Time().UTC().Format("2006-01-02T15:04:05.999Z07:00")
+			require.Equal(t, tc.want, got)
+			require.Equal(t, op.Filter[0].Value, op.Filter[1].Value)
+		})
+	}
+
+	for _, date := range []string{"yesterday", "2024-02-30", "2024-13-01", "2024-01-02T25:00:00Z", "2024-01-02T10:00:00+24:00", "Jan 2024", "+275761-01-01"} {
+		_, err := translator.Parse(`db.c.find({ a: ISODate("` + date + `") })`)
+		require.Error(t, err, date)
+	}
+}
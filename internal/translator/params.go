@@ -0,0 +1,351 @@
+package translator
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytebase/omni/mongo/ast"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Params holds the values bound to the placeholders of a statement. $1, $2, ...
+// are bound from Positional in order and :name from Named.
+type Params struct {
+	Positional []any
+	Named      map[string]any
+}
+
+// param is a placeholder bound to a value. It replaces the placeholder's
+// identifier in the AST, so the value is only ever converted as a single value
+// and can never change the structure of the statement.
+type param struct {
+	name  string
+	value any
+	loc   ast.Loc
+}
+
+func (p *param) GetLoc() ast.Loc { return p.loc }
+
+// integer returns the value of a parameter bound to an integer.
+func (p *param) integer() (int64, bool) {
+	switch v := p.value.(type) {
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+	return 0, false
+}
+
+// isUpdate reports whether v is an update document or pipeline.
+func isUpdate(v any) bool {
+	switch v.(type) {
+	case bson.D, bson.A:
+		return true
+	}
+	return false
+}
+
+// positionalParam matches the name of a positional placeholder such as $1.
+var positionalParam = regexp.MustCompile(`^\$[0-9]+$`)
+
+// rewriteNamedParams rewrites each :name placeholder to $name, which the parser
+// accepts as an identifier, and returns the offsets of the rewritten
+// placeholders. The rewrite keeps every byte offset unchanged.
+func rewriteNamedParams(statement string) (string, map[int]bool) {
+	var buf []byte
+	var offsets map[int]bool
+	var prev byte // last significant byte before i
+	for i := 0; i < len(statement); i++ {
+		c := statement[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			continue
+		case c == '"' || c == '\'' || c == '`':
+			i = skipQuoted(statement, i, c)
+		case c == '/' && i+1 < len(statement) && statement[i+1] == '/':
+			for i < len(statement) && statement[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && i+1 < len(statement) && statement[i+1] == '*':
+			if end := strings.Index(statement[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(statement)
+			}
+			continue
+		case c == '/' && startsValue(prev):
+			i = skipRegex(statement, i)
+		case c == ':' && startsValue(prev) && prev != '{' && i+1 < len(statement) && isIdentStart(statement[i+1]):
+			if buf == nil {
+				buf = []byte(statement)
+				offsets = make(map[int]bool)
+			}
+			buf[i] = '$'
+			offsets[i] = true
+		}
+		prev = c
+	}
+	if buf == nil {
+		return statement, nil
+	}
+	return string(buf), offsets
+}
+
+// startsValue reports whether a value may start after the significant byte prev.
+func startsValue(prev byte) bool {
+	switch prev {
+	case 0, '(', '[', '{', ',', ':', '=', '!', '&', '|', '?', ';', '+', '-', '*', '%':
+		return true
+	}
+	return false
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// skipQuoted returns the offset of the quote closing the string that starts at i.
+func skipQuoted(s string, i int, quote byte) int {
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return len(s)
+}
+
+// skipRegex returns the offset of the slash closing the regular expression
+// literal that starts at i.
+func skipRegex(s string, i int) int {
+	inClass := false
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				return i
+			}
+		case '\n':
+			return i
+		}
+	}
+	return len(s)
+}
+
+// binder replaces the placeholders of a statement with their bound values.
+type binder struct {
+	params       *Params
+	named        map[int]bool // offsets of the :name placeholders
+	script       *script      // expressions and variables, or nil
+	extendedJSON bool         // convert Extended JSON wrapper documents
+	vars         map[string]any
+	generates    bool // whether a helper that generates a new value was found
+}
+
+// bindStatement returns a copy of the statement with every placeholder replaced
+// by its bound value and every expression by its value. The statement itself
+// is left unchanged.
+func (b *binder) bindStatement(node ast.Node) (ast.Node, error) {
+	if b.script != nil {
+		if err := b.declare(); err != nil {
+			return nil, err
+		}
+	}
+	switch n := node.(type) {
+	case *ast.CollectionStatement:
+		stmt := *n
+		var err error
+		if stmt.Args, err = b.bindNodes(n.Args); err != nil {
+			return nil, err
+		}
+		if stmt.ExplainArgs, err = b.bindNodes(n.ExplainArgs); err != nil {
+			return nil, err
+		}
+		if n.CursorMethods != nil {
+			stmt.CursorMethods = make([]ast.CursorMethod, len(n.CursorMethods))
+			for i, cm := range n.CursorMethods {
+				if cm.Args, err = b.bindNodes(cm.Args); err != nil {
+					return nil, err
+				}
+				stmt.CursorMethods[i] = cm
+			}
+		}
+		return &stmt, nil
+	case *ast.DatabaseStatement:
+		stmt := *n
+		var err error
+		if stmt.Args, err = b.bindNodes(n.Args); err != nil {
+			return nil, err
+		}
+		return &stmt, nil
+	default:
+		return node, nil
+	}
+}
+
+// isStatic reports whether a statement translates to the same operation every
+// time: it has no expressions, no placeholders, which binding without
+// parameters fails on, and no helpers that generate a new value, such as
+// ObjectId() without arguments.
+func isStatic(node ast.Node, named map[int]bool, script *script) bool {
+	if script != nil {
+		return false
+	}
+	b := &binder{named: named}
+	_, err := b.bindStatement(node)
+	return err == nil && !b.generates
+}
+
+func (b *binder) bindNodes(nodes []ast.Node) ([]ast.Node, error) {
+	if nodes == nil {
+		return nil, nil
+	}
+	result := make([]ast.Node, len(nodes))
+	for i, node := range nodes {
+		bound, err := b.bind(node)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = bound
+	}
+	return result, nil
+}
+
+func (b *binder) bind(node ast.Node) (ast.Node, error) {
+	switch n := node.(type) {
+	case *ast.Document:
+		doc := &ast.Document{Pairs: make([]ast.KeyValue, len(n.Pairs)), Loc: n.Loc}
+		for i, kv := range n.Pairs {
+			value, err := b.bind(kv.Value)
+			if err != nil {
+				return nil, err
+			}
+			kv.Value = value
+			doc.Pairs[i] = kv
+		}
+		if b.extendedJSON {
+			return extendedJSONNode(doc)
+		}
+		return doc, nil
+	case *ast.Array:
+		elements, err := b.bindNodes(n.Elements)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.Array{Elements: elements, Loc: n.Loc}, nil
+	case *ast.Identifier:
+		if b.script != nil {
+			if e, ok := b.script.slots[n.Loc.Start]; ok {
+				value, err := b.evaluator().evaluate(e)
+				if err != nil {
+					return nil, err
+				}
+				return &param{name: "expression", value: value, loc: n.Loc}, nil
+			}
+		}
+		return b.bindIdentifier(n)
+	case *ast.HelperCall:
+		if generatingHelpers[n.Name] && len(n.Args) == 0 {
+			b.generates = true
+		}
+		return node, nil
+	default:
+		return node, nil
+	}
+}
+
+// generatingHelpers are the helpers that generate a new value when called
+// without arguments.
+var generatingHelpers = map[string]bool{"ObjectId": true, "ISODate": true, "Date": true}
+
+// declare evaluates the variables of the script in order.
+func (b *binder) declare() error {
+	b.vars = make(map[string]any, len(b.script.decls))
+	ev := b.evaluator()
+	for _, d := range b.script.decls {
+		value, err := ev.value(d.value)
+		if err != nil {
+			return err
+		}
+		b.vars[d.name] = value
+	}
+	return nil
+}
+
+func (b *binder) evaluator() *evaluator {
+	return &evaluator{source: b.script.source, vars: b.vars, bind: b.bindIdentifier, extendedJSON: b.extendedJSON}
+}
+
+// bindIdentifier replaces a placeholder identifier with its bound value.
+// Identifiers that are not placeholders are returned unchanged.
+func (b *binder) bindIdentifier(id *ast.Identifier) (ast.Node, error) {
+	var name string
+	var value any
+	var ok bool
+	switch {
+	case b.named[id.Loc.Start]:
+		name = ":" + id.Name[1:]
+		if b.params != nil {
+			value, ok = b.params.Named[id.Name[1:]]
+		}
+	case positionalParam.MatchString(id.Name):
+		name = id.Name
+		n, err := strconv.Atoi(id.Name[1:])
+		if err == nil && b.params != nil && n >= 1 && n <= len(b.params.Positional) {
+			value, ok = b.params.Positional[n-1], true
+		}
+	default:
+		return id, nil
+	}
+	if !ok {
+		return nil, errorAt(id, "no value bound to parameter %s", name)
+	}
+	converted, err := convertParam(value)
+	if err != nil {
+		return nil, errorAt(id, "invalid value for parameter %s: %w", name, err)
+	}
+	return &param{name: name, value: converted, loc: id.Loc}, nil
+}
+
+// convertParam converts a Go value to the BSON value a literal in the statement
+// would produce: documents and structs become bson.D, slices become bson.A and
+// time.Time becomes bson.DateTime.
+func convertParam(v any) (any, error) {
+	// Common scalars are converted directly, as bson.Marshal would.
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string, bool, int32, int64, float64, bson.ObjectID, bson.DateTime, bson.Decimal128:
+		return v, nil
+	case int:
+		if v >= math.MinInt32 && v <= math.MaxInt32 {
+			return int32(v), nil
+		}
+		return int64(v), nil
+	case time.Time:
+		return bson.NewDateTimeFromTime(v), nil
+	}
+
+	t, data, err := bson.MarshalValue(v)
+	if err != nil {
+		return nil, err
+	}
+	var result any
+	if err := (bson.RawValue{Type: t, Value: data}).Unmarshal(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
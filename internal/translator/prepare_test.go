@@ -0,0 +1,114 @@
+package translator_test
+
+import (
+	"testing"
+
+	"github.com/bytebase/gomongo/internal/translator"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const benchmarkStatement = `db.orders.find({ status: "shipped", total: { $gte: 100 }, tags: { $in: ["a", "b", "c"] } }, { _id: 0, total: 1 }).sort({ created: -1 }).limit(50)`
+
+const benchmarkParamStatement = `db.orders.find({ status: $1, total: { $gte: :minTotal }, tags: { $in: ["a", "b", "c"] } }, { _id: 0, total: 1 }).sort({ created: -1 }).limit($2)`
+
+var benchmarkParams = &translator.Params{
+	Positional: []any{"shipped", 50},
+	Named:      map[string]any{"minTotal": 100},
+}
+
+func BenchmarkParse(b *testing.B) {
+	for b.Loop() {
+		if _, err := translator.Parse(benchmarkStatement); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPreparedBind(b *testing.B) {
+	stmt, err := translator.Prepare(benchmarkStatement)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for b.Loop() {
+		if _, err := stmt.Bind(nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseWithParams(b *testing.B) {
+	for b.Loop() {
+		if _, err := translator.ParseWithParams(benchmarkParamStatement, benchmarkParams); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPreparedBindWithParams(b *testing.B) {
+	stmt, err := translator.Prepare(benchmarkParamStatement)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for b.Loop() {
+		if _, err := stmt.Bind(benchmarkParams); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestPreparedBind(t *testing.T) {
+	stmt, err := translator.Prepare(`db.orders.find({ status: $1 }).limit(:n)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, status := range []string{"new", "shipped"} {
+		op, err := stmt.Bind(&translator.Params{
+			Positional: []any{status},
+			Named:      map[string]any{"n": 10},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (bson.D{{Key: "status", Value: status}}); !equalDocs(op.Filter, want) {
+			t.Fatalf("filter = %v, want %v", op.Filter, want)
+		}
+		if op.Limit == nil || *op.Limit != 10 {
+			t.Fatalf("limit = %v, want 10", op.Limit)
+		}
+	}
+
+	if _, err := stmt.Bind(nil); err == nil {
+		t.Fatal("expected an error for unbound parameters")
+	}
+}
+
+func TestPreparedBindGeneratedValues(t *testing.T) {
+	stmt, err := translator.Prepare(`db.orders.insertOne({ _id: ObjectId(), n: 1 })`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := stmt.Bind(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := stmt.Bind(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Document[0].Value == second.Document[0].Value {
+		t.Fatalf("ObjectId() generated the same value twice: %v", first.Document[0].Value)
+	}
+}
+
+func equalDocs(a, b bson.D) bool {
+	x, err := bson.Marshal(a)
+	if err != nil {
+		return false
+	}
+	y, err := bson.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(x) == string(y)
+}
@@ -0,0 +1,1086 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// The parser does not accept JavaScript expressions, such as arithmetic,
+// new Date(), Date.now() or variables declared with const, let and var. Before
+// parsing, a statement is read as a small subset of JavaScript: declarations
+// are blanked out and each expression in a value position is replaced by a $
+// identifier padded to the same length. The binder evaluates the expression
+// when the statement is bound, so Date.now() is read on every execution.
+// Statements that do not fit the subset are parsed as written. Function
+// literals, such as the body of $where, are not evaluated: their source text is
+// sent to the server as BSON Code.
+
+// script is a statement read as JavaScript.
+type script struct {
+	source string
+	decls  []jsDecl
+	slots  map[int]jsExpr // expressions replaced by a $ identifier, by offset
+}
+
+// jsDecl is a variable declared with const, let or var.
+type jsDecl struct {
+	jsSpan // the variable name
+	kind   string
+	name   string
+	value  jsExpr
+}
+
+// jsExpr is a JavaScript expression node.
+type jsExpr interface {
+	span() jsSpan
+}
+
+// jsSpan is the byte range of a node in the statement.
+type jsSpan struct {
+	start, end int
+}
+
+func (s jsSpan) span() jsSpan { return s }
+
+type (
+	// jsLiteral is a number, string, regular expression, boolean or null.
+	jsLiteral struct {
+		jsSpan
+		value any // jsNumber, jsBigInt, string, bson.Regex, bool or nil
+	}
+	// jsInvalid is a literal that is valid JavaScript but has no BSON value,
+	// such as a BigInt beyond int64. It fails when evaluated, so that the error
+	// is located within its method call.
+	jsInvalid struct {
+		jsSpan
+		err error
+	}
+	jsIdent struct {
+		jsSpan
+		name string
+	}
+	jsObject struct {
+		jsSpan
+		keys   []string
+		values []jsExpr
+	}
+	jsArray struct {
+		jsSpan
+		elements []jsExpr
+	}
+	jsParen struct {
+		jsSpan
+		inner jsExpr
+	}
+	jsUnary struct {
+		jsSpan
+		op      string
+		operand jsExpr
+	}
+	jsBinary struct {
+		jsSpan
+		op          string
+		left, right jsExpr
+	}
+	// jsMember is a property access, object.name or object[index].
+	jsMember struct {
+		jsSpan
+		object jsExpr
+		name   string
+		index  jsExpr // nil for object.name
+	}
+	jsCall struct {
+		jsSpan
+		callee jsExpr
+		args   []jsExpr
+		isNew  bool
+	}
+	// jsFunction is a function literal or arrow function, kept as source text.
+	jsFunction struct {
+		jsSpan
+		code string
+	}
+)
+
+// jsNumber is a JavaScript number. It is stored as the smallest BSON number
+// type that holds it exactly.
+type jsNumber float64
+
+// jsBigInt is a JavaScript BigInt. It is kept as an int64, the BSON long it is
+// stored as, so BigInts that do not fit in 64 bits are an error.
+type jsBigInt int64
+
+// mayContainScript reports whether a statement may contain declarations or
+// expressions. It is a quick scan that lets most statements skip readScript:
+// it can report statements that have neither, but never misses one that does.
+func mayContainScript(statement string) bool {
+	depth := 0
+	var prev byte // last significant byte; 'a' after an identifier, '0' after a number
+	for i := 0; i < len(statement); i++ {
+		c := statement[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			continue
+		case c == '"' || c == '\'' || c == '`':
+			i = skipQuoted(statement, i, c)
+		case strings.HasPrefix(statement[i:], "//"):
+			for i < len(statement) && statement[i] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(statement[i:], "/*"):
+			if end := strings.Index(statement[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(statement)
+			}
+			continue
+		case c == '/' && startsValue(prev):
+			i = skipRegex(statement, i)
+		case c == '/' || c == '+' || c == '*' || c == '%' || c == '=':
+			return true
+		case c == '-':
+			// Only a minus sign directly before a number is not an expression.
+			if !startsValue(prev) {
+				return true
+			}
+			j := i + 1
+			for j < len(statement) && (statement[j] == ' ' || statement[j] == '\t') {
+				j++
+			}
+			if j == len(statement) || !isDigit(statement[j]) && statement[j] != '.' {
+				return true
+			}
+		case c == '(' || c == '[' || c == '{':
+			if c == '(' && depth > 0 && prev != 'a' {
+				return true // a parenthesized expression rather than a call
+			}
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == '.' && (i+1 == len(statement) || !isDigit(statement[i+1])):
+			// Property access within arguments, such as Date.now().
+			if depth > 0 {
+				return true
+			}
+		case c == '$' || isIdentStart(c):
+			j := i
+			for j < len(statement) && (statement[j] == '$' || isIdentStart(statement[j]) || isDigit(statement[j])) {
+				j++
+			}
+			switch statement[i:j] {
+			case "const", "let", "var", "new", "function":
+				return true
+			}
+			i, c = j-1, 'a'
+		case isDigit(c) || c == '.':
+			start := i
+			for i+1 < len(statement) && (isDigit(statement[i+1]) || isIdentStart(statement[i+1]) || statement[i+1] == '.' ||
+				(statement[i+1] == '+' || statement[i+1] == '-') && (statement[i] == 'e' || statement[i] == 'E')) {
+				i++
+			}
+			if !parserNumber(statement[start : i+1]) {
+				return true
+			}
+			c = '0'
+		}
+		prev = c
+	}
+	return false
+}
+
+// parserNumber reports whether the parser accepts a number literal. It does not
+// accept octal and binary prefixes, numeric separators or BigInt literals.
+func parserNumber(text string) bool {
+	if len(text) > 1 && text[0] == '0' && strings.ContainsRune("oObB", rune(text[1])) {
+		return false
+	}
+	return !strings.ContainsRune(text, '_') && !strings.HasSuffix(text, "n")
+}
+
+// readScript reads a statement as JavaScript. It returns the statement to parse,
+// with declarations and expressions replaced, and the script to evaluate when
+// binding, or the statement unchanged and nil if it has neither or does not
+// fit the subset. Scripts that fit the subset but are not valid JavaScript,
+// such as a variable declared twice with let, are a *SemanticError.
+func readScript(statement string) (string, *script, error) {
+	tokens, err := tokenizeScript(statement)
+	if err != nil {
+		return statement, nil, nil
+	}
+	p := &jsParser{src: statement, tokens: tokens}
+	s := &script{source: statement, slots: make(map[int]jsExpr)}
+	var blanks []jsSpan
+	var body jsExpr
+	for !p.at(jsEOF) {
+		if p.punct(";") {
+			p.next()
+			continue
+		}
+		tok := p.peek()
+		if tok.kind == jsIdentTok && (tok.text == "const" || tok.text == "let" || tok.text == "var") {
+			decls, end, err := p.parseDeclaration()
+			if err != nil {
+				return statement, nil, nil
+			}
+			for _, d := range decls {
+				if err := s.declare(d); err != nil {
+					return "", nil, err
+				}
+			}
+			blanks = append(blanks, jsSpan{tok.start, end})
+			continue
+		}
+		e, err := p.parseExpr(0)
+		if err != nil {
+			return statement, nil, nil
+		}
+		if body == nil {
+			body = e
+		}
+	}
+	if body != nil {
+		s.collectCallArgs(body)
+	}
+	if len(s.decls) == 0 && len(s.slots) == 0 {
+		return statement, nil, nil
+	}
+
+	buf := []byte(statement)
+	for _, b := range blanks {
+		blank(buf, b)
+	}
+	for start, e := range s.slots {
+		blank(buf, e.span())
+		buf[start] = '$'
+	}
+	return string(buf), s, nil
+}
+
+// blank replaces a span with spaces, keeping line breaks so that line numbers
+// in errors are unchanged.
+func blank(buf []byte, s jsSpan) {
+	for i := s.start; i < s.end; i++ {
+		if buf[i] != '\n' {
+			buf[i] = ' '
+		}
+	}
+}
+
+// declare adds a variable to the script. As in JavaScript, a variable can be
+// declared again with var, but not when either declaration uses const or let.
+func (s *script) declare(d jsDecl) error {
+	for _, prev := range s.decls {
+		if prev.name == d.name && (prev.kind != "var" || d.kind != "var") {
+			return &SemanticError{
+				Message: fmt.Sprintf("SyntaxError: Identifier '%s' has already been declared", d.name),
+				Arg:     -1,
+				Start:   d.start,
+				End:     d.end,
+			}
+		}
+	}
+	s.decls = append(s.decls, d)
+	return nil
+}
+
+// declared reports whether name is a declared variable.
+func (s *script) declared(name string) bool {
+	for _, d := range s.decls {
+		if d.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// collectCallArgs walks a method call chain, such as db.users.find(...).limit(...),
+// collecting the expressions in the arguments of each call.
+func (s *script) collectCallArgs(e jsExpr) {
+	switch n := e.(type) {
+	case *jsCall:
+		s.collectCallArgs(n.callee)
+		for _, arg := range n.args {
+			s.collectValue(arg)
+		}
+	case *jsMember:
+		s.collectCallArgs(n.object)
+	}
+}
+
+// collectValue collects the expressions in a value. Values the parser accepts
+// are left in place; the largest values it does not accept become slots.
+func (s *script) collectValue(e jsExpr) {
+	switch n := e.(type) {
+	case *jsObject:
+		for _, v := range n.values {
+			s.collectValue(v)
+		}
+	case *jsArray:
+		for _, v := range n.elements {
+			s.collectValue(v)
+		}
+	default:
+		if !s.parsable(e) {
+			s.slots[e.span().start] = e
+		}
+	}
+}
+
+// parsable reports whether the parser accepts a value as written.
+func (s *script) parsable(e jsExpr) bool {
+	switch n := e.(type) {
+	case *jsLiteral:
+		return s.parsableLiteral(n)
+	case *jsIdent:
+		return !s.declared(n.name)
+	case *jsUnary:
+		lit, ok := n.operand.(*jsLiteral)
+		if !ok || n.op != "-" {
+			return false
+		}
+		_, ok = lit.value.(jsNumber)
+		return ok && s.parsableLiteral(lit)
+	case *jsObject:
+		for _, v := range n.values {
+			if !s.parsable(v) {
+				return false
+			}
+		}
+		return true
+	case *jsArray:
+		for _, v := range n.elements {
+			if !s.parsable(v) {
+				return false
+			}
+		}
+		return true
+	case *jsCall:
+		callee, ok := n.callee.(*jsIdent)
+		if !ok || n.isNew || s.declared(callee.name) {
+			return false
+		}
+		for _, arg := range n.args {
+			if !s.parsable(arg) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// parsableLiteral reports whether the parser accepts a literal as written.
+func (s *script) parsableLiteral(lit *jsLiteral) bool {
+	switch lit.value.(type) {
+	case jsNumber, jsBigInt:
+		return parserNumber(s.source[lit.start:lit.end])
+	}
+	return true
+}
+
+// jsTokenKind is the kind of a token.
+type jsTokenKind int
+
+const (
+	jsEOF jsTokenKind = iota
+	jsIdentTok
+	jsNumberTok
+	jsStringTok
+	jsRegexTok
+	jsTemplateTok
+	jsPunctTok
+)
+
+type jsToken struct {
+	kind  jsTokenKind
+	text  string // identifier, punctuator or number as written
+	value string // decoded string or regular expression pattern
+	flags string // regular expression flags
+	start int
+	end   int
+}
+
+// jsPunctuators lists the punctuators, longest first. Most are only found in
+// function bodies, which are not parsed but must be tokenized to find their end.
+var jsPunctuators = []string{
+	">>>=", "===", "!==", "**=", "<<=", ">>=", ">>>", "&&=", "||=", "??=", "...",
+	"**", "=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "<<", ">>",
+	"{", "}", "(", ")", "[", "]", ",", ":", ";", ".", "+", "-", "*", "/", "%",
+	"=", "!", "<", ">", "?", "&", "|", "^", "~", "@", "#",
+}
+
+// tokenizeScript splits a statement into tokens.
+func tokenizeScript(src string) ([]jsToken, error) {
+	var tokens []jsToken
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+			continue
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += end + 4
+			continue
+		}
+
+		start := i
+		switch {
+		case c == '$' || isIdentStart(c):
+			for i < len(src) && (src[i] == '$' || isIdentStart(src[i]) || isDigit(src[i])) {
+				i++
+			}
+			tokens = append(tokens, jsToken{kind: jsIdentTok, text: src[start:i], start: start, end: i})
+		case isDigit(c) || c == '.' && i+1 < len(src) && isDigit(src[i+1]):
+			i = scanNumber(src, i)
+			tokens = append(tokens, jsToken{kind: jsNumberTok, text: src[start:i], start: start, end: i})
+		case c == '"' || c == '\'':
+			end := skipQuoted(src, i, c)
+			if end >= len(src) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			value, err := decodeString(src[i+1 : end])
+			if err != nil {
+				return nil, err
+			}
+			i = end + 1
+			tokens = append(tokens, jsToken{kind: jsStringTok, value: value, start: start, end: i})
+		case c == '`':
+			end := skipQuoted(src, i, c)
+			if end >= len(src) {
+				return nil, fmt.Errorf("unterminated template literal")
+			}
+			i = end + 1
+			tokens = append(tokens, jsToken{kind: jsTemplateTok, start: start, end: i})
+		case c == '/' && regexAllowed(tokens):
+			end := skipRegex(src, i)
+			if end >= len(src) || src[end] != '/' {
+				return nil, fmt.Errorf("unterminated regular expression")
+			}
+			i = end + 1
+			for i < len(src) && isIdentStart(src[i]) {
+				i++
+			}
+			tokens = append(tokens, jsToken{
+				kind:  jsRegexTok,
+				value: src[start+1 : end],
+				flags: src[end+1 : i],
+				start: start,
+				end:   i,
+			})
+		default:
+			var punct string
+			for _, p := range jsPunctuators {
+				if strings.HasPrefix(src[i:], p) {
+					punct = p
+					break
+				}
+			}
+			if punct == "" {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+			i += len(punct)
+			tokens = append(tokens, jsToken{kind: jsPunctTok, text: punct, start: start, end: i})
+		}
+	}
+	return append(tokens, jsToken{kind: jsEOF, start: len(src), end: len(src)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// scanNumber returns the end of the number that starts at i.
+func scanNumber(src string, i int) int {
+	if len(src) > i+1 && src[i] == '0' && strings.IndexByte("xXoObB", src[i+1]) >= 0 {
+		i += 2
+		for i < len(src) && strings.IndexByte("0123456789abcdefABCDEF_", src[i]) >= 0 {
+			i++
+		}
+		if i < len(src) && src[i] == 'n' {
+			i++
+		}
+		return i
+	}
+	for i < len(src) && (isDigit(src[i]) || src[i] == '.' || src[i] == '_') {
+		i++
+	}
+	if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+		j := i + 1
+		if j < len(src) && (src[j] == '+' || src[j] == '-') {
+			j++
+		}
+		if j < len(src) && isDigit(src[j]) {
+			i = j
+			for i < len(src) && (isDigit(src[i]) || src[i] == '_') {
+				i++
+			}
+		}
+	} else if i < len(src) && src[i] == 'n' {
+		i++
+	}
+	return i
+}
+
+// regexAllowed reports whether a slash after tokens starts a regular expression
+// rather than a division.
+func regexAllowed(tokens []jsToken) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	last := tokens[len(tokens)-1]
+	switch last.kind {
+	case jsPunctTok:
+		return last.text != ")" && last.text != "]" && last.text != "}"
+	case jsIdentTok:
+		return regexKeywords[last.text]
+	}
+	return false
+}
+
+// regexKeywords are the keywords after which a slash starts a regular expression.
+var regexKeywords = map[string]bool{
+	"return": true, "typeof": true, "case": true, "do": true, "else": true, "in": true,
+	"of": true, "new": true, "delete": true, "void": true, "throw": true, "instanceof": true,
+}
+
+// decodeString decodes the escape sequences of a string literal's contents.
+func decodeString(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return "", fmt.Errorf("invalid escape sequence")
+		}
+		switch c := s[i]; c {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'v':
+			b.WriteByte('\v')
+		case '0':
+			b.WriteByte(0)
+		case '\n':
+		case 'x', 'u':
+			n := 2
+			if c == 'u' {
+				n = 4
+			}
+			if c == 'u' && i+1 < len(s) && s[i+1] == '{' {
+				end := strings.IndexByte(s[i:], '}')
+				if end < 0 {
+					return "", fmt.Errorf("invalid escape sequence")
+				}
+				r, err := strconv.ParseUint(s[i+2:i+end], 16, 32)
+				if err != nil || r > utf8.MaxRune {
+					return "", fmt.Errorf("invalid escape sequence")
+				}
+				b.WriteRune(rune(r))
+				i += end
+				continue
+			}
+			if i+1+n > len(s) {
+				return "", fmt.Errorf("invalid escape sequence")
+			}
+			r, err := strconv.ParseUint(s[i+1:i+1+n], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid escape sequence")
+			}
+			b.WriteRune(rune(r))
+			i += n
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// jsParser parses tokens into expressions.
+type jsParser struct {
+	src    string
+	tokens []jsToken
+	pos    int
+}
+
+func (p *jsParser) peek() jsToken {
+	return p.tokens[p.pos]
+}
+
+func (p *jsParser) next() jsToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != jsEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *jsParser) at(kind jsTokenKind) bool {
+	return p.tokens[p.pos].kind == kind
+}
+
+// punct reports whether the next token is the punctuator text.
+func (p *jsParser) punct(text string) bool {
+	tok := p.tokens[p.pos]
+	return tok.kind == jsPunctTok && tok.text == text
+}
+
+func (p *jsParser) expect(text string) (jsToken, error) {
+	if !p.punct(text) {
+		return jsToken{}, fmt.Errorf("expected %q", text)
+	}
+	return p.next(), nil
+}
+
+// parseDeclaration parses a const, let or var declaration, returning its
+// variables and the end of the declaration, including a closing semicolon.
+func (p *jsParser) parseDeclaration() ([]jsDecl, int, error) {
+	kind := p.next().text
+	var decls []jsDecl
+	for {
+		name := p.next()
+		if name.kind != jsIdentTok {
+			return nil, 0, fmt.Errorf("expected variable name")
+		}
+		if _, err := p.expect("="); err != nil {
+			return nil, 0, err
+		}
+		value, err := p.parseExpr(0)
+		if err != nil {
+			return nil, 0, err
+		}
+		decls = append(decls, jsDecl{jsSpan: jsSpan{name.start, name.end}, kind: kind, name: name.text, value: value})
+		if !p.punct(",") {
+			break
+		}
+		p.next()
+	}
+	end := p.tokens[p.pos-1].end
+	if p.punct(";") {
+		end = p.next().end
+	}
+	return decls, end, nil
+}
+
+// jsBinaryPrecedence is the precedence of each binary operator.
+var jsBinaryPrecedence = map[string]int{
+	"+": 1, "-": 1,
+	"*": 2, "/": 2, "%": 2,
+	"**": 3,
+}
+
+// parseExpr parses an expression whose binary operators bind at least as
+// tightly as minPrecedence.
+func (p *jsParser) parseExpr(minPrecedence int) (jsExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		prec := jsBinaryPrecedence[tok.text]
+		if tok.kind != jsPunctTok || prec == 0 || prec < minPrecedence {
+			return left, nil
+		}
+		p.next()
+		next := prec + 1
+		if tok.text == "**" {
+			next = prec // right-associative
+		}
+		right, err := p.parseExpr(next)
+		if err != nil {
+			return nil, err
+		}
+		left = &jsBinary{
+			jsSpan: jsSpan{left.span().start, right.span().end},
+			op:     tok.text,
+			left:   left,
+			right:  right,
+		}
+	}
+}
+
+func (p *jsParser) parseUnary() (jsExpr, error) {
+	if p.punct("-") || p.punct("+") {
+		op := p.next()
+		if next := p.peek(); op.text == "-" && next.kind == jsNumberTok && strings.HasSuffix(next.text, "n") {
+			// A negative BigInt literal is read whole, so that the lowest
+			// int64, whose magnitude does not fit, is accepted.
+			p.next()
+			return p.bigIntLiteral(jsSpan{op.start, next.end}, "-"+next.text)
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &jsUnary{jsSpan: jsSpan{op.start, operand.span().end}, op: op.text, operand: operand}, nil
+	}
+	e, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return p.parsePostfix(e, true)
+}
+
+// parsePostfix parses the property accesses, and the calls if calls is true,
+// that follow an expression.
+func (p *jsParser) parsePostfix(e jsExpr, calls bool) (jsExpr, error) {
+	for {
+		switch {
+		case p.punct("."):
+			p.next()
+			name := p.next()
+			if name.kind != jsIdentTok {
+				return nil, fmt.Errorf("expected property name")
+			}
+			e = &jsMember{jsSpan: jsSpan{e.span().start, name.end}, object: e, name: name.text}
+		case p.punct("["):
+			p.next()
+			index, err := p.parseExpr(0)
+			if err != nil {
+				return nil, err
+			}
+			end, err := p.expect("]")
+			if err != nil {
+				return nil, err
+			}
+			e = &jsMember{jsSpan: jsSpan{e.span().start, end.end}, object: e, index: index}
+		case p.punct("(") && calls:
+			args, end, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			e = &jsCall{jsSpan: jsSpan{e.span().start, end}, callee: e, args: args}
+		default:
+			return e, nil
+		}
+	}
+}
+
+// parseArgs parses a parenthesized argument list, returning its end.
+func (p *jsParser) parseArgs() ([]jsExpr, int, error) {
+	p.next()
+	var args []jsExpr
+	for !p.punct(")") {
+		arg, err := p.parseExpr(0)
+		if err != nil {
+			return nil, 0, err
+		}
+		args = append(args, arg)
+		if !p.punct(",") {
+			break
+		}
+		p.next()
+	}
+	end, err := p.expect(")")
+	if err != nil {
+		return nil, 0, err
+	}
+	return args, end.end, nil
+}
+
+func (p *jsParser) parsePrimary() (jsExpr, error) {
+	tok := p.next()
+	span := jsSpan{tok.start, tok.end}
+	switch tok.kind {
+	case jsNumberTok:
+		if strings.HasSuffix(tok.text, "n") {
+			return p.bigIntLiteral(span, tok.text)
+		}
+		n, err := parseNumber(tok.text)
+		if err != nil {
+			return nil, err
+		}
+		return &jsLiteral{jsSpan: span, value: toNumber(n)}, nil
+	case jsStringTok:
+		return &jsLiteral{jsSpan: span, value: tok.value}, nil
+	case jsRegexTok:
+		return &jsLiteral{jsSpan: span, value: bson.Regex{Pattern: tok.value, Options: tok.flags}}, nil
+	case jsIdentTok:
+		switch tok.text {
+		case "true", "false":
+			return &jsLiteral{jsSpan: span, value: tok.text == "true"}, nil
+		case "null":
+			return &jsLiteral{jsSpan: span}, nil
+		case "new":
+			return p.parseNew(tok)
+		case "function":
+			return p.parseFunction(tok)
+		case "const", "let", "var", "return", "if", "for", "while", "this", "typeof", "delete":
+			return nil, fmt.Errorf("%s is not supported", tok.text)
+		}
+		if p.punct("=>") {
+			return p.parseArrowBody(tok)
+		}
+		return &jsIdent{jsSpan: span, name: tok.text}, nil
+	case jsPunctTok:
+		switch tok.text {
+		case "(":
+			if p.isArrowParams() {
+				return p.parseArrowBody(tok)
+			}
+			inner, err := p.parseExpr(0)
+			if err != nil {
+				return nil, err
+			}
+			end, err := p.expect(")")
+			if err != nil {
+				return nil, err
+			}
+			return &jsParen{jsSpan: jsSpan{tok.start, end.end}, inner: inner}, nil
+		case "{":
+			return p.parseObject(tok)
+		case "[":
+			return p.parseArray(tok)
+		}
+	}
+	return nil, fmt.Errorf("unexpected token at offset %d", tok.start)
+}
+
+// bigIntLiteral parses a BigInt literal. One that is valid JavaScript but does
+// not fit in an int64 is a *jsInvalid.
+func (p *jsParser) bigIntLiteral(span jsSpan, text string) (jsExpr, error) {
+	n, err := parseNumber(text)
+	if errors.Is(err, errNumberRange) {
+		return &jsInvalid{jsSpan: span, err: err}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &jsLiteral{jsSpan: span, value: jsBigInt(n.(int64))}, nil
+}
+
+// parseFunction parses a function literal, keeping its source text. The body
+// is only tokenized to find where the function ends.
+func (p *jsParser) parseFunction(tok jsToken) (jsExpr, error) {
+	if p.at(jsIdentTok) {
+		p.next() // function name
+	}
+	if !p.punct("(") {
+		return nil, fmt.Errorf("expected \"(\"")
+	}
+	if _, err := p.skipBalanced(); err != nil {
+		return nil, err
+	}
+	if !p.punct("{") {
+		return nil, fmt.Errorf("expected \"{\"")
+	}
+	end, err := p.skipBalanced()
+	if err != nil {
+		return nil, err
+	}
+	return &jsFunction{jsSpan: jsSpan{tok.start, end}, code: p.src[tok.start:end]}, nil
+}
+
+// isArrowParams reports whether the tokens after an opening parenthesis are
+// the parameters of an arrow function.
+func (p *jsParser) isArrowParams() bool {
+	depth := 1
+	for i := p.pos; i < len(p.tokens); i++ {
+		tok := p.tokens[i]
+		if tok.kind == jsEOF {
+			return false
+		}
+		if tok.kind != jsPunctTok {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				next := p.tokens[i+1]
+				return next.kind == jsPunctTok && next.text == "=>"
+			}
+		}
+	}
+	return false
+}
+
+// parseArrowBody parses the rest of an arrow function that starts at tok,
+// keeping its source text. An expression body ends at the first comma or
+// closing bracket outside any brackets.
+func (p *jsParser) parseArrowBody(tok jsToken) (jsExpr, error) {
+	for !p.punct("=>") {
+		p.next()
+	}
+	p.next()
+	end := 0
+	if p.punct("{") {
+		var err error
+		if end, err = p.skipBalanced(); err != nil {
+			return nil, err
+		}
+	} else {
+		depth := 0
+	body:
+		for !p.at(jsEOF) {
+			next := p.peek()
+			if next.kind == jsPunctTok {
+				switch next.text {
+				case "(", "[", "{":
+					depth++
+				case ")", "]", "}":
+					if depth == 0 {
+						break body
+					}
+					depth--
+				case ",", ";":
+					if depth == 0 {
+						break body
+					}
+				}
+			}
+			end = p.next().end
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("expected arrow function body")
+		}
+	}
+	return &jsFunction{jsSpan: jsSpan{tok.start, end}, code: p.src[tok.start:end]}, nil
+}
+
+// skipBalanced skips the tokens from an opening bracket to the bracket that
+// closes it, returning the end of the closing bracket.
+func (p *jsParser) skipBalanced() (int, error) {
+	depth := 0
+	for !p.at(jsEOF) {
+		tok := p.next()
+		if tok.kind != jsPunctTok {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return tok.end, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unterminated function")
+}
+
+// parseNew parses new Callee(args).
+func (p *jsParser) parseNew(tok jsToken) (jsExpr, error) {
+	callee, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if callee, err = p.parsePostfix(callee, false); err != nil {
+		return nil, err
+	}
+	call := &jsCall{jsSpan: jsSpan{tok.start, callee.span().end}, callee: callee, isNew: true}
+	if p.punct("(") {
+		args, end, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+		call.args = args
+		call.end = end
+	}
+	return p.parsePostfix(call, true)
+}
+
+func (p *jsParser) parseObject(open jsToken) (jsExpr, error) {
+	obj := &jsObject{}
+	for !p.punct("}") {
+		key := p.next()
+		switch key.kind {
+		case jsIdentTok, jsNumberTok:
+			obj.keys = append(obj.keys, key.text)
+		case jsStringTok:
+			obj.keys = append(obj.keys, key.value)
+		default:
+			return nil, fmt.Errorf("expected property name")
+		}
+		if _, err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		obj.values = append(obj.values, value)
+		if !p.punct(",") {
+			break
+		}
+		p.next()
+	}
+	end, err := p.expect("}")
+	if err != nil {
+		return nil, err
+	}
+	obj.jsSpan = jsSpan{open.start, end.end}
+	return obj, nil
+}
+
+func (p *jsParser) parseArray(open jsToken) (jsExpr, error) {
+	arr := &jsArray{}
+	for !p.punct("]") {
+		elem, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		arr.elements = append(arr.elements, elem)
+		if !p.punct(",") {
+			break
+		}
+		p.next()
+	}
+	end, err := p.expect("]")
+	if err != nil {
+		return nil, err
+	}
+	arr.jsSpan = jsSpan{open.start, end.end}
+	return arr, nil
+}
+
+// parseJSNumber parses a string converted to a number, as Number() does: a
+// decimal or hex number.
+func parseJSNumber(text string) (jsNumber, error) {
+	if len(text) > 2 && (text[1] == 'x' || text[1] == 'X') {
+		n, err := strconv.ParseUint(text[2:], 16, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %s", text)
+		}
+		return jsNumber(n), nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %s", text)
+	}
+	return jsNumber(f), nil
+}
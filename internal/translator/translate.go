@@ -2,6 +2,7 @@ package translator
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/bytebase/gomongo/types"
@@ -9,6 +10,14 @@ import (
 )
 
 func translateNode(node ast.Node) (*Operation, error) {
+	op, err := translateStatement(node)
+	if err != nil {
+		return nil, locateError(node, err)
+	}
+	return op, nil
+}
+
+func translateStatement(node ast.Node) (*Operation, error) {
 	op := &Operation{OpType: types.OpUnknown}
 	switch n := node.(type) {
 	case *ast.CollectionStatement:
@@ -17,17 +26,68 @@ func translateNode(node ast.Node) (*Operation, error) {
 		return translateDatabaseStatement(op, n)
 	case *ast.ShowCommand:
 		return translateShowCommand(op, n)
+	case *showLogCommand:
+		op.OpType = types.OpShowLog
+		op.LogName = n.name
+		return op, nil
+	case *ast.RsStatement:
+		return translateRsStatement(op, n)
+	case *ast.ShStatement:
+		return translateShStatement(op, n)
 	default:
 		return nil, &UnsupportedOperationError{Operation: fmt.Sprintf("%T", node)}
 	}
 }
 
+// defaultLogName is the log shown by a bare "show log", as in mongosh.
+const defaultLogName = "global"
+
+// showLogPattern matches the name in "show log <name>" at the start of a
+// statement. The name must be an identifier, as log names are, and end the
+// statement.
+var showLogPattern = regexp.MustCompile(`^(\s*show\s+log\s+)([A-Za-z_][A-Za-z0-9_]*)\s*(?:;|//|/\*|$)`)
+
+// showLogCommand is "show log <name>". The parser only recognizes the bare
+// "show log", so readShowLog takes the name out of the statement before
+// parsing and it is put back on the parsed command.
+type showLogCommand struct {
+	*ast.ShowCommand
+	name string
+}
+
+// readShowLog returns the statement with the name of a "show log <name>"
+// command replaced by spaces, keeping every byte offset unchanged, and the
+// location of the name. It returns the statement unchanged and a zero
+// location if the statement is not of that form.
+func readShowLog(statement string) (string, ast.Loc) {
+	m := showLogPattern.FindStringSubmatchIndex(statement)
+	if m == nil {
+		return statement, ast.Loc{}
+	}
+	name := ast.Loc{Start: m[4], End: m[5]}
+	return statement[:name.Start] + strings.Repeat(" ", name.End-name.Start) + statement[name.End:], name
+}
+
 func translateShowCommand(op *Operation, cmd *ast.ShowCommand) (*Operation, error) {
 	switch cmd.Target {
 	case "dbs", "databases":
 		op.OpType = types.OpShowDatabases
 	case "collections", "tables":
 		op.OpType = types.OpShowCollections
+	case "profile":
+		op.OpType = types.OpShowProfile
+	case "users":
+		op.OpType = types.OpShowUsers
+	case "roles":
+		op.OpType = types.OpShowRoles
+	case "log":
+		op.OpType = types.OpShowLog
+		op.LogName = defaultLogName
+	case "startupWarnings":
+		op.OpType = types.OpShowLog
+		op.LogName = "startupWarnings"
+	case "logs":
+		op.OpType = types.OpShowLogs
 	default:
 		return nil, &UnsupportedOperationError{Operation: "show " + cmd.Target}
 	}
@@ -58,6 +118,55 @@ func translateDatabaseStatement(op *Operation, stmt *ast.DatabaseStatement) (*Op
 		op.OpType = types.OpHostInfo
 	case "listCommands":
 		op.OpType = types.OpListCommands
+	case "currentOp":
+		op.OpType = types.OpCurrentOp
+		return extractCurrentOpArgs(op, stmt.Args)
+	case "killOp":
+		op.OpType = types.OpKillOp
+		return extractKillOpArgs(op, stmt.Args)
+	case "setProfilingLevel":
+		op.OpType = types.OpSetProfilingLevel
+		return extractSetProfilingLevelArgs(op, stmt.Args)
+	case "getProfilingLevel":
+		op.OpType = types.OpGetProfilingLevel
+	case "getProfilingStatus":
+		op.OpType = types.OpGetProfilingStatus
+	case "getUsers":
+		op.OpType = types.OpGetUsers
+		return extractGetUsersArgs(op, stmt.Args)
+	case "getUser":
+		op.OpType = types.OpGetUser
+		return extractGetUserArgs(op, stmt.Args)
+	case "getRoles":
+		op.OpType = types.OpGetRoles
+		return extractGetRolesArgs(op, stmt.Args)
+	case "getRole":
+		op.OpType = types.OpGetRole
+		return extractGetRoleArgs(op, stmt.Args)
+	case "createUser":
+		op.OpType = types.OpCreateUser
+		return extractCreateUserArgs(op, stmt.Args)
+	case "updateUser":
+		op.OpType = types.OpUpdateUser
+		return extractUpdateUserArgs(op, stmt.Args)
+	case "dropUser":
+		op.OpType = types.OpDropUser
+		return extractDropUserArgs(op, stmt.Args)
+	case "grantRolesToUser":
+		op.OpType = types.OpGrantRolesToUser
+		return extractUserRolesArgs(op, "grantRolesToUser", stmt.Args)
+	case "revokeRolesFromUser":
+		op.OpType = types.OpRevokeRolesFromUser
+		return extractUserRolesArgs(op, "revokeRolesFromUser", stmt.Args)
+	case "createRole":
+		op.OpType = types.OpCreateRole
+		return extractCreateRoleArgs(op, stmt.Args)
+	case "grantPrivilegesToRole":
+		op.OpType = types.OpGrantPrivilegesToRole
+		return extractGrantPrivilegesToRoleArgs(op, stmt.Args)
+	case "changeUserPassword":
+		op.OpType = types.OpChangeUserPassword
+		return extractChangeUserPasswordArgs(op, stmt.Args)
 	default:
 		return nil, &UnsupportedOperationError{Operation: stmt.Method + "()"}
 	}
@@ -202,6 +311,8 @@ func translateCollectionStatement(op *Operation, stmt *ast.CollectionStatement)
 		op.OpType = types.OpValidate
 	case "latencyStats":
 		op.OpType = types.OpLatencyStats
+	case "getShardDistribution":
+		op.OpType = types.OpGetShardDistribution
 
 	default:
 		methodName := extractMethodName(stmt.Method)
@@ -213,7 +324,7 @@ func translateCollectionStatement(op *Operation, stmt *ast.CollectionStatement)
 
 	// Process cursor methods.
 	for _, cm := range stmt.CursorMethods {
-		if err := translateCursorMethod(op, cm); err != nil {
+		if err := translateCursorMethod(op, stmt.Method, cm); err != nil {
 			return nil, err
 		}
 	}
@@ -221,27 +332,72 @@ func translateCollectionStatement(op *Operation, stmt *ast.CollectionStatement)
 	return op, nil
 }
 
-func translateCursorMethod(op *Operation, cm ast.CursorMethod) error {
+// translateRsStatement translates the read-only rs.* replica set helpers.
+// Replica set reconfiguration is not supported.
+func translateRsStatement(op *Operation, stmt *ast.RsStatement) (*Operation, error) {
+	switch stmt.MethodName {
+	case "status":
+		op.OpType = types.OpRsStatus
+	case "conf", "config":
+		op.OpType = types.OpRsConf
+	case "printReplicationInfo":
+		op.OpType = types.OpRsPrintReplicationInfo
+	case "printSecondaryReplicationInfo", "printSlaveReplicationInfo":
+		op.OpType = types.OpRsPrintSecondaryReplicationInfo
+	default:
+		return nil, &UnsupportedOperationError{Operation: "rs." + stmt.MethodName + "()"}
+	}
+	if len(stmt.Args) > 0 {
+		return nil, errorAt(stmt.Args[0], "rs.%s() takes no arguments", stmt.MethodName)
+	}
+	return op, nil
+}
+
+// translateShStatement translates the read-only sh.* sharding helpers.
+// Cluster administration is not supported.
+func translateShStatement(op *Operation, stmt *ast.ShStatement) (*Operation, error) {
+	switch stmt.MethodName {
+	case "status":
+		op.OpType = types.OpShStatus
+	default:
+		return nil, &UnsupportedOperationError{Operation: "sh." + stmt.MethodName + "()"}
+	}
+	if len(stmt.Args) > 0 {
+		return nil, errorAt(stmt.Args[0], "sh.%s() takes no arguments", stmt.MethodName)
+	}
+	return op, nil
+}
+
+func translateCursorMethod(op *Operation, method string, cm ast.CursorMethod) error {
+	var err error
 	switch cm.Method {
 	case "sort":
-		return extractSort(op, cm.Args)
+		err = extractSort(op, cm.Args)
 	case "limit":
-		return extractLimit(op, cm.Args)
+		err = extractLimit(op, cm.Args)
 	case "skip":
-		return extractSkip(op, cm.Args)
+		err = extractSkip(op, cm.Args)
 	case "projection":
-		return extractProjection(op, cm.Args)
+		err = extractProjection(op, cm.Args)
 	case "hint":
-		return extractCursorHint(op, cm.Args)
+		err = extractCursorHint(op, cm.Args)
 	case "max":
-		return extractMax(op, cm.Args)
+		err = extractMax(op, cm.Args)
 	case "min":
-		return extractMin(op, cm.Args)
+		err = extractMin(op, cm.Args)
 	case "pretty":
-		return nil // no-op
+		op.warn(types.WarningIgnoredMethod, cm.Loc.Start, "pretty() only formats mongosh output and was ignored")
+		return nil
 	default:
 		return &UnsupportedOperationError{Operation: cm.Method + "()"}
 	}
+	if err != nil {
+		return locate(cm.Loc, err)
+	}
+	if !cursorMethodHonored(op.OpType, cm.Method) {
+		op.warn(types.WarningIgnoredMethod, cm.Loc.Start, "%s() has no effect on %s() and was ignored", cm.Method, method)
+	}
+	return nil
 }
 
 // extractMethodName extracts the method name before any parenthesis.
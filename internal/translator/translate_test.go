@@ -0,0 +1,36 @@
+package translator_test
+
+import (
+	"testing"
+
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/bytebase/gomongo/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowLog(t *testing.T) {
+	tests := []struct {
+		statement string
+		name      string
+	}{
+		{"show log", "global"},
+		{"show log global", "global"},
+		{"show log global;", "global"},
+		{" show\n  log\tstartupWarnings ; ", "startupWarnings"},
+		{"show log global // comment", "global"},
+		{"show startupWarnings", "startupWarnings"},
+	}
+	for _, tc := range tests {
+		op, err := translator.Parse(tc.statement)
+		require.NoError(t, err, tc.statement)
+		require.Equal(t, types.OpShowLog, op.OpType, tc.statement)
+		require.Equal(t, tc.name, op.LogName, tc.statement)
+	}
+
+	// A name followed by anything but the end of the statement is a parse error.
+	_, err := translator.Parse("show log global extra")
+	var parseErr *translator.ParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, 1, parseErr.Line)
+	require.Equal(t, 10, parseErr.Column)
+}
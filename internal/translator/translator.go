@@ -3,21 +3,79 @@ package translator
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/bytebase/omni/mongo"
+	"github.com/bytebase/omni/mongo/ast"
 	"github.com/bytebase/omni/mongo/parser"
 )
 
 // Parse parses a MongoDB shell statement and returns the operation.
 func Parse(statement string) (*Operation, error) {
-	stmts, err := mongo.Parse(statement)
+	return ParseWithParams(statement, nil)
+}
+
+// ParseWithParams parses a MongoDB shell statement, binding its $1 and :name
+// placeholders to params, and returns the operation.
+func ParseWithParams(statement string, params *Params) (*Operation, error) {
+	stmt, err := Prepare(statement)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Bind(params)
+}
+
+// Options configures how a statement is read.
+type Options struct {
+	// ExtendedJSON converts Extended JSON v2 wrapper documents, such as
+	// {"$oid": "..."}, to the values they represent. Without it they are kept
+	// as documents, as mongosh keeps them.
+	ExtendedJSON bool
+}
+
+// Statement is a parsed MongoDB shell statement that can be bound and
+// translated any number of times, concurrently.
+type Statement struct {
+	node   ast.Node     // parsed statement; never modified
+	named  map[int]bool // offsets of the :name placeholders
+	script *script      // expressions and variables, or nil
+	opts   Options
+	op     *Operation // translated operation if the statement is static
+}
+
+// Prepare parses a MongoDB shell statement. A statement without placeholders or
+// generated values, such as ObjectId(), is translated once here rather than on
+// every Bind.
+func Prepare(statement string) (*Statement, error) {
+	return PrepareWithOptions(statement, Options{})
+}
+
+// PrepareWithOptions parses a MongoDB shell statement, reading it as opts
+// configures. See Prepare.
+func PrepareWithOptions(statement string, opts Options) (*Statement, error) {
+	source, named := rewriteNamedParams(statement)
+	source, logName := readShowLog(source)
+	var js *script
+	if mayContainScript(source) {
+		var err error
+		if source, js, err = readScript(source); err != nil {
+			return nil, err
+		}
+	}
+	stmts, err := mongo.Parse(source)
 	if err != nil {
 		var pe *parser.ParseError
 		if errors.As(err, &pe) {
+			message := redactParseMessage(statement, pe.Message)
+			found, expected := parseMessageTokens(message)
 			return nil, &ParseError{
-				Line:    pe.Line,
-				Column:  pe.Column,
-				Message: pe.Message,
+				Line:     pe.Line,
+				Column:   pe.Column,
+				Message:  message,
+				Found:    found,
+				Expected: expected,
 			}
 		}
 		return nil, err
@@ -25,10 +83,82 @@ func Parse(statement string) (*Operation, error) {
 
 	// Find the first non-empty statement.
 	for _, s := range stmts {
-		if !s.Empty() {
-			return translateNode(s.AST)
+		if s.Empty() {
+			continue
+		}
+		node := s.AST
+		if cmd, ok := node.(*ast.ShowCommand); ok && cmd.Target == "log" && logName.End > 0 {
+			cmd.Loc.End = logName.End
+			node = &showLogCommand{ShowCommand: cmd, name: statement[logName.Start:logName.End]}
+		}
+		stmt := &Statement{node: node, named: named, script: js, opts: opts}
+		if !isStatic(node, named, js) {
+			return stmt, nil
+		}
+		if stmt.op, err = stmt.Bind(nil); err != nil {
+			return nil, err
 		}
+		return stmt, nil
 	}
 
 	return nil, &ParseError{Message: fmt.Sprintf("empty statement: %s", statement)}
 }
+
+// Bind translates the statement with its placeholders bound to params. Values
+// not used by any placeholder are ignored.
+func (s *Statement) Bind(params *Params) (*Operation, error) {
+	if s.op != nil {
+		op := *s.op
+		return &op, nil
+	}
+	b := &binder{params: params, named: s.named, script: s.script, extendedJSON: s.opts.ExtendedJSON}
+	node, err := b.bindStatement(s.node)
+	if err != nil {
+		return nil, locateError(s.node, err)
+	}
+	return translateNode(node)
+}
+
+// credentialMethods are methods whose arguments may carry a password.
+var credentialMethods = []string{"createUser", "updateUser", "changeUserPassword"}
+
+// quotedText matches the quoted source text that parse error messages echo back.
+var quotedText = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+
+// redactParseMessage removes quoted source text from a parse error message when the
+// statement may contain a password, so the password is never echoed in an error.
+func redactParseMessage(statement, message string) string {
+	for _, method := range credentialMethods {
+		if strings.Contains(statement, method) {
+			return quotedText.ReplaceAllString(message, `"***"`)
+		}
+	}
+	return message
+}
+
+var (
+	// unexpectedToken matches the parser's message for a token other than the
+	// one expected, such as `expected :, got "}"`.
+	unexpectedToken = regexp.MustCompile(`^expected (.+), got ("(?:[^"\\]|\\.)*")$`)
+	// unexpectedEnd matches the parser's message for a statement that ends early.
+	unexpectedEnd = regexp.MustCompile(`^expected (.+) but reached end of input$`)
+	// syntaxError matches the parser's message for a token that cannot start
+	// or continue the statement.
+	syntaxError = regexp.MustCompile(`^syntax error at or near ("(?:[^"\\]|\\.)*")$`)
+)
+
+// parseMessageTokens returns the token found and the token expected that a
+// parse error message names. The parser only reports them in the message.
+func parseMessageTokens(message string) (found, expected string) {
+	if m := unexpectedToken.FindStringSubmatch(message); m != nil {
+		found, _ = strconv.Unquote(m[2])
+		return found, m[1]
+	}
+	if m := unexpectedEnd.FindStringSubmatch(message); m != nil {
+		return "", m[1]
+	}
+	if m := syntaxError.FindStringSubmatch(message); m != nil {
+		found, _ = strconv.Unquote(m[1])
+	}
+	return found, ""
+}
@@ -63,4 +63,34 @@ type Operation struct {
 	ValidationLevel  string // createCollection validationLevel option
 	ValidationAction string // createCollection validationAction option
 	Validator        bson.D // createCollection validator option
+
+	// Operations console fields
+	OpID any // killOp operation id (number, or "shard:opid" string on mongos)
+
+	// Profiler fields
+	ProfilingLevel *int32   // setProfilingLevel level (0, 1 or 2)
+	SlowMS         *int32   // setProfilingLevel slowms option
+	SampleRate     *float64 // setProfilingLevel sampleRate option
+	ProfileFilter  bson.D   // setProfilingLevel filter option
+
+	// show log target
+	LogName string // getLog log name (e.g. "global", "startupWarnings")
+
+	// User and role introspection fields
+	UserName                       string // getUser user name
+	RoleName                       string // getRole role name
+	ShowPrivileges                 *bool  // getUser(s)/getRole(s) showPrivileges option
+	ShowBuiltinRoles               *bool  // getRole(s) showBuiltinRoles option
+	ShowAuthenticationRestrictions *bool  // getUser(s)/getRole(s) showAuthenticationRestrictions option
+	ShowCustomData                 *bool  // getUser(s) showCustomData option
+
+	// User and role administration fields
+	UserSpec   bson.D // createUser/updateUser specification (without the user name)
+	RoleSpec   bson.D // createRole specification (without the role name)
+	Roles      bson.A // grantRolesToUser/revokeRolesFromUser roles
+	Privileges bson.A // grantPrivilegesToRole privileges
+	Password   string // changeUserPassword new password; never include in errors
+
+	// Parts of the statement that were not translated as written
+	Warnings []Warning
 }
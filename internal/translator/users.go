@@ -0,0 +1,373 @@
+package translator
+
+import (
+	"fmt"
+
+	"github.com/bytebase/omni/mongo/ast"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func extractGetUsersArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) == 0 {
+		return op, nil
+	}
+
+	options, err := requireDocument(args, 0, "getUsers() options")
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range options {
+		switch opt.Key {
+		case "filter":
+			if doc, ok := opt.Value.(bson.D); ok {
+				op.Filter = doc
+			} else {
+				return nil, optionError(opt.Key, "getUsers() filter must be a document")
+			}
+		case "showPrivileges":
+			if err := setBoolOption(&op.ShowPrivileges, "getUsers()", opt); err != nil {
+				return nil, err
+			}
+		case "showAuthenticationRestrictions":
+			if err := setBoolOption(&op.ShowAuthenticationRestrictions, "getUsers()", opt); err != nil {
+				return nil, err
+			}
+		case "showCustomData":
+			if err := setBoolOption(&op.ShowCustomData, "getUsers()", opt); err != nil {
+				return nil, err
+			}
+		default:
+			// showCredentials is deliberately unsupported: credentials are never returned.
+			return nil, &UnsupportedOptionError{
+				Method: "getUsers()",
+				Option: opt.Key,
+			}
+		}
+	}
+
+	if len(args) > 1 {
+		return nil, errorAt(args[1], "getUsers() takes at most 1 argument")
+	}
+	return op, nil
+}
+
+func extractGetUserArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("getUser() requires a user name")
+	}
+
+	name, err := requireString(args, 0, "getUser() user name")
+	if err != nil {
+		return nil, err
+	}
+	op.UserName = name
+
+	if len(args) >= 2 {
+		options, err := requireDocument(args, 1, "getUser() options")
+		if err != nil {
+			return nil, err
+		}
+		for _, opt := range options {
+			switch opt.Key {
+			case "showPrivileges":
+				if err := setBoolOption(&op.ShowPrivileges, "getUser()", opt); err != nil {
+					return nil, err
+				}
+			case "showAuthenticationRestrictions":
+				if err := setBoolOption(&op.ShowAuthenticationRestrictions, "getUser()", opt); err != nil {
+					return nil, err
+				}
+			case "showCustomData":
+				if err := setBoolOption(&op.ShowCustomData, "getUser()", opt); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, &UnsupportedOptionError{
+					Method: "getUser()",
+					Option: opt.Key,
+				}
+			}
+		}
+	}
+
+	if len(args) > 2 {
+		return nil, errorAt(args[2], "getUser() takes at most 2 arguments")
+	}
+	return op, nil
+}
+
+func extractGetRolesArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) == 0 {
+		return op, nil
+	}
+
+	options, err := requireDocument(args, 0, "getRoles() options")
+	if err != nil {
+		return nil, err
+	}
+	if err := extractRoleOptions(op, "getRoles()", options); err != nil {
+		return nil, err
+	}
+
+	if len(args) > 1 {
+		return nil, errorAt(args[1], "getRoles() takes at most 1 argument")
+	}
+	return op, nil
+}
+
+func extractGetRoleArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("getRole() requires a role name")
+	}
+
+	name, err := requireString(args, 0, "getRole() role name")
+	if err != nil {
+		return nil, err
+	}
+	op.RoleName = name
+
+	if len(args) >= 2 {
+		options, err := requireDocument(args, 1, "getRole() options")
+		if err != nil {
+			return nil, err
+		}
+		if err := extractRoleOptions(op, "getRole()", options); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(args) > 2 {
+		return nil, errorAt(args[2], "getRole() takes at most 2 arguments")
+	}
+	return op, nil
+}
+
+// extractRoleOptions extracts supported options for getRole/getRoles.
+func extractRoleOptions(op *Operation, method string, options bson.D) error {
+	for _, opt := range options {
+		switch opt.Key {
+		case "showPrivileges":
+			if err := setBoolOption(&op.ShowPrivileges, method, opt); err != nil {
+				return err
+			}
+		case "showBuiltinRoles":
+			if err := setBoolOption(&op.ShowBuiltinRoles, method, opt); err != nil {
+				return err
+			}
+		case "showAuthenticationRestrictions":
+			if err := setBoolOption(&op.ShowAuthenticationRestrictions, method, opt); err != nil {
+				return err
+			}
+		default:
+			return &UnsupportedOptionError{
+				Method: method,
+				Option: opt.Key,
+			}
+		}
+	}
+	return nil
+}
+
+// setBoolOption stores a boolean option value, or returns an error naming the method and option.
+func setBoolOption(dst **bool, method string, opt bson.E) error {
+	val, ok := opt.Value.(bool)
+	if !ok {
+		return optionError(opt.Key, "%s %s must be a boolean", method, opt.Key)
+	}
+	*dst = &val
+	return nil
+}
+
+func extractCreateUserArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("createUser() requires a user document")
+	}
+
+	spec, err := requireDocument(args, 0, "createUser() user document")
+	if err != nil {
+		return nil, err
+	}
+	name, rest, err := splitNamedSpec(spec, "user", "createUser()")
+	if err != nil {
+		return nil, err
+	}
+	op.UserName = name
+	op.UserSpec = rest
+
+	if err := extractWriteConcernArg(op, "createUser()", args, 1); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func extractUpdateUserArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("updateUser() requires a user name and an update document")
+	}
+
+	name, err := requireString(args, 0, "updateUser() user name")
+	if err != nil {
+		return nil, err
+	}
+	op.UserName = name
+
+	update, err := requireDocument(args, 1, "updateUser() update document")
+	if err != nil {
+		return nil, err
+	}
+	op.UserSpec = update
+
+	if err := extractWriteConcernArg(op, "updateUser()", args, 2); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func extractDropUserArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("dropUser() requires a user name")
+	}
+
+	name, err := requireString(args, 0, "dropUser() user name")
+	if err != nil {
+		return nil, err
+	}
+	op.UserName = name
+
+	if err := extractWriteConcernArg(op, "dropUser()", args, 1); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func extractUserRolesArgs(op *Operation, methodName string, args []ast.Node) (*Operation, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("%s() requires a user name and an array of roles", methodName)
+	}
+
+	name, err := requireString(args, 0, fmt.Sprintf("%s() user name", methodName))
+	if err != nil {
+		return nil, err
+	}
+	op.UserName = name
+
+	roles, err := requireArray(args, 1, fmt.Sprintf("%s() roles", methodName))
+	if err != nil {
+		return nil, err
+	}
+	op.Roles = roles
+
+	if err := extractWriteConcernArg(op, methodName+"()", args, 2); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func extractCreateRoleArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("createRole() requires a role document")
+	}
+
+	spec, err := requireDocument(args, 0, "createRole() role document")
+	if err != nil {
+		return nil, err
+	}
+	name, rest, err := splitNamedSpec(spec, "role", "createRole()")
+	if err != nil {
+		return nil, err
+	}
+	op.RoleName = name
+	op.RoleSpec = rest
+
+	if err := extractWriteConcernArg(op, "createRole()", args, 1); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func extractGrantPrivilegesToRoleArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("grantPrivilegesToRole() requires a role name and an array of privileges")
+	}
+
+	name, err := requireString(args, 0, "grantPrivilegesToRole() role name")
+	if err != nil {
+		return nil, err
+	}
+	op.RoleName = name
+
+	privileges, err := requireArray(args, 1, "grantPrivilegesToRole() privileges")
+	if err != nil {
+		return nil, err
+	}
+	op.Privileges = privileges
+
+	if err := extractWriteConcernArg(op, "grantPrivilegesToRole()", args, 2); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func extractChangeUserPasswordArgs(op *Operation, args []ast.Node) (*Operation, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("changeUserPassword() requires a user name and a password")
+	}
+
+	name, err := requireString(args, 0, "changeUserPassword() user name")
+	if err != nil {
+		return nil, err
+	}
+	op.UserName = name
+
+	password, err := requireString(args, 1, "changeUserPassword() password")
+	if err != nil {
+		return nil, err
+	}
+	op.Password = password
+
+	if err := extractWriteConcernArg(op, "changeUserPassword()", args, 2); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// splitNamedSpec separates the name field (user or role) from a createUser/createRole document.
+func splitNamedSpec(spec bson.D, nameKey, method string) (string, bson.D, error) {
+	var name string
+	var found bool
+	rest := bson.D{}
+	for _, elem := range spec {
+		if elem.Key == nameKey {
+			s, ok := elem.Value.(string)
+			if !ok {
+				return "", nil, optionError(elem.Key, "%s %s must be a string", method, nameKey)
+			}
+			name = s
+			found = true
+			continue
+		}
+		rest = append(rest, elem)
+	}
+	if !found {
+		return "", nil, fmt.Errorf("%s document must contain a '%s' field", method, nameKey)
+	}
+	return name, rest, nil
+}
+
+// extractWriteConcernArg extracts the optional trailing writeConcern argument at idx,
+// which must be the last argument.
+func extractWriteConcernArg(op *Operation, method string, args []ast.Node, idx int) error {
+	if len(args) <= idx {
+		return nil
+	}
+	wc, err := requireDocument(args, idx, method+" writeConcern")
+	if err != nil {
+		return err
+	}
+	op.WriteConcern = wc
+
+	if len(args) > idx+1 {
+		return errorAt(args[idx+1], "%s takes at most %d arguments", method, idx+1)
+	}
+	return nil
+}
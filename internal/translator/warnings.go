@@ -0,0 +1,135 @@
+package translator
+
+import (
+	"fmt"
+
+	"github.com/bytebase/gomongo/types"
+	"github.com/bytebase/omni/mongo/ast"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Warning describes part of a statement that was ignored, rewritten or approximated.
+type Warning struct {
+	Code    types.WarningCode
+	Message string
+	Offset  int // byte offset in the statement
+}
+
+// warn records a warning for the part of the statement starting at offset.
+func (op *Operation) warn(code types.WarningCode, offset int, format string, args ...any) {
+	op.Warnings = append(op.Warnings, Warning{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+		Offset:  offset,
+	})
+}
+
+// keyOffset returns the offset of key in a document node, or the start of node
+// if it is not a document literal with that key, such as a placeholder.
+func keyOffset(node ast.Node, key string) int {
+	if doc, ok := node.(*ast.Document); ok {
+		for _, kv := range doc.Pairs {
+			if kv.Key == key {
+				return kv.KeyLoc.Start
+			}
+		}
+	}
+	return node.GetLoc().Start
+}
+
+// warnWriteConcern warns about the fields of the writeConcern option of a
+// collection write that the driver cannot apply: wtimeout, which MongoDB Go
+// driver v2 removed, unknown fields and a j that is not a boolean.
+func (op *Operation) warnWriteConcern(args []ast.Node, key string, doc bson.D) {
+	node := optionNode(args, key)
+	for _, elem := range doc {
+		switch elem.Key {
+		case "w":
+			continue
+		case "j":
+			if _, ok := elem.Value.(bool); ok {
+				continue
+			}
+		}
+		op.warn(types.WarningIgnoredOption, keyOffset(node, elem.Key),
+			"writeConcern %s is not supported and was ignored", elem.Key)
+	}
+}
+
+// collationFields lists the collation fields the driver applies, with the
+// check their value must pass.
+var collationFields = map[string]func(any) bool{
+	"locale":          isString,
+	"caseLevel":       isBool,
+	"caseFirst":       isString,
+	"strength":        isInteger,
+	"numericOrdering": isBool,
+	"alternate":       isString,
+	"maxVariable":     isString,
+	"normalization":   isBool,
+	"backwards":       isBool,
+}
+
+func isString(v any) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+func isBool(v any) bool {
+	_, ok := v.(bool)
+	return ok
+}
+
+func isInteger(v any) bool {
+	switch v.(type) {
+	case int32, int64:
+		return true
+	}
+	return false
+}
+
+// warnCollation warns about the fields of the collation option that are
+// unknown or have the wrong type, which the driver does not apply.
+func (op *Operation) warnCollation(args []ast.Node, key string, doc bson.D) {
+	node := optionNode(args, key)
+	for _, elem := range doc {
+		if valid, ok := collationFields[elem.Key]; ok && valid(elem.Value) {
+			continue
+		}
+		op.warn(types.WarningIgnoredOption, keyOffset(node, elem.Key),
+			"collation %s is not supported or has the wrong type and was ignored", elem.Key)
+	}
+}
+
+// cursorMethodOperations lists, for each cursor method that sets an operation
+// field, the operations whose execution reads that field. On any other
+// operation the method has no effect.
+var cursorMethodOperations = map[string][]types.OperationType{
+	"sort": {
+		types.OpFind, types.OpFindOne, types.OpUpdateOne, types.OpReplaceOne,
+		types.OpFindOneAndUpdate, types.OpFindOneAndReplace, types.OpFindOneAndDelete,
+	},
+	"limit": {types.OpFind, types.OpCountDocuments},
+	"skip":  {types.OpFind, types.OpFindOne, types.OpCountDocuments},
+	"projection": {
+		types.OpFind, types.OpFindOne,
+		types.OpFindOneAndUpdate, types.OpFindOneAndReplace, types.OpFindOneAndDelete,
+	},
+	"hint": {
+		types.OpFind, types.OpFindOne, types.OpAggregate, types.OpCountDocuments,
+		types.OpUpdateOne, types.OpUpdateMany, types.OpReplaceOne, types.OpDeleteOne, types.OpDeleteMany,
+		types.OpFindOneAndUpdate, types.OpFindOneAndReplace, types.OpFindOneAndDelete,
+	},
+	"max": {types.OpFind, types.OpFindOne},
+	"min": {types.OpFind, types.OpFindOne},
+}
+
+// cursorMethodHonored reports whether the operation reads the field set by a cursor method.
+func cursorMethodHonored(opType types.OperationType, method string) bool {
+	for _, t := range cursorMethodOperations[method] {
+		if t == opType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+package gomongo_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestResultTruncated(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_truncated_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		_, err := gc.Execute(ctx, dbName, `db.items.insertMany([{ n: 1 }, { n: 2 }, { n: 3 }])`)
+		require.NoError(t, err)
+
+		tests := []struct {
+			statement string
+			maxRows   int64
+			rows      int
+			truncated bool
+		}{
+			{`db.items.find()`, 2, 2, true},
+			{`db.items.find()`, 3, 3, false},
+			{`db.items.find()`, 5, 3, false},
+			{`db.items.find().limit(2)`, 2, 2, false},
+			{`db.items.find().limit(3)`, 2, 2, true},
+		}
+		for _, tc := range tests {
+			result, err := gc.Execute(ctx, dbName, tc.statement, gomongo.WithMaxRows(tc.maxRows))
+			require.NoError(t, err, tc.statement)
+			require.Equal(t, tc.rows, len(result.Value), tc.statement)
+			require.Equal(t, tc.truncated, result.Truncated, "%s with max rows %d", tc.statement, tc.maxRows)
+		}
+
+		result, err := gc.Execute(ctx, dbName, `db.items.countDocuments()`, gomongo.WithMaxRows(2))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.Value[0])
+		require.True(t, result.Truncated)
+
+		result, err = gc.Execute(ctx, dbName, `db.items.countDocuments()`, gomongo.WithMaxRows(3))
+		require.NoError(t, err)
+		require.Equal(t, int64(3), result.Value[0])
+		require.False(t, result.Truncated)
+
+		// Streams stop at the cap as well.
+		count := 0
+		err = gc.Stream(ctx, dbName, `db.items.find()`, func(_ bson.D) error {
+			count++
+			return nil
+		}, gomongo.WithMaxRows(2))
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+}
+
+func TestMaxRowsListOperations(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_maxrows_list_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		_, err := gc.Execute(ctx, dbName, `db.items.insertMany([{ n: 1, c: "a" }, { n: 2, c: "b" }, { n: 3, c: "c" }])`)
+		require.NoError(t, err)
+		_, err = gc.Execute(ctx, dbName, `db.items.createIndex({ n: 1 })`)
+		require.NoError(t, err)
+		_, err = gc.Execute(ctx, dbName, `db.items.createIndex({ c: 1 })`)
+		require.NoError(t, err)
+		for _, name := range []string{"a", "b", "c"} {
+			_, err = gc.Execute(ctx, dbName, fmt.Sprintf(`db.createCollection("%s")`, name))
+			require.NoError(t, err)
+		}
+
+		tests := []struct {
+			statement string
+			rows      int
+			truncated bool
+		}{
+			{`db.items.aggregate([{ $sort: { n: 1 } }])`, 2, true},
+			{`db.items.aggregate([{ $match: { n: { $gt: 1 } } }])`, 2, false},
+			{`db.items.distinct("c")`, 2, true},
+			{`db.items.distinct("c", { n: { $lt: 3 } })`, 2, false},
+			{`db.items.getIndexes()`, 2, true},
+			{`db.getCollectionInfos()`, 2, true},
+			{`db.getCollectionNames()`, 2, true},
+			{`show collections`, 2, true},
+		}
+		for _, tc := range tests {
+			result, err := gc.Execute(ctx, dbName, tc.statement, gomongo.WithMaxRows(2))
+			require.NoError(t, err, tc.statement)
+			require.Equal(t, tc.rows, len(result.Value), tc.statement)
+			require.Equal(t, tc.truncated, result.Truncated, tc.statement)
+		}
+
+		// Streams stop at the cap as well.
+		var docs []bson.D
+		err = gc.Stream(ctx, dbName, `db.items.aggregate([])`, func(doc bson.D) error {
+			docs = append(docs, doc)
+			return nil
+		}, gomongo.WithMaxRows(1))
+		require.NoError(t, err)
+		require.Equal(t, 1, len(docs))
+	})
+}
+
+func TestMaxRowsAggregateOut(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_maxrows_out_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		_, err := gc.Execute(ctx, dbName, `db.items.insertMany([{ n: 1 }, { n: 2 }, { n: 3 }])`)
+		require.NoError(t, err)
+
+		// The row cap does not limit what $out and $merge write.
+		for _, stage := range []string{`{ $out: "copy" }`, `{ $merge: { into: "merged" } }`} {
+			result, err := gc.Execute(ctx, dbName, fmt.Sprintf(`db.items.aggregate([{ $match: {} }, %s])`, stage), gomongo.WithMaxRows(1))
+			require.NoError(t, err)
+			require.False(t, result.Truncated)
+		}
+		for _, coll := range []string{"copy", "merged"} {
+			count, err := gc.Execute(ctx, dbName, fmt.Sprintf(`db.%s.countDocuments()`, coll))
+			require.NoError(t, err)
+			require.Equal(t, int64(3), count.Value[0], coll)
+		}
+	})
+}
+
+func TestMaxBytes(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_maxbytes_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		// Each document is 122 bytes of BSON.
+		s := strings.Repeat("x", 100)
+		_, err := gc.Execute(ctx, dbName, fmt.Sprintf(`db.items.insertMany([{ _id: 1, s: "%[1]s" }, { _id: 2, s: "%[1]s" }, { _id: 3, s: "%[1]s" }])`, s))
+		require.NoError(t, err)
+
+		tests := []struct {
+			statement string
+			maxBytes  int64
+			rows      int
+			truncated bool
+		}{
+			{`db.items.find()`, 300, 2, true},
+			{`db.items.find()`, 366, 3, false},
+			{`db.items.find()`, 100, 0, true},
+			{`db.items.aggregate([{ $sort: { _id: 1 } }])`, 300, 2, true},
+			// Each distinct int32 value is 4 bytes.
+			{`db.items.distinct("_id")`, 8, 2, true},
+			{`db.items.distinct("_id")`, 12, 3, false},
+		}
+		for _, tc := range tests {
+			result, err := gc.Execute(ctx, dbName, tc.statement, gomongo.WithMaxBytes(tc.maxBytes))
+			require.NoError(t, err, tc.statement)
+			require.Equal(t, tc.rows, len(result.Value), "%s with max bytes %d", tc.statement, tc.maxBytes)
+			require.Equal(t, tc.truncated, result.Truncated, "%s with max bytes %d", tc.statement, tc.maxBytes)
+		}
+
+		// The row and byte caps combine; the tighter one applies.
+		result, err := gc.Execute(ctx, dbName, `db.items.find()`, gomongo.WithMaxRows(1), gomongo.WithMaxBytes(1000))
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+		require.True(t, result.Truncated)
+	})
+}
+
+func TestMaxValueBytes(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_maxvaluebytes_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		_, err := gc.Execute(ctx, dbName, `db.items.insertOne({
+			_id: 1,
+			s: "héllo",
+			u: UUID("550e8400-e29b-41d4-a716-446655440000"),
+			nested: { t: "abcdefgh" },
+			arr: ["abcdefgh", "ab"]
+		})`)
+		require.NoError(t, err)
+
+		// Each shortened string is at most 5 bytes, including the 3-byte "…".
+		result, err := gc.Execute(ctx, dbName, `db.items.findOne()`, gomongo.WithMaxValueBytes(5))
+		require.NoError(t, err)
+		require.Equal(t, 4, result.TruncatedValues)
+		require.False(t, result.Truncated)
+		require.Equal(t, bson.D{
+			{Key: "_id", Value: int32(1)},
+			// The cut falls inside "é", so only "h" is kept.
+			{Key: "s", Value: "h…"},
+			{Key: "u", Value: bson.Binary{Subtype: bson.TypeBinaryUUID, Data: []byte{0x55, 0x0e, 0x84, 0x00, 0xe2}}},
+			{Key: "nested", Value: bson.D{{Key: "t", Value: "ab…"}}},
+			{Key: "arr", Value: bson.A{"ab…", "ab"}},
+		}, result.Value[0])
+
+		// Streamed documents are shortened as well.
+		var docs []bson.D
+		err = gc.Stream(ctx, dbName, `db.items.find()`, func(doc bson.D) error {
+			docs = append(docs, doc)
+			return nil
+		}, gomongo.WithMaxValueBytes(5))
+		require.NoError(t, err)
+		require.Equal(t, result.Value[0], docs[0])
+	})
+}
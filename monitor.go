@@ -0,0 +1,116 @@
+package gomongo
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// batchCommands lists the commands that return a batch of cursor results.
+var batchCommands = map[string]bool{
+	"find":            true,
+	"aggregate":       true,
+	"getMore":         true,
+	"listIndexes":     true,
+	"listCollections": true,
+}
+
+// commandStatsKey is the context key under which execute collects command statistics.
+type commandStatsKey struct{}
+
+// commandStats accumulates the commands sent while executing one statement.
+type commandStats struct {
+	mu             sync.Mutex
+	roundTripTime  time.Duration
+	serverAddress  string
+	batches        int
+	readPreference string
+}
+
+// NewCommandMonitor returns a command monitor that lets Execute report
+// Result.RoundTripTime, Result.ServerAddress, Result.Batches and
+// Result.ReadPreference. Install it on the MongoDB client passed to NewClient:
+//
+//	client, err := mongo.Connect(options.Client().ApplyURI(uri).SetMonitor(gomongo.NewCommandMonitor(nil)))
+//
+// If next is not nil, every event is passed on to it as well.
+func NewCommandMonitor(next *event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			if stats, ok := ctx.Value(commandStatsKey{}).(*commandStats); ok {
+				stats.started(evt)
+			}
+			if next != nil && next.Started != nil {
+				next.Started(ctx, evt)
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			if stats, ok := ctx.Value(commandStatsKey{}).(*commandStats); ok {
+				stats.finished(&evt.CommandFinishedEvent, true)
+			}
+			if next != nil && next.Succeeded != nil {
+				next.Succeeded(ctx, evt)
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			if stats, ok := ctx.Value(commandStatsKey{}).(*commandStats); ok {
+				stats.finished(&evt.CommandFinishedEvent, false)
+			}
+			if next != nil && next.Failed != nil {
+				next.Failed(ctx, evt)
+			}
+		},
+	}
+}
+
+// started records the server of the first command and the first read
+// preference the driver sent. The driver leaves $readPreference out of writes,
+// commands sent to a standalone server and primary reads on a replica set, so
+// the read preference stays empty for statements that only send those.
+func (s *commandStats) started(evt *event.CommandStartedEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.serverAddress == "" {
+		// Connection IDs have the form "host:port[-n]".
+		address, _, _ := strings.Cut(evt.ConnectionID, "[")
+		s.serverAddress = address
+	}
+	if s.readPreference == "" {
+		if mode, ok := evt.Command.Lookup("$readPreference", "mode").StringValueOK(); ok {
+			s.readPreference = mode
+		}
+	}
+}
+
+// finished records the round-trip time of a command and counts cursor batches.
+func (s *commandStats) finished(evt *event.CommandFinishedEvent, succeeded bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.roundTripTime += evt.Duration
+	if succeeded && batchCommands[evt.CommandName] {
+		s.batches++
+	}
+}
+
+// withCommandStats returns a context that collects statistics for the commands
+// sent with it, when the client has the monitor from NewCommandMonitor installed.
+func withCommandStats(ctx context.Context) (context.Context, *commandStats) {
+	stats := &commandStats{}
+	return context.WithValue(ctx, commandStatsKey{}, stats), stats
+}
+
+// apply copies the collected statistics to the result.
+func (s *commandStats) apply(result *Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result.RoundTripTime = s.roundTripTime
+	result.ServerAddress = s.serverAddress
+	result.Batches = s.batches
+	result.ReadPreference = s.readPreference
+}
@@ -0,0 +1,66 @@
+package gomongo_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestResultExecutionMetadata(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_metadata_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+
+		// Events are still passed on to the application's own monitor.
+		forwarded := 0
+		monitor := gomongo.NewCommandMonitor(&event.CommandMonitor{
+			Succeeded: func(context.Context, *event.CommandSucceededEvent) { forwarded++ },
+		})
+		client, err := mongo.Connect(options.Client().ApplyURI(db.URI).SetMonitor(monitor))
+		require.NoError(t, err)
+		defer func() { _ = client.Disconnect(ctx) }()
+		gc := gomongo.NewClient(client)
+
+		var docs []string
+		for i := 0; i < 250; i++ {
+			docs = append(docs, fmt.Sprintf(`{ n: %d }`, i))
+		}
+		_, err = gc.Execute(ctx, dbName, fmt.Sprintf(`db.items.insertMany([%s])`, strings.Join(docs, ", ")))
+		require.NoError(t, err)
+
+		result, err := gc.Execute(ctx, dbName, `db.items.find()`)
+		require.NoError(t, err)
+		require.Equal(t, 250, len(result.Value))
+		// The first batch holds 101 documents; the rest arrive with getMore.
+		require.Equal(t, 2, result.Batches)
+		require.Positive(t, result.Duration)
+		require.Positive(t, result.RoundTripTime)
+		require.LessOrEqual(t, result.RoundTripTime, result.Duration)
+		require.NotEmpty(t, result.ServerAddress)
+		require.NotContains(t, result.ServerAddress, "[")
+		// The driver sends no $readPreference to a standalone server.
+		require.Empty(t, result.ReadPreference)
+		require.Positive(t, forwarded)
+
+		result, err = gc.Execute(ctx, dbName, `db.items.countDocuments()`)
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Batches)
+
+		// Without the monitor only the duration is reported.
+		result, err = gomongo.NewClient(db.Client).Execute(ctx, dbName, `db.items.find()`)
+		require.NoError(t, err)
+		require.Positive(t, result.Duration)
+		require.Zero(t, result.Batches)
+		require.Empty(t, result.ServerAddress)
+	})
+}
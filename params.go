@@ -0,0 +1,49 @@
+package gomongo
+
+import "github.com/bytebase/gomongo/internal/translator"
+
+// NamedParam is a value bound to a :name placeholder. Create one with Named.
+type NamedParam struct {
+	Name  string
+	Value any
+}
+
+// Named binds value to the :name placeholder of a statement.
+func Named(name string, value any) NamedParam {
+	return NamedParam{Name: name, Value: value}
+}
+
+// WithParams binds values to the placeholders of the statement. Values are
+// bound to $1, $2, ... in order, except NamedParam values, which are bound to
+// the :name placeholder of the same name.
+//
+// A placeholder stands for a single value, such as a filter field value, a
+// whole document or a limit. Bound values are converted as by bson.Marshal:
+// bson.D, maps and structs become documents, slices become arrays and
+// time.Time becomes a date. Because values are bound after parsing, a value
+// can never change the structure of the statement, unlike a value formatted
+// into the statement text.
+func WithParams(params ...any) ExecuteOption {
+	return func(c *executeConfig) {
+		c.params = append(c.params, params...)
+	}
+}
+
+// translatorParams sorts bound values into positional and named parameters.
+func translatorParams(params []any) *translator.Params {
+	if len(params) == 0 {
+		return nil
+	}
+	p := &translator.Params{}
+	for _, v := range params {
+		if np, ok := v.(NamedParam); ok {
+			if p.Named == nil {
+				p.Named = make(map[string]any)
+			}
+			p.Named[np.Name] = np.Value
+			continue
+		}
+		p.Positional = append(p.Positional, v)
+	}
+	return p
+}
@@ -0,0 +1,147 @@
+package gomongo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestParams(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_params_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		type user struct {
+			ID     bson.ObjectID `bson:"_id"`
+			Name   string        `bson:"name"`
+			Age    int           `bson:"age"`
+			Joined time.Time     `bson:"joined"`
+		}
+		joined := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		alice := user{ID: bson.NewObjectID(), Name: "alice", Age: 30, Joined: joined}
+
+		_, err := gc.Execute(ctx, dbName, `db.users.insertOne($1)`, gomongo.WithParams(alice))
+		require.NoError(t, err)
+		_, err = gc.Execute(ctx, dbName, `db.users.insertMany([$1, :bob])`, gomongo.WithParams(
+			bson.D{{Key: "name", Value: "carol"}, {Key: "age", Value: 20}},
+			gomongo.Named("bob", map[string]any{"name": "bob", "age": 25}),
+		))
+		require.NoError(t, err)
+
+		tests := []struct {
+			name      string
+			statement string
+			params    []any
+			names     []string
+		}{
+			{
+				name:      "positional and named",
+				statement: `db.users.find({ name: $1, age: { $gt: :minAge } })`,
+				params:    []any{"alice", gomongo.Named("minAge", 18)},
+				names:     []string{"alice"},
+			},
+			{
+				name:      "object id",
+				statement: `db.users.find({ _id: $1 })`,
+				params:    []any{alice.ID},
+				names:     []string{"alice"},
+			},
+			{
+				name:      "date",
+				statement: `db.users.find({ joined: { $gte: :since } })`,
+				params:    []any{gomongo.Named("since", joined)},
+				names:     []string{"alice"},
+			},
+			{
+				name:      "whole filter and limit",
+				statement: `db.users.find($1).sort({ age: 1 }).limit($2)`,
+				params:    []any{bson.D{{Key: "age", Value: bson.D{{Key: "$gte", Value: 20}}}}, 2},
+				names:     []string{"carol", "bob"},
+			},
+			{
+				name:      "array element",
+				statement: `db.users.find({ name: { $in: [$1, $2] } }).sort({ name: 1 })`,
+				params:    []any{"bob", "carol"},
+				names:     []string{"bob", "carol"},
+			},
+			{
+				name:      "value cannot change the statement",
+				statement: `db.users.find({ name: $1 })`,
+				params:    []any{`alice", age: { $gt: 0 }, x: "`},
+			},
+			{
+				name:      "pipeline",
+				statement: `db.users.aggregate($1)`,
+				params:    []any{[]bson.D{{{Key: "$match", Value: bson.D{{Key: "name", Value: "bob"}}}}}},
+				names:     []string{"bob"},
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				result, err := gc.Execute(ctx, dbName, tc.statement, gomongo.WithParams(tc.params...))
+				require.NoError(t, err)
+				var names []string
+				for _, v := range result.Value {
+					for _, e := range v.(bson.D) {
+						if e.Key == "name" {
+							names = append(names, e.Value.(string))
+						}
+					}
+				}
+				require.Equal(t, tc.names, names)
+			})
+		}
+
+		result, err := gc.Execute(ctx, dbName, `db.users.updateOne({ name: :name }, $1)`, gomongo.WithParams(
+			bson.D{{Key: "$set", Value: bson.D{{Key: "age", Value: 31}}}},
+			gomongo.Named("name", "alice"),
+		))
+		require.NoError(t, err)
+		update, ok := result.UpdateResult()
+		require.True(t, ok)
+		require.Equal(t, int64(1), update.ModifiedCount)
+	})
+}
+
+func TestParamsErrors(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_params_errors_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		tests := []struct {
+			statement string
+			params    []any
+			err       string
+		}{
+			{`db.users.find({ name: $2 })`, []any{"alice"}, "no value bound to parameter $2"},
+			{`db.users.find({ name: :name })`, nil, "no value bound to parameter :name"},
+			{`db.users.find({ name: $1 })`, []any{make(chan int)}, "invalid value for parameter $1"},
+			{`db.users.find().limit($1)`, []any{"10"}, "limit() requires an integer argument"},
+			{`db.users.find($1)`, []any{"alice"}, "find() filter must be a document"},
+		}
+		for _, tc := range tests {
+			_, err := gc.Execute(ctx, dbName, tc.statement, gomongo.WithParams(tc.params...))
+			require.ErrorContains(t, err, tc.err, tc.statement)
+		}
+
+		// Placeholders inside strings are not bound.
+		_, err := gc.Execute(ctx, dbName, `db.users.insertOne({ note: ":name $1" })`, gomongo.WithParams("x"))
+		require.NoError(t, err)
+		result, err := gc.Execute(ctx, dbName, `db.users.findOne({}, { _id: 0 })`)
+		require.NoError(t, err)
+		require.Equal(t, bson.D{{Key: "note", Value: ":name $1"}}, result.Value[0])
+	})
+}
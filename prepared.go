@@ -0,0 +1,70 @@
+package gomongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/bytebase/gomongo/internal/translator"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// PreparedStatement is a statement parsed once by Client.Prepare that can be
+// executed any number of times with different parameters. It is safe for
+// concurrent use by multiple goroutines.
+type PreparedStatement struct {
+	client    *Client
+	statement string
+	stmt      *translator.Statement
+}
+
+// Prepare parses a MongoDB shell statement for repeated execution. A statement
+// without placeholders is also translated once, so executing it only runs the
+// operation. A statement with placeholders, or with helpers that generate a new
+// value on each call, such as ObjectId() and ISODate() without arguments, is
+// translated on each execution from the parsed statement.
+//
+// Syntax errors are returned by Prepare. Errors that depend on the bound
+// values, such as a missing parameter, are returned when the statement is
+// executed.
+func (c *Client) Prepare(statement string) (*PreparedStatement, error) {
+	stmt, err := translator.PrepareWithOptions(statement, translator.Options{ExtendedJSON: c.extendedJSON})
+	if err != nil {
+		return nil, convertTranslatorError(statement, err)
+	}
+	return &PreparedStatement{client: c, statement: statement, stmt: stmt}, nil
+}
+
+// Execute executes the prepared statement. See Client.Execute. The statement
+// was parsed by Prepare, so Result.Duration covers binding the parameters and
+// executing, not parsing.
+func (p *PreparedStatement) Execute(ctx context.Context, database string, opts ...ExecuteOption) (*Result, error) {
+	start := time.Now()
+	cfg := &executeConfig{securityAdmin: p.client.securityAdmin, noJavaScript: p.client.noJavaScript, extendedJSON: p.client.extendedJSON}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	op, err := bind(p.statement, p.stmt, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return executeOperation(ctx, p.client.client, database, p.statement, op, cfg, start)
+}
+
+// Stream executes the prepared statement, calling fn for each document it
+// returns. See Client.Stream.
+func (p *PreparedStatement) Stream(ctx context.Context, database string, fn func(bson.D) error, opts ...ExecuteOption) error {
+	cfg := &executeConfig{securityAdmin: p.client.securityAdmin, noJavaScript: p.client.noJavaScript, extendedJSON: p.client.extendedJSON}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	op, err := bind(p.statement, p.stmt, cfg)
+	if err != nil {
+		return err
+	}
+	return streamOperation(ctx, p.client.client, database, p.statement, op, cfg, fn)
+}
+
+// String returns the statement as written.
+func (p *PreparedStatement) String() string {
+	return p.statement
+}
@@ -0,0 +1,74 @@
+package gomongo_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestPreparedStatement(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_prepared_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		_, err := gc.Execute(ctx, dbName, `db.items.insertMany([{ n: 1 }, { n: 2 }, { n: 3 }, { n: 4 }])`)
+		require.NoError(t, err)
+
+		count, err := gc.Prepare(`db.items.countDocuments({ n: { $gte: $1 } })`)
+		require.NoError(t, err)
+		for minimum, want := range map[int]int64{1: 4, 3: 2, 5: 0} {
+			result, err := count.Execute(ctx, dbName, gomongo.WithParams(minimum))
+			require.NoError(t, err)
+			require.Equal(t, want, result.Value[0], "n >= %d", minimum)
+		}
+
+		// A prepared statement can be executed from many goroutines at once.
+		find, err := gc.Prepare(`db.items.find({ n: :n }, { _id: 0 })`)
+		require.NoError(t, err)
+		var wg sync.WaitGroup
+		for n := 1; n <= 4; n++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 5 {
+					result, err := find.Execute(ctx, dbName, gomongo.WithParams(gomongo.Named("n", n)))
+					if !assert.NoError(t, err) {
+						return
+					}
+					assert.Equal(t, []any{bson.D{{Key: "n", Value: int32(n)}}}, result.Value)
+				}
+			}()
+		}
+		wg.Wait()
+
+		// Statements without placeholders are translated once and reused.
+		all, err := gc.Prepare(`db.items.find().sort({ n: 1 })`)
+		require.NoError(t, err)
+		for range 2 {
+			var values []any
+			err := all.Stream(ctx, dbName, func(doc bson.D) error {
+				values = append(values, doc[1].Value)
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, []any{int32(1), int32(2), int32(3), int32(4)}, values)
+		}
+
+		_, err = find.Execute(ctx, dbName)
+		require.ErrorContains(t, err, "no value bound to parameter :n")
+
+		_, err = gc.Prepare(`db.items.find({`)
+		var parseErr *gomongo.ParseError
+		require.ErrorAs(t, err, &parseErr)
+	})
+}
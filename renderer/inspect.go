@@ -0,0 +1,517 @@
+package renderer
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// The layout below follows Node.js util.inspect, which mongosh uses to print
+// values, with mongosh's custom formatting for BSON types.
+
+// compactLevels is util.inspect's default "compact" setting: up to this many of
+// the innermost nesting levels are combined on a single line when they fit.
+const compactLevels = 3
+
+// minLineWidth is the length below which strings are never split across lines.
+const minLineWidth = 16
+
+// maxArrayLength is the number of array elements shown before the rest are summarized.
+const maxArrayLength = 100
+
+// identifierKey matches object keys that are printed without quotes.
+var identifierKey = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z_0-9]*$`)
+
+// jsRegexFlags are the regular expression options JavaScript understands.
+const jsRegexFlags = "dgimsuy"
+
+// inspector formats values the way util.inspect does.
+type inspector struct {
+	breakLength    int
+	depth          int
+	indentationLvl int
+	currentDepth   int
+}
+
+// formatValue formats v at the given nesting level.
+func (c *inspector) formatValue(v any, recurseTimes int) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bson.D:
+		if ref, ok := c.formatDBRef(val, recurseTimes); ok {
+			return ref
+		}
+		return c.formatObject(val, recurseTimes)
+	case bson.M:
+		return c.formatObject(sortedDocument(val), recurseTimes)
+	case map[string]any:
+		return c.formatObject(sortedDocument(val), recurseTimes)
+	case bson.Raw:
+		var doc bson.D
+		if err := bson.Unmarshal(val, &doc); err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return c.formatValue(doc, recurseTimes)
+	case bson.A:
+		return c.formatArray([]any(val), recurseTimes)
+	case []any:
+		return c.formatArray(val, recurseTimes)
+	case []string:
+		values := make([]any, len(val))
+		for i, s := range val {
+			values[i] = s
+		}
+		return c.formatArray(values, recurseTimes)
+	case bson.CodeWithScope:
+		return "Code(" + c.formatString(string(val.Code)) + ", " + c.formatValue(val.Scope, recurseTimes) + ")"
+	case string:
+		return c.formatString(val)
+	default:
+		return formatScalar(v)
+	}
+}
+
+// formatObject formats a document as an object literal.
+func (c *inspector) formatObject(doc bson.D, recurseTimes int) string {
+	if len(doc) == 0 {
+		return "{}"
+	}
+	if recurseTimes > c.depth {
+		return "[Object]"
+	}
+	recurseTimes++
+	c.currentDepth = recurseTimes
+
+	output := make([]string, len(doc))
+	for i, elem := range doc {
+		c.indentationLvl += 2
+		str := c.formatValue(elem.Value, recurseTimes)
+		c.indentationLvl -= 2
+		output[i] = formatKey(elem.Key) + ": " + str
+	}
+	return c.reduceToSingleString(output, "{", "}", false, recurseTimes, nil)
+}
+
+// formatArray formats a slice as an array literal.
+func (c *inspector) formatArray(values []any, recurseTimes int) string {
+	if len(values) == 0 {
+		return "[]"
+	}
+	if recurseTimes > c.depth {
+		return "[Array]"
+	}
+	recurseTimes++
+	c.currentDepth = recurseTimes
+
+	shown := min(len(values), maxArrayLength)
+	output := make([]string, 0, shown+1)
+	for _, v := range values[:shown] {
+		c.indentationLvl += 2
+		output = append(output, c.formatValue(v, recurseTimes))
+		c.indentationLvl -= 2
+	}
+	if remaining := len(values) - shown; remaining > 0 {
+		output = append(output, fmt.Sprintf("... %d more item%s", remaining, plural(remaining)))
+	}
+	return c.reduceToSingleString(output, "[", "]", true, recurseTimes, values)
+}
+
+// reduceToSingleString joins formatted entries on one line when they fit, and
+// otherwise puts each entry on its own line.
+func (c *inspector) reduceToSingleString(output []string, open, close string, isArray bool, recurseTimes int, values []any) string {
+	entries := len(output)
+	if isArray && entries > 6 {
+		output = c.groupArrayElements(output, values)
+	}
+	if c.currentDepth-recurseTimes < compactLevels && entries == len(output) {
+		start := len(output) + c.indentationLvl + len(open) + 10
+		if c.isBelowBreakLength(output, start) {
+			joined := strings.Join(output, ", ")
+			if !strings.Contains(joined, "\n") {
+				return open + " " + joined + " " + close
+			}
+		}
+	}
+	indentation := "\n" + strings.Repeat(" ", c.indentationLvl)
+	return open + indentation + "  " + strings.Join(output, ","+indentation+"  ") + indentation + close
+}
+
+// isBelowBreakLength reports whether the entries fit within the line width.
+func (c *inspector) isBelowBreakLength(output []string, start int) bool {
+	totalLength := len(output) + start
+	if totalLength+len(output) > c.breakLength {
+		return false
+	}
+	for _, s := range output {
+		totalLength += jsLength(s)
+		if totalLength > c.breakLength {
+			return false
+		}
+	}
+	return true
+}
+
+// groupArrayElements arranges the entries of long arrays of short values in columns.
+func (c *inspector) groupArrayElements(output []string, values []any) []string {
+	const separatorSpace = 2 // a comma and a space between entries
+	outputLength := len(output)
+	if len(values) > maxArrayLength {
+		// Keep the "... more items" entry out of the columns.
+		outputLength--
+	}
+
+	totalLength := 0
+	maxLength := 0
+	dataLen := make([]int, outputLength)
+	for i := 0; i < outputLength; i++ {
+		l := jsLength(output[i])
+		dataLen[i] = l
+		totalLength += l + separatorSpace
+		if maxLength < l {
+			maxLength = l
+		}
+	}
+	actualMax := maxLength + separatorSpace
+	if actualMax*3+c.indentationLvl >= c.breakLength ||
+		(float64(totalLength)/float64(actualMax) <= 5 && maxLength > 6) {
+		return output
+	}
+
+	const approxCharHeights = 2.5
+	averageBias := math.Sqrt(float64(actualMax) - float64(totalLength)/float64(len(output)))
+	biasedMax := math.Max(float64(actualMax)-3-averageBias, 1)
+	columns := min(
+		int(math.Round(math.Sqrt(approxCharHeights*biasedMax*float64(outputLength))/biasedMax)),
+		(c.breakLength-c.indentationLvl)/actualMax,
+		compactLevels*4,
+		15,
+	)
+	if columns <= 1 {
+		return output
+	}
+
+	maxLineLength := make([]int, 0, columns)
+	for i := 0; i < columns; i++ {
+		lineLength := 0
+		for j := i; j < len(output); j += columns {
+			if j < outputLength && dataLen[j] > lineLength {
+				lineLength = dataLen[j]
+			}
+		}
+		maxLineLength = append(maxLineLength, lineLength+separatorSpace)
+	}
+
+	padStart := true
+	for _, v := range values[:min(len(values), len(output))] {
+		if !isJSNumber(v) {
+			padStart = false
+			break
+		}
+	}
+
+	grouped := make([]string, 0, outputLength/columns+2)
+	for i := 0; i < outputLength; i += columns {
+		last := min(i+columns, outputLength)
+		var line strings.Builder
+		j := i
+		for ; j < last-1; j++ {
+			line.WriteString(pad(output[j]+", ", maxLineLength[j-i], padStart))
+		}
+		if padStart {
+			line.WriteString(pad(output[j], maxLineLength[j-i]-separatorSpace, true))
+		} else {
+			line.WriteString(output[j])
+		}
+		grouped = append(grouped, line.String())
+	}
+	if len(values) > maxArrayLength {
+		grouped = append(grouped, output[outputLength])
+	}
+	return grouped
+}
+
+// formatString quotes a string, splitting long multi-line strings at newlines.
+func (c *inspector) formatString(s string) string {
+	l := jsLength(s)
+	if l > minLineWidth && l > c.breakLength-c.indentationLvl-4 {
+		lines := splitAfterNewlines(s)
+		quoted := make([]string, len(lines))
+		for i, line := range lines {
+			quoted[i] = quoteString(line)
+		}
+		return strings.Join(quoted, " +\n"+strings.Repeat(" ", c.indentationLvl+2))
+	}
+	return quoteString(s)
+}
+
+// formatDBRef formats a {$ref, $id, $db} document as mongosh's DBRef(...).
+func (c *inspector) formatDBRef(doc bson.D, recurseTimes int) (string, bool) {
+	if len(doc) < 2 || len(doc) > 3 || doc[0].Key != "$ref" || doc[1].Key != "$id" {
+		return "", false
+	}
+	ref, ok := doc[0].Value.(string)
+	if !ok {
+		return "", false
+	}
+	args := []string{quoteString(ref), c.formatValue(doc[1].Value, recurseTimes)}
+	if len(doc) == 3 {
+		db, ok := doc[2].Value.(string)
+		if doc[2].Key != "$db" || !ok {
+			return "", false
+		}
+		args = append(args, quoteString(db))
+	}
+	return "DBRef(" + strings.Join(args, ", ") + ")", true
+}
+
+// formatScalar formats numbers, booleans and BSON scalar types.
+func formatScalar(v any) string {
+	switch val := v.(type) {
+	case bool:
+		return strconv.FormatBool(val)
+	case int32:
+		return strconv.FormatInt(int64(val), 10)
+	case int:
+		return strconv.Itoa(val)
+	case int64:
+		return "Long('" + strconv.FormatInt(val, 10) + "')"
+	case float64:
+		return formatNumber(val)
+	case bson.ObjectID:
+		return "ObjectId('" + val.Hex() + "')"
+	case bson.DateTime:
+		return "ISODate('" + formatISODate(val) + "')"
+	case bson.Decimal128:
+		return "Decimal128('" + val.String() + "')"
+	case bson.Binary:
+		return formatBinary(val)
+	case bson.Timestamp:
+		return fmt.Sprintf("Timestamp({ t: %d, i: %d })", val.T, val.I)
+	case bson.Regex:
+		return formatRegex(val)
+	case bson.MinKey:
+		return "MinKey()"
+	case bson.MaxKey:
+		return "MaxKey()"
+	case bson.Undefined:
+		return "undefined"
+	case bson.Null:
+		return "null"
+	case bson.JavaScript:
+		return "Code(" + quoteString(string(val)) + ")"
+	case bson.Symbol:
+		return "BSONSymbol(" + quoteString(string(val)) + ")"
+	case bson.DBPointer:
+		return "DBPointer(" + quoteString(val.DB) + ", ObjectId('" + val.Pointer.Hex() + "'))"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// formatNumber formats a double the way JavaScript prints numbers.
+func formatNumber(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	case f == 0 && math.Signbit(f):
+		return "-0"
+	}
+	abs := math.Abs(f)
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		s := strconv.FormatFloat(f, 'e', -1, 64)
+		mantissa, exp, _ := strings.Cut(s, "e")
+		sign := exp[:1]
+		exp = strings.TrimLeft(exp[1:], "0")
+		return mantissa + "e" + sign + exp
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatISODate formats a date like JavaScript's Date.prototype.toISOString.
+func formatISODate(d bson.DateTime) string {
+	t := d.Time().UTC()
+	year := t.Year()
+	if year >= 0 && year <= 9999 {
+		return t.Format("2006-01-02T15:04:05.000Z")
+	}
+	sign := "+"
+	if year < 0 {
+		sign = "-"
+		year = -year
+	}
+	return fmt.Sprintf("%s%06d", sign, year) + t.Format("-01-02T15:04:05.000Z")
+}
+
+// formatBinary formats binary data as a UUID or a base64 constructor.
+func formatBinary(b bson.Binary) string {
+	if b.Subtype == bson.TypeBinaryUUID && len(b.Data) == 16 {
+		h := hex.EncodeToString(b.Data)
+		return "UUID('" + h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:] + "')"
+	}
+	return fmt.Sprintf("Binary.createFromBase64('%s', %d)", base64.StdEncoding.EncodeToString(b.Data), b.Subtype)
+}
+
+// formatRegex formats a regular expression as a literal when JavaScript supports
+// its options, and as a BSONRegExp otherwise.
+func formatRegex(r bson.Regex) string {
+	for _, flag := range r.Options {
+		if !strings.ContainsRune(jsRegexFlags, flag) {
+			return "BSONRegExp(" + quoteString(r.Pattern) + ", " + quoteString(r.Options) + ")"
+		}
+	}
+	pattern := r.Pattern
+	if pattern == "" {
+		pattern = "(?:)"
+	}
+	return "/" + escapeRegexSlashes(pattern) + "/" + r.Options
+}
+
+// escapeRegexSlashes escapes unescaped forward slashes, as RegExp.prototype.source does.
+func escapeRegexSlashes(pattern string) string {
+	var b strings.Builder
+	escaped := false
+	inClass := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '[':
+			inClass = true
+		case r == ']':
+			inClass = false
+		case r == '/' && !inClass:
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// formatKey formats an object key, quoting it unless it is an identifier.
+func formatKey(key string) string {
+	if identifierKey.MatchString(key) {
+		return key
+	}
+	return quoteString(key)
+}
+
+// quoteString quotes a string like util.inspect: single quotes by default, and
+// double quotes or backticks when that avoids escaping a single quote.
+func quoteString(s string) string {
+	quote := '\''
+	if strings.ContainsRune(s, '\'') {
+		switch {
+		case !strings.ContainsRune(s, '"'):
+			quote = '"'
+		case !strings.ContainsRune(s, '`') && !strings.Contains(s, "${"):
+			quote = '`'
+		}
+	}
+
+	var b strings.Builder
+	b.WriteRune(quote)
+	for _, r := range s {
+		switch {
+		case r == quote && quote == '\'':
+			b.WriteString(`\'`)
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\b':
+			b.WriteString(`\b`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r < 0x20 || (r >= 0x7f && r <= 0x9f):
+			fmt.Fprintf(&b, `\x%02X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteRune(quote)
+	return b.String()
+}
+
+// splitAfterNewlines splits s into lines, keeping each newline with its line.
+func splitAfterNewlines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// sortedDocument converts a map to a document with keys in sorted order.
+func sortedDocument(m map[string]any) bson.D {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	doc := make(bson.D, len(keys))
+	for i, k := range keys {
+		doc[i] = bson.E{Key: k, Value: m[k]}
+	}
+	return doc
+}
+
+// isJSNumber reports whether v prints as a JavaScript number.
+func isJSNumber(v any) bool {
+	switch v.(type) {
+	case int32, int, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// jsLength returns the length of s in UTF-16 code units, as JavaScript measures strings.
+func jsLength(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// pad pads s with spaces to width, at the start or at the end.
+func pad(s string, width int, atStart bool) string {
+	n := width - jsLength(s)
+	if n <= 0 {
+		return s
+	}
+	if atStart {
+		return strings.Repeat(" ", n) + s
+	}
+	return s + strings.Repeat(" ", n)
+}
+
+// plural returns "s" unless n is one.
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,220 @@
+// Package renderer formats gomongo results as text, the way mongosh prints them.
+package renderer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/bytebase/gomongo/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Default layout settings, matching mongosh.
+const (
+	defaultWidth = 80
+	defaultDepth = 6
+)
+
+// config holds configuration for Render.
+type config struct {
+	width int
+	depth int
+}
+
+// Option configures Render behavior.
+type Option func(*config)
+
+// WithWidth sets the line width at which objects and arrays are broken across
+// lines. The default is 80, as in mongosh.
+func WithWidth(n int) Option {
+	return func(c *config) {
+		c.width = n
+	}
+}
+
+// WithDepth sets how many levels of nested documents and arrays are printed
+// before they are abbreviated as [Object] or [Array]. The default is 6, as in mongosh.
+func WithDepth(n int) Option {
+	return func(c *config) {
+		c.depth = n
+	}
+}
+
+// Render formats a result as mongosh would print it.
+//
+// Documents use mongosh's shell syntax, such as ObjectId('...'), ISODate('...'),
+// Long('...') and Decimal128('...'). Write results are shown in mongosh's
+// { acknowledged: true, ... } form, and show commands use mongosh's plain-text
+// listings. A find() or aggregate() that returns no documents renders as an
+// empty string, as mongosh prints nothing for an empty cursor.
+func Render(result *gomongo.Result, opts ...Option) string {
+	cfg := &config{width: defaultWidth, depth: defaultDepth}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	switch result.Operation {
+	// Cursors print their documents as an array, or nothing when empty.
+	case types.OpFind, types.OpAggregate:
+		if len(result.Value) == 0 {
+			return ""
+		}
+		return inspect(cfg, result.Value)
+
+	// Show commands print plain-text listings.
+	case types.OpShowDatabases:
+		return formatDatabases(result.Value)
+	case types.OpShowCollections, types.OpShowLog, types.OpShowLogs:
+		return formatLines(result.Value)
+
+	// Single documents print as null when there is no match.
+	case types.OpFindOne, types.OpFindOneAndUpdate, types.OpFindOneAndReplace, types.OpFindOneAndDelete,
+		types.OpGetUser, types.OpGetRole:
+		if len(result.Value) == 0 {
+			return "null"
+		}
+		return inspect(cfg, result.Value[0])
+
+	// Write results use mongosh's result shapes.
+	case types.OpInsertOne, types.OpInsertMany, types.OpUpdateOne, types.OpUpdateMany, types.OpReplaceOne,
+		types.OpDeleteOne, types.OpDeleteMany:
+		if len(result.Value) == 0 {
+			return ""
+		}
+		return inspect(cfg, writeResult(result))
+
+	// Counts and sizes are plain JavaScript numbers in mongosh.
+	case types.OpCountDocuments, types.OpEstimatedDocumentCount, types.OpDataSize, types.OpStorageSize,
+		types.OpTotalIndexSize, types.OpTotalSize, types.OpGetProfilingLevel:
+		if len(result.Value) == 0 {
+			return ""
+		}
+		return formatCount(cfg, result.Value[0])
+
+	// Helpers that return a list print it as an array.
+	case types.OpDistinct, types.OpGetIndexes, types.OpGetCollectionInfos, types.OpGetCollectionNames,
+		types.OpCreateIndexes, types.OpLatencyStats, types.OpShowProfile, types.OpShowUsers, types.OpShowRoles,
+		types.OpGetUsers, types.OpGetRoles, types.OpRsPrintSecondaryReplicationInfo:
+		return inspect(cfg, result.Value)
+
+	default:
+		if len(result.Value) == 0 {
+			return ""
+		}
+		if len(result.Value) > 1 {
+			return inspect(cfg, result.Value)
+		}
+		// Top-level strings, such as the name returned by createIndex(), print unquoted.
+		if s, ok := result.Value[0].(string); ok {
+			return s
+		}
+		return inspect(cfg, result.Value[0])
+	}
+}
+
+// inspect formats a single value with the configured layout.
+func inspect(cfg *config, v any) string {
+	c := &inspector{breakLength: cfg.width, depth: cfg.depth}
+	return c.formatValue(v, 0)
+}
+
+// formatCount formats a count or size, which mongosh prints as a plain number.
+func formatCount(cfg *config, v any) string {
+	if n, ok := v.(int64); ok {
+		return strconv.FormatInt(n, 10)
+	}
+	return inspect(cfg, v)
+}
+
+// writeResult converts a write result to the shape mongosh prints, where counts
+// are plain numbers and update results always report insertedId and upsertedCount.
+func writeResult(result *gomongo.Result) bson.D {
+	out := bson.D{{Key: "acknowledged", Value: true}}
+	if r, ok := result.InsertOneResult(); ok {
+		out = append(out, bson.E{Key: "insertedId", Value: r.InsertedID})
+	}
+	if r, ok := result.InsertManyResult(); ok {
+		ids := bson.D{}
+		for i, id := range r.InsertedIDs {
+			ids = append(ids, bson.E{Key: strconv.Itoa(i), Value: id})
+		}
+		out = append(out, bson.E{Key: "insertedIds", Value: ids})
+	}
+	if r, ok := result.UpdateResult(); ok {
+		out = append(out,
+			bson.E{Key: "insertedId", Value: r.UpsertedID},
+			bson.E{Key: "matchedCount", Value: float64(r.MatchedCount)},
+			bson.E{Key: "modifiedCount", Value: float64(r.ModifiedCount)},
+			bson.E{Key: "upsertedCount", Value: int32(r.UpsertedCount)},
+		)
+	}
+	if r, ok := result.DeleteResult(); ok {
+		out = append(out, bson.E{Key: "deletedCount", Value: float64(r.DeletedCount)})
+	}
+	return out
+}
+
+// formatDatabases formats show dbs output as a two-column table of names and sizes.
+func formatDatabases(values []any) string {
+	names := make([]string, len(values))
+	sizes := make([]string, len(values))
+	nameWidth, sizeWidth := 0, 0
+	for i, v := range values {
+		doc, _ := v.(bson.D)
+		names[i], _ = lookup(doc, "name").(string)
+		size, _ := translator.ToInt64(lookup(doc, "sizeOnDisk"))
+		sizes[i] = formatBytes(size)
+		nameWidth = max(nameWidth, jsLength(names[i]))
+		sizeWidth = max(sizeWidth, jsLength(sizes[i]))
+	}
+
+	lines := make([]string, len(values))
+	for i := range values {
+		lines[i] = pad(names[i], nameWidth, false) + "  " + pad(sizes[i], sizeWidth, true)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatBytes formats a byte count with binary units and two decimals.
+func formatBytes(n int64) string {
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB"}
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	size := float64(n)
+	unit := ""
+	for _, u := range units {
+		size /= 1024
+		unit = u
+		if size < 1024 {
+			break
+		}
+	}
+	return fmt.Sprintf("%.2f %s", size, unit)
+}
+
+// formatLines prints each value on its own line.
+func formatLines(values []any) string {
+	lines := make([]string, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			lines[i] = s
+		} else {
+			lines[i] = fmt.Sprintf("%v", v)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lookup finds a field value in a bson.D by key.
+func lookup(doc bson.D, key string) any {
+	for _, elem := range doc {
+		if elem.Key == key {
+			return elem.Value
+		}
+	}
+	return nil
+}
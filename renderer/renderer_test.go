@@ -0,0 +1,238 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func mustObjectID(t *testing.T) bson.ObjectID {
+	t.Helper()
+	oid, err := bson.ObjectIDFromHex("507f1f77bcf86cd799439011")
+	require.NoError(t, err)
+	return oid
+}
+
+func TestRenderValues(t *testing.T) {
+	oid := mustObjectID(t)
+	dec, err := bson.ParseDecimal128("1.50")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		value    any
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "simple document",
+			value:    bson.D{{Key: "a", Value: int32(1)}, {Key: "b", Value: "x"}},
+			expected: `{ a: 1, b: 'x' }`,
+		},
+		{
+			name: "shell types",
+			value: bson.D{
+				{Key: "_id", Value: oid},
+				{Key: "n", Value: int64(5)},
+				{Key: "at", Value: bson.NewDateTimeFromTime(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC))},
+				{Key: "price", Value: dec},
+			},
+			expected: "{\n" +
+				"  _id: ObjectId('507f1f77bcf86cd799439011'),\n" +
+				"  n: Long('5'),\n" +
+				"  at: ISODate('2024-01-02T10:00:00.000Z'),\n" +
+				"  price: Decimal128('1.50')\n" +
+				"}",
+		},
+		{
+			name: "binary, timestamp and regex",
+			value: bson.D{
+				{Key: "u", Value: bson.Binary{Subtype: bson.TypeBinaryUUID, Data: []byte{0x12, 0x34, 0x56, 0x78, 0x12, 0x34, 0x12, 0x34, 0x12, 0x34, 0x12, 0x34, 0x56, 0x78, 0x90, 0xab}}},
+				{Key: "b", Value: bson.Binary{Subtype: 0, Data: []byte{1, 2, 3}}},
+				{Key: "ts", Value: bson.Timestamp{T: 1, I: 2}},
+				{Key: "re", Value: bson.Regex{Pattern: "^a/b", Options: "i"}},
+			},
+			expected: "{\n" +
+				"  u: UUID('12345678-1234-1234-1234-1234567890ab'),\n" +
+				"  b: Binary.createFromBase64('AQID', 0),\n" +
+				"  ts: Timestamp({ t: 1, i: 2 }),\n" +
+				"  re: /^a\\/b/i\n" +
+				"}",
+		},
+		{
+			name:     "numbers",
+			value:    bson.A{3.0, 1.5, math.Copysign(0, -1), 1e21, 1.5e-7, int32(-2)},
+			expected: `[ 3, 1.5, -0, 1e+21, 1.5e-7, -2 ]`,
+		},
+		{
+			name:     "quoted keys and strings",
+			value:    bson.D{{Key: "$gt", Value: "it's"}, {Key: "a-b", Value: "tab\there"}, {Key: "0", Value: `"'`}},
+			expected: "{ '$gt': \"it's\", 'a-b': 'tab\\there', '0': `\"'` }",
+		},
+		{
+			name:     "empty containers",
+			value:    bson.D{{Key: "d", Value: bson.D{}}, {Key: "a", Value: bson.A{}}, {Key: "n", Value: nil}},
+			expected: `{ d: {}, a: [], n: null }`,
+		},
+		{
+			name:     "depth",
+			value:    bson.D{{Key: "a", Value: bson.D{{Key: "b", Value: bson.A{int32(1)}}}}},
+			opts:     []Option{WithDepth(0)},
+			expected: `{ a: [Object] }`,
+		},
+		{
+			name: "innermost three levels are combined",
+			value: bson.D{{Key: "a", Value: bson.D{{Key: "b", Value: bson.D{{Key: "c", Value: bson.D{
+				{Key: "d", Value: int32(1)},
+			}}}}}}},
+			expected: "{\n  a: { b: { c: { d: 1 } } }\n}",
+		},
+		{
+			name:  "long arrays of numbers are grouped",
+			value: numbers(26),
+			expected: "[\n" +
+				"   0,  1,  2,  3,  4,  5,  6,  7,\n" +
+				"   8,  9, 10, 11, 12, 13, 14, 15,\n" +
+				"  16, 17, 18, 19, 20, 21, 22, 23,\n" +
+				"  24, 25\n" +
+				"]",
+		},
+		{
+			name:     "multi-line strings are split",
+			value:    bson.D{{Key: "s", Value: "aaaaaaaaaa\nbbbbbbbbbb"}},
+			opts:     []Option{WithWidth(20)},
+			expected: "{\n  s: 'aaaaaaaaaa\\n' +\n    'bbbbbbbbbb'\n}",
+		},
+		{
+			name:     "DBRef",
+			value:    bson.D{{Key: "owner", Value: bson.D{{Key: "$ref", Value: "users"}, {Key: "$id", Value: int32(7)}}}},
+			expected: `{ owner: DBRef('users', 7) }`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := &gomongo.Result{Operation: types.OpFindOne, Value: []any{tc.value}}
+			require.Equal(t, tc.expected, Render(result, tc.opts...))
+		})
+	}
+}
+
+func numbers(n int) bson.A {
+	values := make(bson.A, n)
+	for i := range values {
+		values[i] = int32(i)
+	}
+	return values
+}
+
+func TestRenderResults(t *testing.T) {
+	oid := mustObjectID(t)
+
+	tests := []struct {
+		name     string
+		result   gomongo.Result
+		expected string
+	}{
+		{
+			name: "find",
+			result: gomongo.Result{Operation: types.OpFind, Value: []any{
+				bson.D{{Key: "_id", Value: oid}, {Key: "name", Value: "alice"}, {Key: "age", Value: int32(30)}},
+			}},
+			expected: "[\n  { _id: ObjectId('507f1f77bcf86cd799439011'), name: 'alice', age: 30 }\n]",
+		},
+		{
+			name:     "empty find",
+			result:   gomongo.Result{Operation: types.OpFind, Value: []any{}},
+			expected: "",
+		},
+		{
+			name:     "findOne without match",
+			result:   gomongo.Result{Operation: types.OpFindOne, Value: []any{}},
+			expected: "null",
+		},
+		{
+			name:     "count",
+			result:   gomongo.Result{Operation: types.OpCountDocuments, Value: []any{int64(42)}},
+			expected: "42",
+		},
+		{
+			name:     "createIndex",
+			result:   gomongo.Result{Operation: types.OpCreateIndex, Value: []any{"name_1"}},
+			expected: "name_1",
+		},
+		{
+			name:     "drop",
+			result:   gomongo.Result{Operation: types.OpDrop, Value: []any{true}},
+			expected: "true",
+		},
+		{
+			name:     "distinct",
+			result:   gomongo.Result{Operation: types.OpDistinct, Value: []any{"a", "b"}},
+			expected: "[ 'a', 'b' ]",
+		},
+		{
+			name: "insertOne",
+			result: gomongo.Result{Operation: types.OpInsertOne, Value: []any{bson.D{
+				{Key: "acknowledged", Value: true},
+				{Key: "insertedId", Value: oid},
+			}}},
+			expected: "{\n  acknowledged: true,\n  insertedId: ObjectId('507f1f77bcf86cd799439011')\n}",
+		},
+		{
+			name: "insertMany",
+			result: gomongo.Result{Operation: types.OpInsertMany, Value: []any{bson.D{
+				{Key: "acknowledged", Value: true},
+				{Key: "insertedIds", Value: []any{int32(1), int32(2)}},
+			}}},
+			expected: "{ acknowledged: true, insertedIds: { '0': 1, '1': 2 } }",
+		},
+		{
+			name: "updateOne",
+			result: gomongo.Result{Operation: types.OpUpdateOne, Value: []any{bson.D{
+				{Key: "acknowledged", Value: true},
+				{Key: "matchedCount", Value: int64(1)},
+				{Key: "modifiedCount", Value: int64(1)},
+			}}},
+			expected: "{\n" +
+				"  acknowledged: true,\n" +
+				"  insertedId: null,\n" +
+				"  matchedCount: 1,\n" +
+				"  modifiedCount: 1,\n" +
+				"  upsertedCount: 0\n" +
+				"}",
+		},
+		{
+			name: "deleteMany",
+			result: gomongo.Result{Operation: types.OpDeleteMany, Value: []any{bson.D{
+				{Key: "acknowledged", Value: true},
+				{Key: "deletedCount", Value: int64(2)},
+			}}},
+			expected: "{ acknowledged: true, deletedCount: 2 }",
+		},
+		{
+			name: "show dbs",
+			result: gomongo.Result{Operation: types.OpShowDatabases, Value: []any{
+				bson.D{{Key: "name", Value: "admin"}, {Key: "sizeOnDisk", Value: int64(40960)}, {Key: "empty", Value: false}},
+				bson.D{{Key: "name", Value: "config"}, {Key: "sizeOnDisk", Value: int64(110592)}, {Key: "empty", Value: false}},
+			}},
+			expected: "admin    40.00 KiB\nconfig  108.00 KiB",
+		},
+		{
+			name:     "show collections",
+			result:   gomongo.Result{Operation: types.OpShowCollections, Value: []any{"orders", "users"}},
+			expected: "orders\nusers",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, Render(&tc.result))
+		})
+	}
+}
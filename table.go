@@ -0,0 +1,195 @@
+package gomongo
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Table is a tabular view of document results, with one row per document.
+type Table struct {
+	Columns []Column
+	Rows    [][]Cell // each row has one cell per column, in column order
+}
+
+// Column describes a table column.
+type Column struct {
+	Name  string      // field name, or dotted path for flattened nested fields
+	Types []bson.Type // BSON types seen in the column, in first-seen order
+}
+
+// Mixed reports whether the column holds values of more than one BSON type.
+func (c Column) Mixed() bool {
+	return len(c.Types) > 1
+}
+
+// Cell is a single table value.
+type Cell struct {
+	Value   any       // the field value; arrays and unflattened documents are kept whole
+	Type    bson.Type // BSON type of Value; zero when Missing
+	Missing bool      // the document does not have this field
+}
+
+// String returns a display form of the cell. Strings are returned as is, numbers,
+// booleans, ObjectIDs, dates and decimals in their plain text form, missing fields
+// as the empty string, and other values as relaxed Extended JSON.
+func (c Cell) String() string {
+	if c.Missing {
+		return ""
+	}
+	switch v := c.Value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bson.ObjectID:
+		return v.Hex()
+	case bson.DateTime:
+		return v.Time().UTC().Format(time.RFC3339Nano)
+	case bson.Decimal128:
+		return v.String()
+	}
+	data, err := marshalExtJSONValue(c.Value, false)
+	if err != nil {
+		return fmt.Sprintf("%v", c.Value)
+	}
+	return string(data)
+}
+
+// tableConfig holds configuration for Table.
+type tableConfig struct {
+	flattenDepth int
+}
+
+// TableOption configures Table behavior.
+type TableOption func(*tableConfig)
+
+// WithFlattenDepth flattens nested documents into dotted-path columns, such as
+// "address.city", up to n levels deep. Documents nested deeper than n are kept
+// whole in a single cell. Arrays are never flattened. The default of 0 keeps
+// every nested document in a single cell.
+func WithFlattenDepth(n int) TableOption {
+	return func(c *tableConfig) {
+		c.flattenDepth = n
+	}
+}
+
+// Table returns the result as rows and columns.
+//
+// Columns are the union of the fields of all documents, in first-seen order.
+// A row has a Missing cell for each field its document lacks. Table works for
+// every operation that returns documents, such as OpFind, OpAggregate,
+// OpGetIndexes, OpGetCollectionInfos and OpLatencyStats, and returns an error
+// for results that contain other values.
+func (r *Result) Table(opts ...TableOption) (*Table, error) {
+	cfg := &tableConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	table := &Table{}
+	index := make(map[string]int)
+	records := make([]map[int]any, len(r.Value))
+	for i, v := range r.Value {
+		doc, ok := v.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("table view requires document results: value %d is %T", i, v)
+		}
+		record := make(map[int]any)
+		flattenInto(record, table, index, "", doc, cfg.flattenDepth)
+		records[i] = record
+	}
+
+	table.Rows = make([][]Cell, len(records))
+	for i, record := range records {
+		row := make([]Cell, len(table.Columns))
+		for col := range row {
+			value, ok := record[col]
+			if !ok {
+				row[col] = Cell{Missing: true}
+				continue
+			}
+			row[col] = Cell{Value: value, Type: bsonTypeOf(value)}
+		}
+		table.Rows[i] = row
+	}
+
+	for _, row := range table.Rows {
+		for col, cell := range row {
+			if !cell.Missing {
+				table.Columns[col].addType(cell.Type)
+			}
+		}
+	}
+	return table, nil
+}
+
+// flattenInto records the fields of doc under their column indexes, adding columns
+// as new field paths are seen and expanding nested documents depth levels deep.
+func flattenInto(record map[int]any, table *Table, index map[string]int, prefix string, doc bson.D, depth int) {
+	for _, elem := range doc {
+		path := prefix + elem.Key
+		if nested, ok := elem.Value.(bson.D); ok && depth > 0 && len(nested) > 0 {
+			flattenInto(record, table, index, path+".", nested, depth-1)
+			continue
+		}
+		col, ok := index[path]
+		if !ok {
+			col = len(table.Columns)
+			index[path] = col
+			table.Columns = append(table.Columns, Column{Name: path})
+		}
+		record[col] = elem.Value
+	}
+}
+
+// addType records a BSON type seen in the column.
+func (c *Column) addType(t bson.Type) {
+	for _, seen := range c.Types {
+		if seen == t {
+			return
+		}
+	}
+	c.Types = append(c.Types, t)
+}
+
+// bsonTypeOf returns the BSON type a value is encoded as.
+func bsonTypeOf(v any) bson.Type {
+	switch v.(type) {
+	case nil:
+		return bson.TypeNull
+	case string:
+		return bson.TypeString
+	case int32:
+		return bson.TypeInt32
+	case int64:
+		return bson.TypeInt64
+	case float64:
+		return bson.TypeDouble
+	case bool:
+		return bson.TypeBoolean
+	case bson.D:
+		return bson.TypeEmbeddedDocument
+	case bson.A:
+		return bson.TypeArray
+	case bson.ObjectID:
+		return bson.TypeObjectID
+	case bson.DateTime:
+		return bson.TypeDateTime
+	}
+	t, _, err := bson.MarshalValue(v)
+	if err != nil {
+		return 0
+	}
+	return t
+}
@@ -0,0 +1,120 @@
+package gomongo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/bytebase/gomongo/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// columnNames returns the names of the table's columns.
+func columnNames(table *gomongo.Table) []string {
+	names := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+func TestResultTable(t *testing.T) {
+	oid, err := bson.ObjectIDFromHex("507f1f77bcf86cd799439011")
+	require.NoError(t, err)
+
+	result := &gomongo.Result{Operation: types.OpFind, Value: []any{
+		bson.D{
+			{Key: "_id", Value: oid},
+			{Key: "name", Value: "alice"},
+			{Key: "address", Value: bson.D{{Key: "city", Value: "Paris"}, {Key: "geo", Value: bson.D{{Key: "lat", Value: 48.8}}}}},
+		},
+		bson.D{
+			{Key: "_id", Value: int32(2)},
+			{Key: "tags", Value: bson.A{"a", "b"}},
+			{Key: "name", Value: "bob"},
+		},
+	}}
+
+	table, err := result.Table()
+	require.NoError(t, err)
+	require.Equal(t, []string{"_id", "name", "address", "tags"}, columnNames(table))
+	require.Equal(t, 2, len(table.Rows))
+
+	// Types are reported per column, including mixes.
+	require.Equal(t, []bson.Type{bson.TypeObjectID, bson.TypeInt32}, table.Columns[0].Types)
+	require.True(t, table.Columns[0].Mixed())
+	require.False(t, table.Columns[1].Mixed())
+	require.Equal(t, []bson.Type{bson.TypeEmbeddedDocument}, table.Columns[2].Types)
+
+	// Missing fields are marked, and arrays keep their type.
+	require.True(t, table.Rows[0][3].Missing)
+	require.Equal(t, "", table.Rows[0][3].String())
+	require.Equal(t, bson.TypeArray, table.Rows[1][3].Type)
+	require.Equal(t, `["a","b"]`, table.Rows[1][3].String())
+	require.Equal(t, "507f1f77bcf86cd799439011", table.Rows[0][0].String())
+	require.Equal(t, `{"city":"Paris","geo":{"lat":48.8}}`, table.Rows[0][2].String())
+
+	// Flattening expands nested documents into dotted paths up to the depth.
+	table, err = result.Table(gomongo.WithFlattenDepth(1))
+	require.NoError(t, err)
+	require.Equal(t, []string{"_id", "name", "address.city", "address.geo", "tags"}, columnNames(table))
+	require.Equal(t, "Paris", table.Rows[0][2].String())
+	require.Equal(t, bson.TypeEmbeddedDocument, table.Rows[0][3].Type)
+
+	table, err = result.Table(gomongo.WithFlattenDepth(2))
+	require.NoError(t, err)
+	require.Equal(t, []string{"_id", "name", "address.city", "address.geo.lat", "tags"}, columnNames(table))
+	require.Equal(t, bson.TypeDouble, table.Rows[0][3].Type)
+	require.True(t, table.Rows[1][3].Missing)
+}
+
+func TestResultTableRejectsNonDocuments(t *testing.T) {
+	result := &gomongo.Result{Operation: types.OpCountDocuments, Value: []any{int64(3)}}
+	_, err := result.Table()
+	require.ErrorContains(t, err, "table view requires document results")
+
+	empty := &gomongo.Result{Operation: types.OpFind, Value: []any{}}
+	table, err := empty.Table()
+	require.NoError(t, err)
+	require.Equal(t, 0, len(table.Columns))
+	require.Equal(t, 0, len(table.Rows))
+}
+
+func TestResultTableDocumentOperations(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_table_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		_, err := gc.Execute(ctx, dbName, `db.users.insertMany([{ name: "alice", age: 30 }, { name: "bob", city: "Paris" }])`)
+		require.NoError(t, err)
+		_, err = gc.Execute(ctx, dbName, `db.users.createIndex({ name: 1 })`)
+		require.NoError(t, err)
+
+		for _, statement := range []string{
+			`db.users.find()`,
+			`db.users.aggregate([{ $project: { name: 1 } }])`,
+			`db.users.getIndexes()`,
+			`db.getCollectionInfos()`,
+			`db.users.latencyStats()`,
+		} {
+			result, err := gc.Execute(ctx, dbName, statement)
+			require.NoError(t, err, statement)
+			table, err := result.Table(gomongo.WithFlattenDepth(1))
+			require.NoError(t, err, statement)
+			require.Equal(t, len(result.Value), len(table.Rows), statement)
+			require.NotEmpty(t, table.Columns, statement)
+		}
+
+		result, err := gc.Execute(ctx, dbName, `db.users.find({}, { _id: 0 })`)
+		require.NoError(t, err)
+		table, err := result.Table()
+		require.NoError(t, err)
+		require.Equal(t, []string{"name", "age", "city"}, columnNames(table))
+	})
+}
@@ -53,4 +53,38 @@ const (
 	OpIsCapped
 	OpValidate
 	OpLatencyStats
+	// Operations Console
+	OpCurrentOp
+	OpKillOp
+	// Profiler
+	OpSetProfilingLevel
+	OpGetProfilingLevel
+	OpGetProfilingStatus
+	OpShowProfile
+	// Show Commands
+	OpShowUsers
+	OpShowRoles
+	OpShowLog
+	OpShowLogs
+	// User and Role Introspection
+	OpGetUsers
+	OpGetUser
+	OpGetRoles
+	OpGetRole
+	// User and Role Administration
+	OpCreateUser
+	OpUpdateUser
+	OpDropUser
+	OpGrantRolesToUser
+	OpRevokeRolesFromUser
+	OpCreateRole
+	OpGrantPrivilegesToRole
+	OpChangeUserPassword
+	// Replication and Sharding Status
+	OpRsStatus
+	OpRsConf
+	OpRsPrintReplicationInfo
+	OpRsPrintSecondaryReplicationInfo
+	OpShStatus
+	OpGetShardDistribution
 )
@@ -0,0 +1,11 @@
+package types
+
+// WarningCode identifies why part of a statement was not executed as written.
+type WarningCode string
+
+const (
+	// WarningIgnoredOption reports an option that was accepted but has no effect.
+	WarningIgnoredOption WarningCode = "IgnoredOption"
+	// WarningIgnoredMethod reports a method call that was accepted but has no effect.
+	WarningIgnoredMethod WarningCode = "IgnoredMethod"
+)
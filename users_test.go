@@ -0,0 +1,293 @@
+package gomongo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// createTestUser creates a user with the read role and returns a cleanup function.
+func createTestUser(t *testing.T, db testutil.TestDB, dbName, user string) func() {
+	t.Helper()
+	ctx := context.Background()
+	err := db.Client.Database(dbName).RunCommand(ctx, bson.D{
+		{Key: "createUser", Value: user},
+		{Key: "pwd", Value: "secret-password"},
+		{Key: "roles", Value: bson.A{"read"}},
+		{Key: "customData", Value: bson.D{{Key: "team", Value: "analytics"}}},
+	}).Err()
+	require.NoError(t, err)
+	return func() {
+		_ = db.Client.Database(dbName).RunCommand(ctx, bson.D{{Key: "dropUser", Value: user}}).Err()
+	}
+}
+
+func TestGetUsers(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_get_users_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+		defer createTestUser(t, db, dbName, "alice")()
+		defer createTestUser(t, db, dbName, "bob")()
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		result, err := gc.Execute(ctx, dbName, `db.getUsers()`)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(result.Value))
+		for _, row := range valuesToStrings(result.Value) {
+			require.NotContains(t, row, `"credentials"`)
+			require.NotContains(t, row, "secret-password")
+		}
+
+		result, err = gc.Execute(ctx, dbName, `db.getUsers({ filter: { user: "alice" }, showPrivileges: true })`)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+		row := valueToJSON(result.Value[0])
+		require.Contains(t, row, `"user": "alice"`)
+		require.Contains(t, row, `"inheritedPrivileges"`)
+	})
+}
+
+func TestGetUser(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_get_user_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+		defer createTestUser(t, db, dbName, "alice")()
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		result, err := gc.Execute(ctx, dbName, `db.getUser("alice")`)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+		row := valueToJSON(result.Value[0])
+		require.Contains(t, row, `"user": "alice"`)
+		require.Contains(t, row, `"team": "analytics"`)
+		require.NotContains(t, row, `"inheritedPrivileges"`)
+
+		result, err = gc.Execute(ctx, dbName, `db.getUser("alice", { showPrivileges: true })`)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+		require.Contains(t, valueToJSON(result.Value[0]), `"inheritedPrivileges"`)
+
+		// Missing user returns an empty result, like findOne
+		result, err = gc.Execute(ctx, dbName, `db.getUser("nobody")`)
+		require.NoError(t, err)
+		require.Empty(t, result.Value)
+	})
+}
+
+func TestGetRoles(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_get_roles_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		// No user-defined roles in a fresh database
+		result, err := gc.Execute(ctx, dbName, `db.getRoles()`)
+		require.NoError(t, err)
+		require.Empty(t, result.Value)
+
+		result, err = gc.Execute(ctx, dbName, `db.getRoles({ showBuiltinRoles: true, showPrivileges: true })`)
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Value)
+		require.Contains(t, valueToJSON(result.Value[0]), `"privileges"`)
+	})
+}
+
+func TestGetRole(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_get_role_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		result, err := gc.Execute(ctx, dbName, `db.getRole("read", { showPrivileges: true })`)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+		row := valueToJSON(result.Value[0])
+		require.Contains(t, row, `"role": "read"`)
+		require.Contains(t, row, `"isBuiltin": true`)
+		require.Contains(t, row, `"privileges"`)
+
+		result, err = gc.Execute(ctx, dbName, `db.getRole("nosuchrole")`)
+		require.NoError(t, err)
+		require.Empty(t, result.Value)
+	})
+}
+
+func TestUserIntrospectionErrors(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_user_introspect_err_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		tests := []struct {
+			name      string
+			statement string
+			errMsg    string
+		}{
+			{"getUser missing name", `db.getUser()`, "getUser() requires a user name"},
+			{"getUser non-string name", `db.getUser(1)`, "getUser() user name must be a string"},
+			{"getRole missing name", `db.getRole()`, "getRole() requires a role name"},
+			{"getRoles non-boolean option", `db.getRoles({ showPrivileges: 1 })`, "getRoles() showPrivileges must be a boolean"},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := gc.Execute(ctx, dbName, tc.statement)
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+
+		// Credentials are never returned
+		_, err := gc.Execute(ctx, dbName, `db.getUsers({ showCredentials: true })`)
+		var optErr *gomongo.UnsupportedOptionError
+		require.ErrorAs(t, err, &optErr)
+		require.Equal(t, "showCredentials", optErr.Option)
+	})
+}
+
+func TestSecurityAdminRequired(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_security_admin_req_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client)
+		ctx := context.Background()
+
+		tests := []struct {
+			statement string
+			operation string
+		}{
+			{`db.createUser({ user: "carol", pwd: "secret-password", roles: [] })`, "createUser()"},
+			{`db.updateUser("carol", { customData: {} })`, "updateUser()"},
+			{`db.dropUser("carol")`, "dropUser()"},
+			{`db.grantRolesToUser("carol", ["read"])`, "grantRolesToUser()"},
+			{`db.revokeRolesFromUser("carol", ["read"])`, "revokeRolesFromUser()"},
+			{`db.createRole({ role: "auditor", privileges: [], roles: [] })`, "createRole()"},
+			{`db.grantPrivilegesToRole("auditor", [])`, "grantPrivilegesToRole()"},
+			{`db.changeUserPassword("carol", "secret-password")`, "changeUserPassword()"},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.operation, func(t *testing.T) {
+				_, err := gc.Execute(ctx, dbName, tc.statement)
+				var adminErr *gomongo.SecurityAdminRequiredError
+				require.ErrorAs(t, err, &adminErr)
+				require.Equal(t, tc.operation, adminErr.Operation)
+				require.NotContains(t, err.Error(), "secret-password")
+			})
+		}
+
+		// Nothing was created
+		result, err := gc.Execute(ctx, dbName, `db.getUsers()`)
+		require.NoError(t, err)
+		require.Empty(t, result.Value)
+	})
+}
+
+func TestUserAdministration(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_user_admin_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client, gomongo.WithSecurityAdmin())
+		ctx := context.Background()
+
+		result, err := gc.Execute(ctx, dbName, `db.createUser({ user: "carol", pwd: "secret-password", roles: ["read"] }, { w: 1 })`)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+		require.Equal(t, bson.D{{Key: "ok", Value: int32(1)}}, result.Value[0])
+		defer func() { _, _ = gc.Execute(ctx, dbName, `db.dropUser("carol")`) }()
+
+		_, err = gc.Execute(ctx, dbName, `db.grantRolesToUser("carol", ["readWrite"])`)
+		require.NoError(t, err)
+		_, err = gc.Execute(ctx, dbName, `db.revokeRolesFromUser("carol", ["read"])`)
+		require.NoError(t, err)
+		_, err = gc.Execute(ctx, dbName, `db.updateUser("carol", { customData: { team: "ops" } })`)
+		require.NoError(t, err)
+		_, err = gc.Execute(ctx, dbName, `db.changeUserPassword("carol", "another-secret")`)
+		require.NoError(t, err)
+
+		result, err = gc.Execute(ctx, dbName, `db.getUser("carol")`)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+		row := valueToJSON(result.Value[0])
+		require.Contains(t, row, `"role": "readWrite"`)
+		require.NotContains(t, row, `"role": "read"`)
+		require.Contains(t, row, `"team": "ops"`)
+		require.NotContains(t, row, "secret")
+
+		_, err = gc.Execute(ctx, dbName, `db.dropUser("carol")`)
+		require.NoError(t, err)
+
+		result, err = gc.Execute(ctx, dbName, `db.getUser("carol")`)
+		require.NoError(t, err)
+		require.Empty(t, result.Value)
+	})
+}
+
+func TestRoleAdministration(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_role_admin_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client, gomongo.WithSecurityAdmin())
+		ctx := context.Background()
+
+		_, err := gc.Execute(ctx, dbName, `db.createRole({ role: "auditor", privileges: [], roles: [] })`)
+		require.NoError(t, err)
+		defer func() {
+			_ = db.Client.Database(dbName).RunCommand(ctx, bson.D{{Key: "dropRole", Value: "auditor"}}).Err()
+		}()
+
+		statement := fmt.Sprintf(`db.grantPrivilegesToRole("auditor", [{ resource: { db: "%s", collection: "" }, actions: ["find"] }])`, dbName)
+		_, err = gc.Execute(ctx, dbName, statement)
+		require.NoError(t, err)
+
+		result, err := gc.Execute(ctx, dbName, `db.getRole("auditor", { showPrivileges: true })`)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(result.Value))
+		require.Contains(t, valueToJSON(result.Value[0]), `"find"`)
+	})
+}
+
+func TestUserAdministrationErrorsDoNotEchoPassword(t *testing.T) {
+	testutil.RunOnMongoDBOnly(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_user_admin_err_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		gc := gomongo.NewClient(db.Client, gomongo.WithSecurityAdmin())
+		ctx := context.Background()
+
+		tests := []struct {
+			name      string
+			statement string
+		}{
+			{"syntax error", `db.changeUserPassword("carol" "secret-password")`},
+			{"missing user field", `db.createUser({ pwd: "secret-password", roles: [] })`},
+			{"unknown user", `db.changeUserPassword("nobody", "secret-password")`},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := gc.Execute(ctx, dbName, tc.statement)
+				require.Error(t, err)
+				require.NotContains(t, err.Error(), "secret-password")
+			})
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package gomongo
+
+import (
+	"strings"
+
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/bytebase/gomongo/types"
+)
+
+// Warning reports part of a statement that was ignored, rewritten or
+// approximated, so the statement did not run exactly as written.
+type Warning struct {
+	Code    types.WarningCode
+	Message string
+	Line    int // 1-based line in the statement
+	Column  int // 1-based column in bytes
+}
+
+// convertWarnings converts internal warnings to public warnings, resolving
+// their byte offsets to positions in the statement.
+func convertWarnings(statement string, warnings []translator.Warning) []Warning {
+	if len(warnings) == 0 {
+		return nil
+	}
+	result := make([]Warning, len(warnings))
+	for i, w := range warnings {
+		result[i] = Warning{Code: w.Code, Message: w.Message}
+		result[i].Line, result[i].Column = lineColumn(statement, w.Offset)
+	}
+	return result
+}
+
+// lineColumn returns the 1-based line and byte column of an offset in the
+// statement, or zeros if the offset is outside it.
+func lineColumn(statement string, offset int) (line, column int) {
+	if offset < 0 || offset > len(statement) {
+		return 0, 0
+	}
+	before := statement[:offset]
+	return strings.Count(before, "\n") + 1, offset - (strings.LastIndex(before, "\n") + 1) + 1
+}
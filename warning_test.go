@@ -0,0 +1,113 @@
+package gomongo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/bytebase/gomongo/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestWarnings(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_warnings_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		tests := []struct {
+			name      string
+			statement string
+			warnings  []gomongo.Warning
+		}{
+			{
+				name:      "no warnings",
+				statement: `db.items.find().sort({ n: 1 }).limit(5)`,
+			},
+			{
+				name:      "pretty",
+				statement: `db.items.find().pretty()`,
+				warnings: []gomongo.Warning{{
+					Code:    types.WarningIgnoredMethod,
+					Message: "pretty() only formats mongosh output and was ignored",
+					Line:    1,
+					Column:  17,
+				}},
+			},
+			{
+				name:      "cursor method on aggregate",
+				statement: "db.items.aggregate([])\n  .sort({ n: 1 })",
+				warnings: []gomongo.Warning{{
+					Code:    types.WarningIgnoredMethod,
+					Message: "sort() has no effect on aggregate() and was ignored",
+					Line:    2,
+					Column:  4,
+				}},
+			},
+			{
+				name:      "createIndex background",
+				statement: `db.items.createIndex({ n: 1 }, { background: true })`,
+				warnings: []gomongo.Warning{{
+					Code:    types.WarningIgnoredOption,
+					Message: "createIndex() background is deprecated and was ignored",
+					Line:    1,
+					Column:  34,
+				}},
+			},
+			{
+				name:      "createIndexes unsupported option",
+				statement: `db.items.createIndexes([{ key: { m: 1 }, background: true }])`,
+				warnings: []gomongo.Warning{{
+					Code:    types.WarningIgnoredOption,
+					Message: "createIndexes() option background of index 0 is not supported and was ignored",
+					Line:    1,
+					Column:  42,
+				}},
+			},
+			{
+				name:      "write concern wtimeout",
+				statement: `db.items.insertOne({ n: 1 }, { writeConcern: { w: 1, wtimeout: 1000 } })`,
+				warnings: []gomongo.Warning{{
+					Code:    types.WarningIgnoredOption,
+					Message: "writeConcern wtimeout is not supported and was ignored",
+					Line:    1,
+					Column:  54,
+				}},
+			},
+			{
+				name:      "collation field of the wrong type",
+				statement: `db.items.updateMany({}, { $set: { m: 1 } }, { collation: { locale: "en", strength: "2" } })`,
+				warnings: []gomongo.Warning{{
+					Code:    types.WarningIgnoredOption,
+					Message: "collation strength is not supported or has the wrong type and was ignored",
+					Line:    1,
+					Column:  74,
+				}},
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				result, err := gc.Execute(ctx, dbName, tc.statement)
+				require.NoError(t, err)
+				require.Equal(t, tc.warnings, result.Warnings)
+			})
+		}
+
+		// Options bound to a placeholder are reported at the placeholder.
+		result, err := gc.Execute(ctx, dbName, `db.items.insertOne({ n: 1 }, $1)`,
+			gomongo.WithParams(bson.D{{Key: "writeConcern", Value: bson.D{{Key: "wtimeout", Value: 1000}}}}))
+		require.NoError(t, err)
+		require.Equal(t, []gomongo.Warning{{
+			Code:    types.WarningIgnoredOption,
+			Message: "writeConcern wtimeout is not supported and was ignored",
+			Line:    1,
+			Column:  30,
+		}}, result.Warnings)
+	})
+}
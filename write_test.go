@@ -628,3 +628,69 @@ func TestFindOneAndDeleteWithSort(t *testing.T) {
 		require.Contains(t, verifyRow, `"score": 20`)
 	})
 }
+
+func TestInsertManyPartialSuccess(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_insert_many_partial_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		_, err := gc.Execute(ctx, dbName, `db.users.insertOne({ _id: 1 })`)
+		require.NoError(t, err)
+
+		// Unordered inserts skip the rejected documents and report the rest.
+		result, err := gc.Execute(ctx, dbName, `db.users.insertMany([{ _id: 2 }, { _id: 1 }, { _id: 3 }, { _id: 2 }], { ordered: false })`)
+		var serverErr *gomongo.ServerError
+		require.ErrorAs(t, err, &serverErr)
+		require.Equal(t, 2, len(serverErr.WriteErrors))
+		require.Equal(t, 1, serverErr.WriteErrors[0].Index)
+		require.Equal(t, 3, serverErr.WriteErrors[1].Index)
+		require.NotNil(t, result)
+		inserted, ok := result.InsertManyResult()
+		require.True(t, ok)
+		require.Equal(t, []any{int32(2), int32(3)}, inserted.InsertedIDs)
+
+		// Ordered inserts stop at the first rejected document.
+		result, err = gc.Execute(ctx, dbName, `db.users.insertMany([{ _id: 4 }, { _id: 1 }, { _id: 5 }])`)
+		require.ErrorAs(t, err, &serverErr)
+		require.Equal(t, 1, len(serverErr.WriteErrors))
+		inserted, ok = result.InsertManyResult()
+		require.True(t, ok)
+		require.Equal(t, []any{int32(4)}, inserted.InsertedIDs)
+
+		count, err := gc.Execute(ctx, dbName, `db.users.countDocuments()`)
+		require.NoError(t, err)
+		require.Equal(t, int64(4), count.Value[0])
+	})
+}
+
+func TestWriteConcernErrorReturnsResult(t *testing.T) {
+	client := testutil.GetReplicaSetClient(t)
+	dbName := "testdb_write_concern_error"
+	defer testutil.CleanupDatabase(t, client, dbName)
+
+	ctx := context.Background()
+	gc := gomongo.NewClient(client)
+
+	_, err := gc.Execute(ctx, dbName, `db.users.insertMany([{ _id: 1, n: 1 }, { _id: 2, n: 1 }])`)
+	require.NoError(t, err)
+
+	// A single-member replica set cannot satisfy w: 5, but the write is still applied.
+	result, err := gc.Execute(ctx, dbName, `db.users.updateMany({}, { $inc: { n: 1 } }, { writeConcern: { w: 5 } })`)
+	var serverErr *gomongo.ServerError
+	require.ErrorAs(t, err, &serverErr)
+	require.NotNil(t, serverErr.WriteConcernError)
+	require.Empty(t, serverErr.WriteErrors)
+	update, ok := result.UpdateResult()
+	require.True(t, ok)
+	require.Equal(t, int64(2), update.ModifiedCount)
+
+	result, err = gc.Execute(ctx, dbName, `db.users.deleteMany({ n: 2 }, { writeConcern: { w: 5 } })`)
+	require.ErrorAs(t, err, &serverErr)
+	require.NotNil(t, serverErr.WriteConcernError)
+	del, ok := result.DeleteResult()
+	require.True(t, ok)
+	require.Equal(t, int64(2), del.DeletedCount)
+}
@@ -0,0 +1,132 @@
+package gomongo
+
+import (
+	"github.com/bytebase/gomongo/internal/translator"
+	"github.com/bytebase/gomongo/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// InsertOneResult is the outcome of an insertOne() statement.
+type InsertOneResult struct {
+	Acknowledged bool
+	InsertedID   any // _id of the inserted document
+}
+
+// InsertManyResult is the outcome of an insertMany() statement.
+type InsertManyResult struct {
+	Acknowledged bool
+	InsertedIDs  []any // _id of each inserted document, in input order
+}
+
+// UpdateResult is the outcome of an updateOne(), updateMany() or replaceOne() statement.
+type UpdateResult struct {
+	Acknowledged  bool
+	MatchedCount  int64
+	ModifiedCount int64
+	UpsertedCount int64
+	UpsertedID    any // _id of the upserted document, or nil if no document was upserted
+}
+
+// DeleteResult is the outcome of a deleteOne() or deleteMany() statement.
+type DeleteResult struct {
+	Acknowledged bool
+	DeletedCount int64
+}
+
+// InsertOneResult returns the typed result of an insertOne() statement. It
+// reports false if the result is from another operation.
+func (r *Result) InsertOneResult() (*InsertOneResult, bool) {
+	doc, ok := r.writeResultDocument(types.OpInsertOne)
+	if !ok {
+		return nil, false
+	}
+	return &InsertOneResult{
+		Acknowledged: acknowledged(doc),
+		InsertedID:   lookupField(doc, "insertedId"),
+	}, true
+}
+
+// InsertManyResult returns the typed result of an insertMany() statement. It
+// reports false if the result is from another operation.
+func (r *Result) InsertManyResult() (*InsertManyResult, bool) {
+	doc, ok := r.writeResultDocument(types.OpInsertMany)
+	if !ok {
+		return nil, false
+	}
+	ids, _ := lookupField(doc, "insertedIds").([]any)
+	return &InsertManyResult{
+		Acknowledged: acknowledged(doc),
+		InsertedIDs:  ids,
+	}, true
+}
+
+// UpdateResult returns the typed result of an updateOne(), updateMany() or
+// replaceOne() statement. It reports false if the result is from another operation.
+func (r *Result) UpdateResult() (*UpdateResult, bool) {
+	doc, ok := r.writeResultDocument(types.OpUpdateOne, types.OpUpdateMany, types.OpReplaceOne)
+	if !ok {
+		return nil, false
+	}
+	result := &UpdateResult{
+		Acknowledged:  acknowledged(doc),
+		MatchedCount:  countField(doc, "matchedCount"),
+		ModifiedCount: countField(doc, "modifiedCount"),
+		UpsertedID:    lookupField(doc, "upsertedId"),
+	}
+	if result.UpsertedID != nil {
+		result.UpsertedCount = 1
+	}
+	return result, true
+}
+
+// DeleteResult returns the typed result of a deleteOne() or deleteMany()
+// statement. It reports false if the result is from another operation.
+func (r *Result) DeleteResult() (*DeleteResult, bool) {
+	doc, ok := r.writeResultDocument(types.OpDeleteOne, types.OpDeleteMany)
+	if !ok {
+		return nil, false
+	}
+	return &DeleteResult{
+		Acknowledged: acknowledged(doc),
+		DeletedCount: countField(doc, "deletedCount"),
+	}, true
+}
+
+// writeResultDocument returns the single document of a write result, if the
+// result is from one of the given operations. A nil result, as returned with
+// errors that leave nothing written, has no document.
+func (r *Result) writeResultDocument(ops ...types.OperationType) (bson.D, bool) {
+	if r == nil {
+		return nil, false
+	}
+	for _, op := range ops {
+		if r.Operation != op || len(r.Value) != 1 {
+			continue
+		}
+		doc, ok := r.Value[0].(bson.D)
+		return doc, ok
+	}
+	return nil, false
+}
+
+// acknowledged reports whether a write result document is acknowledged.
+func acknowledged(doc bson.D) bool {
+	ack, _ := lookupField(doc, "acknowledged").(bool)
+	return ack
+}
+
+// countField returns an integer field of a write result document, or 0 if absent.
+func countField(doc bson.D, key string) int64 {
+	n, _ := translator.ToInt64(lookupField(doc, key))
+	return n
+}
+
+// lookupField finds a field value in a bson.D by key.
+func lookupField(doc bson.D, key string) any {
+	for _, elem := range doc {
+		if elem.Key == key {
+			return elem.Value
+		}
+	}
+	return nil
+}
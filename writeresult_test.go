@@ -0,0 +1,88 @@
+package gomongo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bytebase/gomongo"
+	"github.com/bytebase/gomongo/internal/testutil"
+	"github.com/bytebase/gomongo/types"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestWriteResultAccessors(t *testing.T) {
+	update := &gomongo.Result{Operation: types.OpReplaceOne, Value: []any{bson.D{
+		{Key: "acknowledged", Value: true},
+		{Key: "matchedCount", Value: int64(0)},
+		{Key: "modifiedCount", Value: int64(0)},
+		{Key: "upsertedId", Value: int32(7)},
+	}}}
+	r, ok := update.UpdateResult()
+	require.True(t, ok)
+	require.Equal(t, &gomongo.UpdateResult{Acknowledged: true, UpsertedCount: 1, UpsertedID: int32(7)}, r)
+
+	// Accessors for other operations report false.
+	_, ok = update.DeleteResult()
+	require.False(t, ok)
+	_, ok = update.InsertOneResult()
+	require.False(t, ok)
+
+	find := &gomongo.Result{Operation: types.OpFind, Value: []any{bson.D{{Key: "matchedCount", Value: int64(1)}}}}
+	_, ok = find.UpdateResult()
+	require.False(t, ok)
+
+	// A nil result, as returned with most errors, has no write result.
+	var none *gomongo.Result
+	_, ok = none.InsertManyResult()
+	require.False(t, ok)
+}
+
+func TestWriteResultTypes(t *testing.T) {
+	testutil.RunOnAllDBs(t, func(t *testing.T, db testutil.TestDB) {
+		dbName := fmt.Sprintf("testdb_write_result_%s", db.Name)
+		defer testutil.CleanupDatabase(t, db.Client, dbName)
+
+		ctx := context.Background()
+		gc := gomongo.NewClient(db.Client)
+
+		result, err := gc.Execute(ctx, dbName, `db.users.insertOne({ _id: 1, name: "alice" })`)
+		require.NoError(t, err)
+		insertOne, ok := result.InsertOneResult()
+		require.True(t, ok)
+		require.Equal(t, &gomongo.InsertOneResult{Acknowledged: true, InsertedID: int32(1)}, insertOne)
+
+		result, err = gc.Execute(ctx, dbName, `db.users.insertMany([{ _id: 3, name: "carol" }, { _id: 2, name: "bob" }])`)
+		require.NoError(t, err)
+		insertMany, ok := result.InsertManyResult()
+		require.True(t, ok)
+		require.Equal(t, []any{int32(3), int32(2)}, insertMany.InsertedIDs)
+
+		result, err = gc.Execute(ctx, dbName, `db.users.updateMany({}, { $set: { active: true } })`)
+		require.NoError(t, err)
+		update, ok := result.UpdateResult()
+		require.True(t, ok)
+		require.Equal(t, &gomongo.UpdateResult{Acknowledged: true, MatchedCount: 3, ModifiedCount: 3}, update)
+
+		result, err = gc.Execute(ctx, dbName, `db.users.updateOne({ _id: 4 }, { $set: { name: "dave" } }, { upsert: true })`)
+		require.NoError(t, err)
+		update, ok = result.UpdateResult()
+		require.True(t, ok)
+		require.Equal(t, int64(1), update.UpsertedCount)
+		require.Equal(t, int32(4), update.UpsertedID)
+
+		result, err = gc.Execute(ctx, dbName, `db.users.replaceOne({ _id: 1 }, { name: "Alice" })`)
+		require.NoError(t, err)
+		update, ok = result.UpdateResult()
+		require.True(t, ok)
+		require.Equal(t, int64(1), update.ModifiedCount)
+		require.Nil(t, update.UpsertedID)
+
+		result, err = gc.Execute(ctx, dbName, `db.users.deleteMany({ active: true })`)
+		require.NoError(t, err)
+		del, ok := result.DeleteResult()
+		require.True(t, ok)
+		require.Equal(t, &gomongo.DeleteResult{Acknowledged: true, DeletedCount: 3}, del)
+	})
+}